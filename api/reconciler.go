@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -15,4 +17,118 @@ type Reconciler[Parent client.Object] interface {
 type Descriptor struct {
 	Name        string
 	Description string
+	// RequiredConditions are conditions that must be present (either in the conductor State or on the parent status)
+	// before the reconciler is run. If any are not met, the conductor skips the reconciler and requeues.
+	RequiredConditions []ConditionRequirement
+	// DependsOn are the names of the reconcilers that must complete before this one runs. When the conductor runs
+	// reconcilers in parallel, reconcilers without a dependency path between them run concurrently. Sequential
+	// conductors run reconcilers in registration order, which should respect the declared dependencies.
+	DependsOn []string
+	// ChildGVKs are the kinds of the objects managed by the reconciler, for reconcilers whose child types aren't known
+	// statically (e.g. unstructured children). They are combined with the types returned by ChildDescriber to wire
+	// watches, and can be used to generate documentation or RBAC rules.
+	ChildGVKs []schema.GroupVersionKind
+	// RequiredAPIs are the kinds that must be served by the API server for the reconciler to run, typically the kinds
+	// of optional integrations such as a ServiceMonitor. When one isn't, the conductor skips the reconciler, adds a
+	// `<Name>Unavailable` condition and checks again later.
+	RequiredAPIs []schema.GroupVersionKind
+	// Conditions are the condition Types the reconciler adds to the parent, other than those written by the conductor
+	// for it (e.g. `<Name>Reconciled`). They are documented with the pipeline and, with stale condition pruning, removed
+	// from the parent once the reconciler is disabled or runs without setting them.
+	Conditions []string
+	// Stability is the maturity of the reconciler, StabilityStable when empty.
+	Stability Stability
+	// Owner identifies the team or person owning the reconciler, for documentation and the debugging tools.
+	Owner string
+}
+
+// Stability is the maturity of a reconciler.
+type Stability string
+
+const (
+	StabilityStable     Stability = "Stable"
+	StabilityBeta       Stability = "Beta"
+	StabilityAlpha      Stability = "Alpha"
+	StabilityDeprecated Stability = "Deprecated"
+)
+
+// Valid returns true if the Stability is empty or one of the defined values.
+func (s Stability) Valid() bool {
+	switch s {
+	case "", StabilityStable, StabilityBeta, StabilityAlpha, StabilityDeprecated:
+		return true
+	}
+	return false
+}
+
+// ConditionRequirement describes a condition Type that must be reported with the given Status.
+type ConditionRequirement struct {
+	Type   string
+	Status metav1.ConditionStatus
+}
+
+// Finalizer is implemented by reconcilers that need to clean up when the parent is deleted.
+// When the conductor manages a finalizer on the parent, Finalize is called in reverse registration order once the
+// parent is marked for deletion. A result requesting a requeue means the cleanup is not complete yet.
+type Finalizer[Parent client.Object] interface {
+	Finalize(ctx context.Context, client client.Client, parent Parent) (reconcile.Result, error)
+}
+
+// Cleaner is implemented by reconcilers that can delete their children ahead of the garbage collector. When the
+// conductor runs its teardown sequence, Cleanup is called in reverse registration order once the parent is marked for
+// deletion, after Finalize. A result requesting a requeue means the children are not gone yet.
+type Cleaner[Parent client.Object] interface {
+	Cleanup(ctx context.Context, client client.Client, parent Parent) (reconcile.Result, error)
+}
+
+// ChildDescriber is implemented by reconcilers owning child objects through a controller reference.
+// Children returns an empty object of every child type, used to watch the children of the parent.
+type ChildDescriber interface {
+	Children() []client.Object
+}
+
+// ChildTypeDescriber is implemented by reconcilers managing child objects, whether they own them through a controller
+// reference or not. ChildTypes returns an empty object of every child type, used to check that they are registered in
+// the scheme; reconcilers not implementing it are checked through ChildDescriber.
+type ChildTypeDescriber interface {
+	ChildTypes() []client.Object
+}
+
+// ConditionDescriber is implemented by reconcilers adding conditions to the parent. Conditions returns the condition
+// Types the reconciler can produce, used to document the pipeline.
+type ConditionDescriber interface {
+	Conditions() []string
+}
+
+// Validator is implemented by reconcilers that can check their own configuration. The conductor calls Validate when
+// the reconciler is registered, so misconfigured reconcilers are reported by Conductor.Validate before the controller
+// starts processing events.
+type Validator interface {
+	Validate() error
+}
+
+// Middleware decorates a reconciler with a cross-cutting concern, such as logging, tracing or metrics. It returns a
+// reconciler calling next, usually built with Decorate.
+type Middleware[Parent client.Object] func(next Reconciler[Parent]) Reconciler[Parent]
+
+// ReconcileFunc is the signature of the Reconcile method of reconcilers.
+type ReconcileFunc[Parent client.Object] func(ctx context.Context, client client.Client, parent Parent) (reconcile.Result, error)
+
+// Decorate returns a reconciler with the Descriptor of next, whose Reconcile method calls fn. It is the building block
+// of middlewares: fn typically does some work around a call to next.Reconcile.
+func Decorate[Parent client.Object](next Reconciler[Parent], fn ReconcileFunc[Parent]) Reconciler[Parent] {
+	return &decorated[Parent]{next: next, fn: fn}
+}
+
+type decorated[Parent client.Object] struct {
+	next Reconciler[Parent]
+	fn   ReconcileFunc[Parent]
+}
+
+func (d *decorated[Parent]) Reconcile(ctx context.Context, client client.Client, parent Parent) (reconcile.Result, error) {
+	return d.fn(ctx, client, parent)
+}
+
+func (d *decorated[Parent]) Describe() Descriptor {
+	return d.next.Describe()
 }
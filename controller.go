@@ -0,0 +1,137 @@
+// Package maestro wires conductor pipelines into controller-runtime.
+package maestro
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ErrMissingConductor is returned by SetupWithManager when no conductor was provided.
+var ErrMissingConductor = errors.New("a conductor is required to set up the controller")
+
+// ControllerBuilder builds a controller reconciling a parent type with a conductor.
+type ControllerBuilder[Parent client.Object] struct {
+	parent    Parent
+	conductor *conductor.Conductor[Parent]
+	name      string
+	options   controller.Options
+}
+
+// NewControllerFor returns a new instance of ControllerBuilder for the parent type.
+// The parent is only used for its type; an empty object such as &myapi.App{} is expected.
+func NewControllerFor[Parent client.Object](parent Parent) *ControllerBuilder[Parent] {
+	return &ControllerBuilder[Parent]{
+		parent: parent,
+	}
+}
+
+// WithConductor sets the conductor run for every parent. The children declared by its registered reconcilers are
+// watched, so changes to the children reconcile the parent. Concurrent requests share the conductor, each Conduct
+// running on its own copy holding the parent of the request.
+func (b *ControllerBuilder[Parent]) WithConductor(c *conductor.Conductor[Parent]) *ControllerBuilder[Parent] {
+	b.conductor = c
+	return b
+}
+
+// WithName overrides the name of the controller, which defaults to the lowercased kind of the parent.
+func (b *ControllerBuilder[Parent]) WithName(name string) *ControllerBuilder[Parent] {
+	b.name = name
+	return b
+}
+
+// WithOptions sets the options of the controller, such as MaxConcurrentReconciles. With concurrent reconciles, the
+// registered reconcilers must be safe for concurrent use.
+func (b *ControllerBuilder[Parent]) WithOptions(options controller.Options) *ControllerBuilder[Parent] {
+	b.options = options
+	return b
+}
+
+// SetupWithManager creates the controller, watching the parent type and owning every child kind declared by the
+// reconcilers of the conductor, through api.ChildDescriber or the ChildGVKs of their Descriptor. The children are
+// mapped to their parent through their controller reference, or through the owner labels and annotations of the
+// children that can't have one (see reconciler.MarkOwned), such as cluster-scoped children of a namespaced parent. Each request fetches
+// the parent and passes it to the conductor; the conductor should be built with the client of the manager.
+func (b *ControllerBuilder[Parent]) SetupWithManager(mgr ctrl.Manager) error {
+	if b.conductor == nil {
+		return ErrMissingConductor
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(b.parent).
+		WithOptions(b.options)
+	if b.name != "" {
+		builder = builder.Named(b.name)
+	}
+	gvks, err := b.conductor.ChildGVKs(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	parentGVK, err := apiutil.GVKForObject(b.parent, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	for _, gvk := range gvks {
+		// Children owned through labels, e.g. cluster-scoped or in another namespace, have no owner reference.
+		builder = builder.
+			Owns(ownedObject(mgr.GetScheme(), gvk)).
+			Watches(ownedObject(mgr.GetScheme(), gvk), handler.EnqueueRequestsFromMapFunc(labelOwnerRequests(parentGVK.GroupKind())))
+	}
+
+	return builder.Complete(&controllerReconciler[Parent]{
+		client:    mgr.GetClient(),
+		parent:    b.parent,
+		conductor: b.conductor,
+	})
+}
+
+// ownedObject returns the object used to watch the children of the kind. Kinds unknown to the scheme are watched
+// through their metadata only.
+func ownedObject(scheme *runtime.Scheme, gvk schema.GroupVersionKind) client.Object {
+	if obj, err := scheme.New(gvk); err == nil {
+		if obj, ok := obj.(client.Object); ok {
+			return obj
+		}
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// labelOwnerRequests maps a child owned through labels to a request for its parent, unless the OwnerKindAnnotation of
+// the child names another kind of parent.
+func labelOwnerRequests(parentKind schema.GroupKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if kind, ok := obj.GetAnnotations()[reconciler.OwnerKindAnnotation]; ok && kind != parentKind.String() {
+			return nil
+		}
+		return reconciler.EnqueueOwner(ctx, obj)
+	}
+}
+
+// controllerReconciler fetches the parent of each request and runs the conductor for it.
+type controllerReconciler[Parent client.Object] struct {
+	client    client.Client
+	parent    Parent
+	conductor *conductor.Conductor[Parent]
+}
+
+func (r *controllerReconciler[Parent]) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	parent := r.parent.DeepCopyObject().(Parent)
+	if err := r.client.Get(ctx, req.NamespacedName, parent); err != nil {
+		// The parent is gone, its children are garbage collected.
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	return r.conductor.Conduct(ctx, parent)
+}
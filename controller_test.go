@@ -0,0 +1,159 @@
+package maestro
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newConductor(cli client.Client) *conductor.Conductor[*appsv1.Deployment] {
+	cond := conductor.ForParent(&appsv1.Deployment{}).WithClient(cli).Build()
+	cond.Register(simple.FromReconcileFunc(func(_ context.Context, parent *appsv1.Deployment) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace}}, nil
+	}).WithDetails(api.Descriptor{Name: "ConfigMap"}).Build())
+	return cond
+}
+
+func TestSetupWithManager(t *testing.T) {
+	mgr, err := manager.New(&rest.Config{Host: "http://127.0.0.1:0"}, manager.Options{
+		Metrics: server.Options{BindAddress: "0"},
+	})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, NewControllerFor(&appsv1.Deployment{}).SetupWithManager(mgr), ErrMissingConductor)
+
+	cond := newConductor(mgr.GetClient())
+	assert.Len(t, cond.Children(), 1)
+	require.NoError(t, NewControllerFor(&appsv1.Deployment{}).WithConductor(cond).SetupWithManager(mgr))
+
+	// Cluster-scoped children are watched as well
+	cond = newConductor(mgr.GetClient())
+	cond.Register(simple.FromReconcileFunc(func(_ context.Context, parent *appsv1.Deployment) (*rbacv1.ClusterRole, error) {
+		return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-reader"}}, nil
+	}).WithDetails(api.Descriptor{Name: "ClusterRole"}).Build())
+	require.NoError(t, NewControllerFor(&appsv1.Deployment{}).WithConductor(cond).WithName("deployment-roles").SetupWithManager(mgr))
+}
+
+func TestLabelOwnerRequests(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	parent := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	mapFn := labelOwnerRequests(appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind())
+
+	// A cluster-scoped child of a namespaced parent is mapped through its owner labels
+	child := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "app-reader"}}
+	require.NoError(t, reconciler.MarkOwned(parent, child, scheme))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(parent)}}, mapFn(ctx, child))
+
+	// Children of another kind of parent, and children without owner labels, are ignored
+	child.Annotations[reconciler.OwnerKindAnnotation] = "StatefulSet.apps"
+	assert.Empty(t, mapFn(ctx, child))
+	assert.Empty(t, mapFn(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "unowned"}}))
+}
+
+func TestOwnedObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	assert.IsType(t, &corev1.ConfigMap{}, ownedObject(scheme, corev1.SchemeGroupVersion.WithKind("ConfigMap")))
+
+	// Kinds unknown to the scheme are watched through their metadata
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	obj := ownedObject(scheme, widget)
+	assert.IsType(t, &metav1.PartialObjectMetadata{}, obj)
+	assert.Equal(t, widget, obj.GetObjectKind().GroupVersionKind())
+}
+
+func TestControllerReconcile(t *testing.T) {
+	ctx := context.Background()
+	parent := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(parent).Build()
+	r := &controllerReconciler[*appsv1.Deployment]{client: cli, parent: &appsv1.Deployment{}, conductor: newConductor(cli)}
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(parent)})
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(parent), &corev1.ConfigMap{}))
+
+	// Deleted parents are ignored
+	result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "gone", Namespace: "default"}})
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+}
+
+func TestControllerReconcileConcurrently(t *testing.T) {
+	ctx := context.Background()
+	builder := fake.NewClientBuilder()
+	var parents []*appsv1.Deployment
+	for i := range 20 {
+		parent := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app-%d", i), Namespace: "default", UID: types.UID(fmt.Sprintf("uid-%d", i))}}
+		builder.WithObjects(parent)
+		parents = append(parents, parent)
+	}
+	cli := builder.Build()
+	// The first runs wait for each other, so they all are in progress at once
+	var arrived sync.WaitGroup
+	arrived.Add(len(parents))
+	var calls atomic.Int32
+	cond := conductor.ForParent(&appsv1.Deployment{}).WithClient(cli).WithFinalizer("example.com/finalizer").
+		WithPreReconcileHook(func(context.Context, *appsv1.Deployment, api.Descriptor) error {
+			if calls.Add(1) <= int32(len(parents)) {
+				arrived.Done()
+				arrived.Wait()
+			}
+			return nil
+		}).
+		Build()
+	cond.Register(simple.FromReconcileFunc(func(_ context.Context, parent *appsv1.Deployment) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace}}, nil
+	}).WithDetails(api.Descriptor{Name: "ConfigMap"}).Build())
+	r := &controllerReconciler[*appsv1.Deployment]{client: cli, parent: &appsv1.Deployment{}, conductor: cond}
+
+	// Requests for different parents share the conductor, like with MaxConcurrentReconciles
+	var wg sync.WaitGroup
+	for _, parent := range parents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 3 {
+				_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(parent)})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, parent := range parents {
+		fetched := &appsv1.Deployment{}
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(parent), fetched))
+		assert.Equal(t, []string{"example.com/finalizer"}, fetched.Finalizers)
+
+		child := &corev1.ConfigMap{}
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(parent), child))
+		require.Len(t, child.OwnerReferences, 1)
+		assert.Equal(t, parent.UID, child.OwnerReferences[0].UID, "child of %s", parent.Name)
+	}
+}
@@ -0,0 +1,74 @@
+// Package benchmarks provides reproducible benchmarks for Maestro reconcilers and conductors, along with helpers to
+// quantify the reconcile cost of your own pipelines before deploying them.
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconciler measures a single reconciler invocation against the client and parent.
+// The first reconcile is run before measuring, so that the children exist and the steady state is measured.
+func Reconciler[Parent client.Object](ctx context.Context, r api.Reconciler[Parent], k8sCli client.Client, parent Parent) (testing.BenchmarkResult, error) {
+	if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+		return testing.BenchmarkResult{}, err
+	}
+
+	var runErr error
+	result := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+				runErr = err
+				b.FailNow()
+			}
+		}
+	})
+	return result, runErr
+}
+
+// Conductor measures a full Conduct of the pipeline for the parent.
+// The first Conduct is run before measuring, so that the children exist and the steady state is measured.
+func Conductor[Parent client.Object](ctx context.Context, c api.Conductor[Parent], parent Parent) (testing.BenchmarkResult, error) {
+	if _, err := c.Conduct(ctx, parent); err != nil {
+		return testing.BenchmarkResult{}, err
+	}
+
+	var runErr error
+	result := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.Conduct(ctx, parent); err != nil {
+				runErr = err
+				b.FailNow()
+			}
+		}
+	})
+	return result, runErr
+}
+
+// Report formats a benchmark result in the same format as `go test -bench`.
+func Report(name string, result testing.BenchmarkResult) string {
+	return fmt.Sprintf("%s\t%s\t%s", name, result.String(), result.MemString())
+}
+
+// ConfigMapOfSize returns a ConfigMap holding roughly size bytes of data, spread over keys of at most 1KiB.
+func ConfigMapOfSize(name, namespace string, size int) *corev1.ConfigMap {
+	data := map[string]string{}
+	for i := 0; size > 0; i++ {
+		chunk := min(size, 1024)
+		data[fmt.Sprintf("key-%d", i)] = strings.Repeat("x", chunk)
+		size -= chunk
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       data,
+	}
+}
@@ -0,0 +1,217 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newScheme(b *testing.B) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		b.Fatal(err)
+	}
+	return s
+}
+
+func newParent() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "parent-uid"},
+	}
+}
+
+func newReconciler(name string, size int, dryRun reconciler.DryRunType, opts ...cmp.Option) *simple.Reconciler[*corev1.ConfigMap, *corev1.ConfigMap] {
+	return simple.FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return ConfigMapOfSize(name, parent.Namespace, size), nil
+	}).
+		WithDetails(api.Descriptor{Name: name}).
+		WithDryRunType(dryRun).
+		AddCompareOpt(opts).
+		Build()
+}
+
+// BenchmarkReconcile measures the steady state of a simple reconciler (child exists and is up-to-date).
+func BenchmarkReconcile(b *testing.B) {
+	sizes := []int{1 << 10, 64 << 10, 512 << 10}
+	compareOpts := []struct {
+		name string
+		opts []cmp.Option
+	}{
+		{name: "default"},
+		{name: "ignore-annotations", opts: []cmp.Option{reconciler.IgnoreAnnotations(), reconciler.IgnoreFinalizers()}},
+	}
+
+	for _, size := range sizes {
+		for _, compare := range compareOpts {
+			opts := compare.opts
+			b.Run(fmt.Sprintf("size=%dKiB/opts=%s", size>>10, compare.name), func(b *testing.B) {
+				ctx := context.Background()
+				k8sCli := fake.NewClientBuilder().WithScheme(newScheme(b)).Build()
+				parent := newParent()
+				r := newReconciler("child", size, reconciler.DryRunNone, opts...)
+				if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+					b.Fatal(err)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// newDefaultingClient returns a fake client defaulting the Immutable field of the ConfigMaps it writes, dry-runs
+// included, like a mutating webhook would.
+func newDefaultingClient(b *testing.B) client.Client {
+	return fake.NewClientBuilder().WithScheme(newScheme(b)).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			defaultImmutable(obj)
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			defaultImmutable(obj)
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+}
+
+func defaultImmutable(obj client.Object) {
+	if cm, ok := obj.(*corev1.ConfigMap); ok && cm.Immutable == nil {
+		cm.Immutable = ptr.To(false)
+	}
+}
+
+// BenchmarkReconcileDefaults measures a simple reconciler whose child only differs from the desired object by API
+// defaults, by dry-run mode: without a dry-run the child is updated on every iteration, with one the update is found
+// to be a no-op.
+func BenchmarkReconcileDefaults(b *testing.B) {
+	sizes := []int{1 << 10, 64 << 10, 512 << 10}
+	dryRuns := []reconciler.DryRunType{reconciler.DryRunNone, reconciler.DryRunSilent, reconciler.DryRunWarn}
+
+	for _, size := range sizes {
+		for _, dryRun := range dryRuns {
+			b.Run(fmt.Sprintf("size=%dKiB/dryrun=%s", size>>10, dryRun), func(b *testing.B) {
+				ctx := context.Background()
+				k8sCli := newDefaultingClient(b)
+				parent := newParent()
+				r := newReconciler("child", size, dryRun)
+				if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+					b.Fatal(err)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					result, err := r.Reconcile(ctx, k8sCli, parent)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if updated := result.Requeue; updated != (dryRun == reconciler.DryRunNone) {
+						b.Fatalf("child updated: %t", updated)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkReconcileUpdate measures a simple reconciler updating a drifted child on every iteration.
+func BenchmarkReconcileUpdate(b *testing.B) {
+	for _, size := range []int{1 << 10, 64 << 10} {
+		b.Run(fmt.Sprintf("size=%dKiB", size>>10), func(b *testing.B) {
+			ctx := context.Background()
+			k8sCli := fake.NewClientBuilder().WithScheme(newScheme(b)).Build()
+			parent := newParent()
+			r := newReconciler("child", size, reconciler.DryRunNone)
+			if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				drifted := &corev1.ConfigMap{}
+				if err := k8sCli.Get(ctx, client.ObjectKey{Name: "child", Namespace: "default"}, drifted); err != nil {
+					b.Fatal(err)
+				}
+				drifted.Data["drift"] = fmt.Sprint(i)
+				if err := k8sCli.Update(ctx, drifted); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				if _, err := r.Reconcile(ctx, k8sCli, parent); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConduct measures a full Conduct in the steady state with a varying number of reconcilers.
+func BenchmarkConduct(b *testing.B) {
+	for _, count := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("reconcilers=%d", count), func(b *testing.B) {
+			ctx := context.Background()
+			k8sCli := fake.NewClientBuilder().WithScheme(newScheme(b)).Build()
+			parent := newParent()
+
+			c := conductor.ForParent(parent).WithClient(k8sCli).Build()
+			for i := 0; i < count; i++ {
+				c.Register(newReconciler(fmt.Sprintf("child-%d", i), 1<<10, reconciler.DryRunNone))
+			}
+			// Each reconciler requeues once after creating its child
+			for i := 0; i <= count; i++ {
+				if _, err := c.Conduct(ctx, parent); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Conduct(ctx, parent); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+	parent := newParent()
+
+	result, err := Reconciler[*corev1.ConfigMap](ctx, newReconciler("child", 1<<10, reconciler.DryRunNone), k8sCli, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.N == 0 {
+		t.Errorf("Reconciler did not run the benchmark")
+	}
+	t.Log(Report("child", result))
+}
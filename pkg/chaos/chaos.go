@@ -0,0 +1,220 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Fault string
+
+const (
+	// FaultConflict fails writes (Update and Patch) with a 409 Conflict.
+	FaultConflict Fault = "Conflict"
+	// FaultTimeout fails calls with a server timeout.
+	FaultTimeout Fault = "Timeout"
+	// FaultNotFoundAfterCreate makes the first Get of a freshly created object return NotFound, like a lagging cache.
+	FaultNotFoundAfterCreate Fault = "NotFoundAfterCreate"
+	// FaultLatency delays calls by Config.Latency before executing them.
+	FaultLatency Fault = "Latency"
+)
+
+var ErrInjected = errors.New("injected fault")
+
+// Config configures which faults are injected and how often.
+type Config struct {
+	// Rate is the probability (0 to 1) of injecting a fault on an eligible call.
+	Rate float64
+	// Faults are the faults to inject. One is picked at random among the ones eligible for a call.
+	Faults []Fault
+	// Latency is the delay added by FaultLatency.
+	Latency time.Duration
+	// Seed seeds the random source, for reproducible runs.
+	Seed int64
+}
+
+// Client wraps a client.Client and injects faults on a percentage of calls, so that pipelines can be soak-tested
+// for correct retry and condition behavior.
+type Client struct {
+	client.Client
+	config Config
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	created  map[client.ObjectKey]struct{}
+	injected map[Fault]int
+}
+
+var _ client.Client = &Client{}
+
+// Wrap returns a Client injecting faults into the calls made to the wrapped client.
+func Wrap(c client.Client, config Config) *Client {
+	return &Client{
+		Client:   c,
+		config:   config,
+		rand:     rand.New(rand.NewSource(config.Seed)),
+		created:  map[client.ObjectKey]struct{}{},
+		injected: map[Fault]int{},
+	}
+}
+
+// Injected returns how many times each fault was injected.
+func (c *Client) Injected() map[Fault]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	injected := make(map[Fault]int, len(c.injected))
+	for fault, count := range c.injected {
+		injected[fault] = count
+	}
+	return injected
+}
+
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.inject(ctx, "get", key, FaultTimeout, FaultLatency, FaultNotFoundAfterCreate); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.inject(ctx, "list", client.ObjectKey{}, FaultTimeout, FaultLatency); err != nil {
+		return err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.inject(ctx, "create", client.ObjectKeyFromObject(obj), FaultTimeout, FaultLatency); err != nil {
+		return err
+	}
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.created[client.ObjectKeyFromObject(obj)] = struct{}{}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.inject(ctx, "update", client.ObjectKeyFromObject(obj), FaultTimeout, FaultLatency, FaultConflict); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.inject(ctx, "patch", client.ObjectKeyFromObject(obj), FaultTimeout, FaultLatency, FaultConflict); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.inject(ctx, "delete", client.ObjectKeyFromObject(obj), FaultTimeout, FaultLatency); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *Client) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if err := c.inject(ctx, "deletecollection", client.ObjectKey{}, FaultTimeout, FaultLatency); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *Client) Status() client.SubResourceWriter {
+	return &statusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type statusWriter struct {
+	client.SubResourceWriter
+	client *Client
+}
+
+func (s *statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := s.client.inject(ctx, "update", client.ObjectKeyFromObject(obj), FaultTimeout, FaultLatency, FaultConflict); err != nil {
+		return err
+	}
+	return s.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (s *statusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := s.client.inject(ctx, "patch", client.ObjectKeyFromObject(obj), FaultTimeout, FaultLatency, FaultConflict); err != nil {
+		return err
+	}
+	return s.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// inject picks a fault eligible for the call and returns the error to fail it with, if any.
+// Latency faults sleep and return nil, unless the context is done first.
+func (c *Client) inject(ctx context.Context, verb string, key client.ObjectKey, eligible ...Fault) error {
+	fault, ok := c.roll(key, eligible)
+	if !ok {
+		return nil
+	}
+
+	switch fault {
+	case FaultLatency:
+		select {
+		case <-time.After(c.config.Latency):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case FaultConflict:
+		return apierrors.NewConflict(schema.GroupResource{}, key.Name, ErrInjected)
+	case FaultTimeout:
+		return apierrors.NewServerTimeout(schema.GroupResource{}, verb, 1)
+	case FaultNotFoundAfterCreate:
+		return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+	default:
+		return nil
+	}
+}
+
+func (c *Client) roll(key client.ObjectKey, eligible []Fault) (Fault, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var candidates []Fault
+	for _, fault := range c.config.Faults {
+		if !contains(eligible, fault) {
+			continue
+		}
+		if fault == FaultNotFoundAfterCreate {
+			if _, ok := c.created[key]; !ok {
+				continue
+			}
+		}
+		candidates = append(candidates, fault)
+	}
+	// A freshly created object is only eligible on the first Get.
+	if contains(eligible, FaultNotFoundAfterCreate) {
+		delete(c.created, key)
+	}
+
+	if len(candidates) == 0 || c.rand.Float64() >= c.config.Rate {
+		return "", false
+	}
+
+	fault := candidates[c.rand.Intn(len(candidates))]
+	c.injected[fault]++
+	return fault, true
+}
+
+func contains(faults []Fault, fault Fault) bool {
+	for _, f := range faults {
+		if f == fault {
+			return true
+		}
+	}
+	return false
+}
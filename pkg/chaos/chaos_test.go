@@ -0,0 +1,67 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConflictInjection(t *testing.T) {
+	ctx := context.Background()
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	c := Wrap(fake.NewClientBuilder().WithObjects(configMap).Build(), Config{
+		Rate:   1,
+		Faults: []Fault{FaultConflict},
+	})
+
+	// Reads are not eligible for conflicts
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(configMap), configMap))
+
+	err := c.Update(ctx, configMap)
+	assert.True(t, apierrors.IsConflict(err))
+	assert.Equal(t, 1, c.Injected()[FaultConflict])
+}
+
+func TestNotFoundAfterCreate(t *testing.T) {
+	ctx := context.Background()
+	c := Wrap(fake.NewClientBuilder().Build(), Config{
+		Rate:   1,
+		Faults: []Fault{FaultNotFoundAfterCreate},
+	})
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	require.NoError(t, c.Create(ctx, configMap))
+
+	err := c.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Only the first read after the create fails
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{}))
+}
+
+func TestRate(t *testing.T) {
+	ctx := context.Background()
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	c := Wrap(fake.NewClientBuilder().WithObjects(configMap).Build(), Config{
+		Rate:   0.5,
+		Faults: []Fault{FaultTimeout},
+		Seed:   42,
+	})
+
+	failures := 0
+	for i := 0; i < 1000; i++ {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{}); err != nil {
+			require.True(t, apierrors.IsServerTimeout(err))
+			failures++
+		}
+	}
+	assert.InDelta(t, 500, failures, 100)
+	assert.Equal(t, failures, c.Injected()[FaultTimeout])
+}
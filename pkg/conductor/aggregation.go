@@ -0,0 +1,32 @@
+package conductor
+
+import (
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AggregationPolicy configures how the results of the reconcilers are combined into the result of a run.
+// Errors always stop the run.
+type AggregationPolicy string
+
+const (
+	// ReturnOnFirstRequeue stops the run at the first reconciler requesting a requeue, and returns its result (default)
+	ReturnOnFirstRequeue AggregationPolicy = "return-on-first-requeue"
+	// RunAllAndReturnMinRequeueAfter runs every reconciler and returns the shortest RequeueAfter among them
+	RunAllAndReturnMinRequeueAfter AggregationPolicy = "run-all-min-requeue-after"
+	// RunAllAndReturnMaxRequeueAfter runs every reconciler and returns the longest RequeueAfter among them
+	RunAllAndReturnMaxRequeueAfter AggregationPolicy = "run-all-max-requeue-after"
+)
+
+// stopsOnRequeue returns true if a requeue stops the run.
+func (p AggregationPolicy) stopsOnRequeue() bool {
+	return p != RunAllAndReturnMinRequeueAfter && p != RunAllAndReturnMaxRequeueAfter
+}
+
+// merge combines two results following the policy.
+func (p AggregationPolicy) merge(a, b reconcile.Result) reconcile.Result {
+	if p == RunAllAndReturnMaxRequeueAfter {
+		return reconcile.Result{Requeue: a.Requeue || b.Requeue, RequeueAfter: max(a.RequeueAfter, b.RequeueAfter)}
+	}
+	return reconciler.MergeResults(a, b)
+}
@@ -0,0 +1,58 @@
+package conductor
+
+import (
+	"reflect"
+
+	"github.com/ethan-gallant/maestro/api"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Children returns the child types declared by the registered reconcilers implementing api.ChildDescriber, without
+// duplicates.
+func (d *Conductor[Parent]) Children() []client.Object {
+	seen := map[reflect.Type]bool{}
+	var children []client.Object
+	for _, r := range d.reconcilers {
+		describer, ok := r.(api.ChildDescriber)
+		if !ok {
+			continue
+		}
+		for _, child := range describer.Children() {
+			if t := reflect.TypeOf(child); !seen[t] {
+				seen[t] = true
+				children = append(children, child)
+			}
+		}
+	}
+	return children
+}
+
+// ChildGVKs returns the kinds of the children managed by the registered reconcilers, without duplicates: the ChildGVKs
+// of their Descriptor, followed by the types returned by Children resolved through the scheme.
+func (d *Conductor[Parent]) ChildGVKs(scheme *runtime.Scheme) ([]schema.GroupVersionKind, error) {
+	seen := map[schema.GroupVersionKind]bool{}
+	var gvks []schema.GroupVersionKind
+	add := func(gvk schema.GroupVersionKind) {
+		if !seen[gvk] {
+			seen[gvk] = true
+			gvks = append(gvks, gvk)
+		}
+	}
+
+	for _, r := range d.reconcilers {
+		for _, gvk := range r.Describe().ChildGVKs {
+			add(gvk)
+		}
+	}
+	for _, child := range d.Children() {
+		gvk, err := apiutil.GVKForObject(child, scheme)
+		if err != nil {
+			return nil, err
+		}
+		add(gvk)
+	}
+	return gvks, nil
+}
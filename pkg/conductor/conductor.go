@@ -2,63 +2,324 @@ package conductor
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 type Conductor[Parent client.Object] struct {
-	client            client.Client
-	ctx               context.Context
-	parent            Parent
-	log               klog.Logger
-	reconcilers       []api.Reconciler[Parent]
-	conditionsHandler StatusConditionHandler
+	client               client.Client
+	apiReader            client.Reader
+	ctx                  context.Context
+	parent               Parent
+	log                  logr.Logger
+	verbosity            map[string]int
+	reconcilers          []api.Reconciler[Parent]
+	gates                []func(Parent) bool
+	phases               []string
+	middlewares          []api.Middleware[Parent]
+	preHooks             []PreReconcileHook[Parent]
+	postHooks            []PostReconcileHook[Parent]
+	outputs              map[string]producer
+	errs                 []error
+	conditionsHandler    StatusConditionHandler
+	gateRequeueAfter     time.Duration
+	references           []Reference[Parent]
+	dependencies         []Dependency
+	finalizer            string
+	metrics              *Metrics
+	recorder             record.EventRecorder
+	parallelism          int
+	diffRenderer         reconciler.DiffRenderer
+	aggregation          AggregationPolicy
+	reconcilerTimeout    time.Duration
+	pruneStaleConditions bool
+	readyCondition       string
+	parentRefresh        ParentRefresh
+	requeueOnChange      bool
+	apis                 *APIAvailability
+	apiWarnings          bool
+	inspector            *Inspector
+	reports              *runReports
+	teardown             bool
+	// planner is set on the copy of the conductor used by Plan.
+	planner *planningClient
 }
 
+// DefaultGateRequeueAfter is the delay used to requeue the parent when a reconciler was skipped due to unmet
+// RequiredConditions.
+const DefaultGateRequeueAfter = 10 * time.Second
+
 type StatusConditionHandler func(ctx context.Context, client client.Client, parent client.Object, conditions []metav1.Condition) error
 
 var _ api.Conductor[client.Object] = &Conductor[client.Object]{}
 
 func (d *Conductor[Parent]) Register(reconciler api.Reconciler[Parent]) api.Conductor[Parent] {
-	d.reconcilers = append(d.reconcilers, reconciler)
+	return d.register(DefaultPhase, nil, reconciler)
+}
+
+// RegisterGated registers a reconciler that is only run while the gate returns true, e.g. a FeatureGates.Enabled
+// function, so experimental reconcilers can be rolled out without code changes. The gate is evaluated at the start of
+// every run; while it returns false, the reconciler is left out of the run as if it wasn't registered (finalization
+// included). A nil gate always enables the reconciler.
+func (d *Conductor[Parent]) RegisterGated(reconciler api.Reconciler[Parent], gate func() bool) api.Conductor[Parent] {
+	if gate == nil {
+		return d.register(DefaultPhase, nil, reconciler)
+	}
+	return d.register(DefaultPhase, func(Parent) bool { return gate() }, reconciler)
+}
+
+// RegisterIf registers a group of reconcilers that are only run when the predicate returns true for the parent, e.g.
+// when a feature is enabled in its spec, instead of repeating the same PredicateFn in each of them. When the predicate
+// returns false, the reconcilers are left out of the run as if they weren't registered (finalization included).
+func (d *Conductor[Parent]) RegisterIf(predicate func(parent Parent) bool, reconcilers ...api.Reconciler[Parent]) api.Conductor[Parent] {
+	return d.register(DefaultPhase, predicate, reconcilers...)
+}
+
+// register registers the reconcilers in the phase with the gate, which is nil for reconcilers always enabled.
+func (d *Conductor[Parent]) register(phase string, gate func(Parent) bool, reconcilers ...api.Reconciler[Parent]) api.Conductor[Parent] {
+	for _, reconciler := range reconcilers {
+		d.checkReconciler(reconciler)
+		d.checkContract(phase, reconciler)
+		d.reconcilers = append(d.reconcilers, reconciler)
+		d.gates = append(d.gates, gate)
+		d.phases = append(d.phases, phase)
+	}
+	return d
+}
+
+// Use adds middlewares decorating the Reconcile calls of every registered reconciler, so cross-cutting concerns are
+// layered onto the whole pipeline. The first middleware is the outermost one. Middlewares run inside the panic
+// recovery and the reconciler timeout of the conductor; Finalize calls are not decorated.
+func (d *Conductor[Parent]) Use(middlewares ...api.Middleware[Parent]) api.Conductor[Parent] {
+	d.middlewares = append(d.middlewares, middlewares...)
 	return d
 }
 
+// decorate applies the middlewares to the reconciler.
+func (d *Conductor[Parent]) decorate(reconciler api.Reconciler[Parent]) api.Reconciler[Parent] {
+	for _, middleware := range slices.Backward(d.middlewares) {
+		reconciler = middleware(reconciler)
+	}
+	return reconciler
+}
+
+// enabled returns true if the gate of the i-th registered reconciler, if any, is open for the parent.
+func (d *Conductor[Parent]) enabled(i int, parent Parent) bool {
+	return i >= len(d.gates) || d.gates[i] == nil || d.gates[i](parent)
+}
+
 func (d *Conductor[Parent]) Conduct(ctx context.Context, parent Parent) (reconcile.Result, error) {
-	state := &State{
-		Conditions: []metav1.Condition{},
+	return d.conduct(ctx, parent, nil)
+}
+
+// conduct runs the pipeline for the parent. If emit is set, it is called with the outcome of every reconciler as it
+// completes; returning false from emit stops the run. The run is kept as the LastRunReport of the parent.
+// Each run works on its own copy of the conductor holding the parent of the run, so concurrent runs for different
+// parents, e.g. with MaxConcurrentReconciles, don't share it.
+func (d *Conductor[Parent]) conduct(ctx context.Context, parent Parent, emit func(ReconcilerOutcome, error) bool) (reconcile.Result, error) {
+	run := *d
+	run.parent = parent
+	ctx = run.bindReconcileID(ctx)
+	emit, done := run.report(parent, emit)
+	result, err := run.run(ctx, parent, emit)
+	done(ctx, result, err)
+	return result, err
+}
+
+// run runs the pipeline for the parent, see conduct.
+func (d *Conductor[Parent]) run(ctx context.Context, parent Parent, emit func(ReconcilerOutcome, error) bool) (reconcile.Result, error) {
+	if err := d.Validate(); err != nil {
+		return reconcile.Result{}, err
 	}
-	if _, err := BindState(ctx, state); err != nil {
+	state := newState(parent)
+	ctx, err := BindState(ctx, state)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	d.parent = parent
-	for _, reconciler := range d.reconcilers {
-		if result, err := d.Reconcile(state.ctx, reconciler); shouldReturn(result, err) {
-			return result, err
+	phases := d.enabledPhases(parent)
+	var reconcilers []api.Reconciler[Parent]
+	for _, phase := range phases {
+		reconcilers = append(reconcilers, phase.reconcilers...)
+	}
+	if d.pruneStaleConditions {
+		owners, err := d.recordConditionOwners(ctx)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		d.enablePruning(state, phases, owners)
+	}
+	if d.metrics != nil || d.recorder != nil || d.inspector != nil {
+		ctx, err = operationBinder.BindToContext(ctx, &operationRecorder{
+			metrics:     d.metrics,
+			recorder:    d.recorder,
+			inspector:   d.inspector,
+			parent:      parent,
+			scheme:      d.client.Scheme(),
+			reconcileID: ReconcileID(ctx),
+		})
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if d.apiReader != nil {
+		if ctx, err = readerBinder.BindToContext(ctx, &liveReader{Reader: d.apiReader}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if len(d.dependencies) > 0 {
+		if ctx, err = d.bindDependencies(ctx); err != nil {
+			return reconcile.Result{}, err
 		}
 	}
 
-	if d.conditionsHandler != nil {
-		if err := d.conditionsHandler(state.ctx, d.client, parent, state.Conditions); err != nil {
+	if d.finalizer != "" {
+		if !parent.GetDeletionTimestamp().IsZero() {
+			return d.finalize(ctx, reconcilers, emit)
+		}
+		if err := d.ensureFinalizer(ctx); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
-	return reconcile.Result{}, nil
+	if len(d.references) > 0 {
+		resolved, err := d.resolveReferences(ctx, state)
+		if err != nil {
+			d.addReadyCondition(state, reconcile.Result{}, err)
+			return reconcile.Result{}, d.handleConditions(ctx, state, err)
+		}
+		ctx = resolved
+	}
+
+	aborted := false
+	if emit != nil {
+		next := emit
+		emit = func(outcome ReconcilerOutcome, err error) bool {
+			aborted = !next(outcome, err)
+			return !aborted
+		}
+	}
+	result, gated, stop, err := d.runPhases(ctx, state, phases, emit)
+	if stop {
+		if err == nil {
+			result = withResync(result, state)
+		}
+		if d.readyCondition != "" && !aborted {
+			// The aggregate condition reports the requeue or the error stopping the run.
+			d.addReadyCondition(state, result, err)
+			return result, d.handleConditions(ctx, state, err)
+		}
+		return result, err
+	}
+
+	if gated {
+		result = d.aggregation.merge(result, reconcile.Result{RequeueAfter: d.gateRequeueAfter})
+	}
+	d.addReadyCondition(state, result, nil)
+	if err := d.handleConditions(ctx, state, nil); err != nil {
+		return reconcile.Result{}, err
+	}
+	return withResync(result, state), nil
+}
+
+// runSequential runs the enabled reconcilers one at a time in registration order. It returns the results aggregated following
+// the AggregationPolicy, whether a reconciler was gated, and whether the run must stop without handling the conditions
+// (on error, on requeue unless every reconciler runs, or when emit returned false).
+func (d *Conductor[Parent]) runSequential(ctx context.Context, state *State, reconcilers []api.Reconciler[Parent], emit func(ReconcilerOutcome, error) bool) (reconcile.Result, bool, bool, error) {
+	gated := false
+	aggregated := reconcile.Result{}
+	for i, reconciler := range reconcilers {
+		desc := reconciler.Describe()
+		met, err := d.requirementsMet(ctx, state, desc)
+		if err != nil {
+			return reconcile.Result{}, gated, true, err
+		}
+		if !met {
+			gated = true
+			if emit != nil && !emit(ReconcilerOutcome{Descriptor: desc, Skipped: true}, nil) {
+				return reconcile.Result{}, gated, true, nil
+			}
+			continue
+		}
+		if i > 0 && d.parentRefresh == RefreshBetweenReconcilers {
+			changed, err := d.refreshParent(ctx)
+			if err != nil || changed {
+				return reconcile.Result{Requeue: changed}, gated, true, err
+			}
+		}
+
+		start := time.Now()
+		result, err := d.Reconcile(ctx, reconciler)
+		duration := time.Since(start)
+		if d.metrics != nil {
+			d.metrics.observeReconcile(desc.Name, duration, err)
+		}
+		if emit != nil && !emit(ReconcilerOutcome{Descriptor: desc, Result: result, Duration: duration}, err) {
+			return result, gated, true, err
+		}
+		if shouldReturn(result, err) && (err != nil || d.aggregation.stopsOnRequeue()) {
+			return result, gated, true, err
+		}
+		aggregated = d.aggregation.merge(aggregated, result)
+	}
+	return aggregated, gated, false, nil
+}
+
+// handleConditions passes the conditions collected in the State to the conditionsHandler, if any, deduplicated and
+// sorted by Type.
+// The error of the run (if any) takes precedence over an error returned by the handler.
+func (d *Conductor[Parent]) handleConditions(ctx context.Context, state *State, runErr error) error {
+	if d.conditionsHandler == nil {
+		return runErr
+	}
+	if err := d.conditionsHandler(ctx, d.client, d.parent, state.sortedConditions()); err != nil && runErr == nil {
+		return err
+	}
+	return runErr
 }
 
 // Reconcile takes a single reconciler and invokes its Reconcile method, providing the necessary dependencies.
+// A panic of the reconciler is recovered and returned as an ErrReconcilerPanicked error. With a reconciler timeout, the
+// reconciler is run through RunWithTimeout. The reconciler is decorated with the middlewares added with Use, and run
+// between the pre and post reconcile hooks. With WithAPIWarnings, the warnings returned to it by the API server are
+// reported as a `<Name>APIWarning` condition. Errors are returned as a ReconcileError, attributing them to the
+// reconciler, the parent and the child if known. The logger of the context passed to the reconciler has its name
+// and the key of the parent, see Logger.
 func (d *Conductor[Parent]) Reconcile(
 	ctx context.Context,
 	reconciler api.Reconciler[Parent],
-) (reconcile.Result, error) {
-	return reconciler.Reconcile(ctx, d.client, d.parent)
+) (result reconcile.Result, err error) {
+	name := reconciler.Describe().Name
+	start := time.Now()
+	defer func() {
+		d.inspect(name, start, result, err)
+		err = d.wrapError(name, err)
+	}()
+	ctx = d.withReconcilerLogger(ctx, name)
+	markRan(ctx, name)
+	if available, err := d.apisAvailable(ctx, reconciler.Describe()); !available {
+		return reconcile.Result{}, err
+	}
+	if d.apiWarnings {
+		var warnings *warningCollector
+		ctx, warnings = collectWarnings(ctx)
+		defer d.reportWarnings(ctx, reconciler.Describe(), warnings)
+	}
+	defer d.planner.attribute(name)()
+	defer recoverPanic(ctx, name, &err)
+	decorated := d.decorate(reconciler)
+	return RunWithTimeout(ctx, name, d.reconcilerTimeout, func(ctx context.Context) (reconcile.Result, error) {
+		return d.runHooked(ctx, decorated)
+	})
 }
 
 func shouldReturn(result reconcile.Result, err error) bool {
@@ -2,8 +2,13 @@ package conductor
 
 import (
 	"context"
+	"time"
 
-	"k8s.io/klog/v2"
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -14,8 +19,10 @@ type Builder[Parent client.Object] struct {
 func ForParent[Parent client.Object](parent Parent) *Builder[Parent] {
 	return &Builder[Parent]{
 		conductor: &Conductor[Parent]{
-			parent: parent,
-			ctx:    context.Background(),
+			parent:           parent,
+			ctx:              context.Background(),
+			gateRequeueAfter: DefaultGateRequeueAfter,
+			aggregation:      ReturnOnFirstRequeue,
 		},
 	}
 }
@@ -25,29 +32,245 @@ func (b *Builder[Parent]) WithClient(c client.Client) *Builder[Parent] {
 	return b
 }
 
+// WithAPIReader sets an uncached reader, e.g. the one of manager.GetAPIReader, next to the cached client of
+// WithClient. Reconcilers retrieve it with APIReader to read objects that may be stale in the cache.
+func (b *Builder[Parent]) WithAPIReader(reader client.Reader) *Builder[Parent] {
+	b.conductor.apiReader = reader
+	return b
+}
+
 func (b *Builder[Parent]) WithContext(ctx context.Context) *Builder[Parent] {
 	b.conductor.ctx = ctx
 	return b
 }
 
-func (b *Builder[Parent]) WithLogger(l klog.Logger) *Builder[Parent] {
+// WithLogger sets the logger of the conductor and of its reconcilers, which receive it from the context. Without it,
+// the logger of the context passed to Conduct is used, e.g. the one controller-runtime passes to Reconcile.
+func (b *Builder[Parent]) WithLogger(l logr.Logger) *Builder[Parent] {
 	b.conductor.log = l
 	return b
 }
 
+// WithReconcilerVerbosity shifts the verbosity of the logs of the named reconciler: with a positive shift, its log
+// lines of level V(shift) are written at level 0, e.g. to debug a single reconciler without raising the verbosity of
+// the whole controller. A negative shift quiets the reconciler instead.
+func (b *Builder[Parent]) WithReconcilerVerbosity(name string, shift int) *Builder[Parent] {
+	if b.conductor.verbosity == nil {
+		b.conductor.verbosity = map[string]int{}
+	}
+	b.conductor.verbosity[name] = shift
+	return b
+}
+
 func (b *Builder[Parent]) WithStatusConditionsHandler(handler StatusConditionHandler) *Builder[Parent] {
 	b.conductor.conditionsHandler = handler
 	return b
 }
 
+// WithGateRequeueAfter sets how long to wait before requeueing when a reconciler was skipped
+// because its RequiredConditions were not met.
+func (b *Builder[Parent]) WithGateRequeueAfter(after time.Duration) *Builder[Parent] {
+	b.conductor.gateRequeueAfter = after
+	return b
+}
+
+// WithReferences registers ConfigMaps and Secrets referenced by the parent to be resolved before the reconcilers run.
+// The resolved contents are available to reconcilers through FetchReferences.
+func (b *Builder[Parent]) WithReferences(references ...Reference[Parent]) *Builder[Parent] {
+	b.conductor.references = append(b.conductor.references, references...)
+	return b
+}
+
+// WithDependencies registers dependencies bound into the context of every run, see Provide and Inject.
+func (b *Builder[Parent]) WithDependencies(dependencies ...Dependency) *Builder[Parent] {
+	b.conductor.dependencies = append(b.conductor.dependencies, dependencies...)
+	return b
+}
+
+// WithFinalizer enables finalizer management: the finalizer is added to the parent, and once the parent is marked
+// for deletion, the reconcilers implementing api.Finalizer are finalized in reverse registration order before the
+// finalizer is removed.
+func (b *Builder[Parent]) WithFinalizer(finalizer string) *Builder[Parent] {
+	b.conductor.finalizer = finalizer
+	return b
+}
+
+// WithMetrics records Prometheus metrics for every reconciler, registered against the registerer. A nil registerer
+// uses controller-runtime's metrics registry, exposed by the manager's metrics endpoint.
+func (b *Builder[Parent]) WithMetrics(registerer prometheus.Registerer) *Builder[Parent] {
+	b.conductor.metrics = NewMetrics(registerer)
+	return b
+}
+
+// WithEventRecorder records an Event on the parent for every child created, updated or deleted by the reconcilers.
+// The recorder is typically obtained from the manager with mgr.GetEventRecorderFor.
+func (b *Builder[Parent]) WithEventRecorder(recorder record.EventRecorder) *Builder[Parent] {
+	b.conductor.recorder = recorder
+	return b
+}
+
+// WithParallelism runs up to workers reconcilers concurrently, ordered by the DependsOn of their Descriptor.
+// When a reconciler fails, requeues, or is gated, its dependents are not run while independent reconcilers carry on;
+// the results are merged and the errors joined. A value of 1 or less keeps the sequential registration order.
+func (b *Builder[Parent]) WithParallelism(workers int) *Builder[Parent] {
+	b.conductor.parallelism = workers
+	return b
+}
+
+// WithParentRefresh reads the parent again between the phases or the reconcilers of a run, so later reconcilers act
+// on its latest spec. With requeueOnGenerationChange, the run stops and the parent is requeued instead when its
+// generation changed, so the whole pipeline runs again on the new spec.
+func (b *Builder[Parent]) WithParentRefresh(refresh ParentRefresh, requeueOnGenerationChange bool) *Builder[Parent] {
+	b.conductor.parentRefresh = refresh
+	b.conductor.requeueOnChange = requeueOnGenerationChange
+	return b
+}
+
+// WithAPIAvailability shares the lookups of the RequiredAPIs of the reconcilers across runs. Without it, the kinds are
+// looked up through the REST mapper of the client on every run.
+func (b *Builder[Parent]) WithAPIAvailability(availability *APIAvailability) *Builder[Parent] {
+	b.conductor.apis = availability
+	return b
+}
+
+// WithAPIWarnings reports the warnings returned by the API server to each reconciler, such as the deprecation of an
+// API version it writes, as a `<Name>APIWarning` condition and in the Metrics. The transport of the client must be
+// wrapped with WrapWarnings.
+func (b *Builder[Parent]) WithAPIWarnings() *Builder[Parent] {
+	b.conductor.apiWarnings = true
+	return b
+}
+
+// WithInspector records the last run of each reconciler and its last action on a child per parent in the inspector,
+// which serves them over HTTP. An inspector can be shared by the conductors of several controllers.
+func (b *Builder[Parent]) WithInspector(inspector *Inspector) *Builder[Parent] {
+	b.conductor.inspector = inspector
+	return b
+}
+
+// WithDiffRenderer sets how Plan renders the diffs of the planned updates, e.g. reconciler.YAMLDiffRenderer for a
+// kubectl diff style output. Defaults to the cmp.Diff of the objects.
+func (b *Builder[Parent]) WithDiffRenderer(renderer reconciler.DiffRenderer) *Builder[Parent] {
+	b.conductor.diffRenderer = renderer
+	return b
+}
+
+// WithAggregationPolicy sets how the results of the reconcilers are combined. By default, the run stops at the first
+// reconciler requesting a requeue; RunAllAndReturnMinRequeueAfter and RunAllAndReturnMaxRequeueAfter run every
+// reconciler and return the shortest or longest RequeueAfter among them.
+func (b *Builder[Parent]) WithAggregationPolicy(policy AggregationPolicy) *Builder[Parent] {
+	b.conductor.aggregation = policy
+	return b
+}
+
+// WithReconcilerTimeout bounds the duration of each reconciler. A reconciler exceeding it requeues the parent with
+// the backoff of the controller and adds a `<Name>TimedOut` condition, see RunWithTimeout.
+func (b *Builder[Parent]) WithReconcilerTimeout(timeout time.Duration) *Builder[Parent] {
+	b.conductor.reconcilerTimeout = timeout
+	return b
+}
+
+// WithStaleConditionPruning removes the conditions owned by maestro that are no longer produced from the parent, such
+// as those of removed reconcilers or a `<Name>Error` condition after a successful reconcile. The names of the
+// registered reconcilers, references and phases are recorded on the parent in the ConditionOwnersAnnotation, so
+// conditions written by others are never pruned. See State.IsStale.
+func (b *Builder[Parent]) WithStaleConditionPruning(prune bool) *Builder[Parent] {
+	b.conductor.pruneStaleConditions = prune
+	return b
+}
+
+// WithReadyCondition adds a condition of the given Type (typically DefaultReadyConditionType) aggregating the outcome
+// of the run: True once every reconciler completed without error, requeue, or condition showing it is not done, such
+// as a `<Name>Waiting` or `<Name>Progressing` one. The conditions are then also handled when a requeue or an error
+// stops the run, so the condition turns False.
+func (b *Builder[Parent]) WithReadyCondition(conditionType string) *Builder[Parent] {
+	b.conductor.readyCondition = conditionType
+	return b
+}
+
+// WithTeardown runs the Cleanup hook of the reconcilers implementing api.Cleaner when the parent is deleted, in
+// reverse registration order, waiting for the children of each reconciler to be gone before the previous one is torn
+// down and the finalizer is released. It requires WithFinalizer.
+func (b *Builder[Parent]) WithTeardown(teardown bool) *Builder[Parent] {
+	b.conductor.teardown = teardown
+	return b
+}
+
+// WithMiddlewares adds middlewares decorating every reconciler, see Conductor.Use.
+func (b *Builder[Parent]) WithMiddlewares(middlewares ...api.Middleware[Parent]) *Builder[Parent] {
+	b.conductor.middlewares = append(b.conductor.middlewares, middlewares...)
+	return b
+}
+
+// WithPreReconcileHook adds a hook called before each reconciler, e.g. for audit logging or per-reconciler gating.
+// Returning ErrSkipReconciler skips the reconciler, any other error fails it.
+func (b *Builder[Parent]) WithPreReconcileHook(hook PreReconcileHook[Parent]) *Builder[Parent] {
+	b.conductor.preHooks = append(b.conductor.preHooks, hook)
+	return b
+}
+
+// WithPostReconcileHook adds a hook called after each reconciler with its result and error, e.g. for custom
+// telemetry.
+func (b *Builder[Parent]) WithPostReconcileHook(hook PostReconcileHook[Parent]) *Builder[Parent] {
+	b.conductor.postHooks = append(b.conductor.postHooks, hook)
+	return b
+}
+
 func (b *Builder[Parent]) Build() *Conductor[Parent] {
 	// Return an identical copy of the conductor (to prevent mutation)
 	return &Conductor[Parent]{
-		client:            b.conductor.client,
-		ctx:               b.conductor.ctx,
-		parent:            b.conductor.parent,
-		log:               b.conductor.log,
-		reconcilers:       b.conductor.reconcilers,
-		conditionsHandler: b.conductor.conditionsHandler,
+		client:               b.conductor.client,
+		apiReader:            b.conductor.apiReader,
+		ctx:                  b.conductor.ctx,
+		parent:               b.conductor.parent,
+		log:                  b.conductor.log,
+		verbosity:            b.conductor.verbosity,
+		reconcilers:          b.conductor.reconcilers,
+		gates:                b.conductor.gates,
+		phases:               b.conductor.phases,
+		middlewares:          b.conductor.middlewares,
+		preHooks:             b.conductor.preHooks,
+		postHooks:            b.conductor.postHooks,
+		outputs:              b.conductor.outputs,
+		errs:                 b.conductor.errs,
+		conditionsHandler:    b.conductor.conditionsHandler,
+		gateRequeueAfter:     b.conductor.gateRequeueAfter,
+		references:           b.conductor.references,
+		dependencies:         b.conductor.dependencies,
+		finalizer:            b.conductor.finalizer,
+		metrics:              b.conductor.metrics,
+		recorder:             b.conductor.recorder,
+		parallelism:          b.conductor.parallelism,
+		diffRenderer:         b.conductor.diffRenderer,
+		aggregation:          b.conductor.aggregation,
+		reconcilerTimeout:    b.conductor.reconcilerTimeout,
+		pruneStaleConditions: b.conductor.pruneStaleConditions,
+		readyCondition:       b.conductor.readyCondition,
+		parentRefresh:        b.conductor.parentRefresh,
+		requeueOnChange:      b.conductor.requeueOnChange,
+		apis:                 b.conductor.apis,
+		apiWarnings:          b.conductor.apiWarnings,
+		inspector:            b.conductor.inspector,
+		reports:              newRunReports(),
+		teardown:             b.conductor.teardown,
+	}
+}
+
+// BuildValidated builds the conductor and validates its configuration, see Conductor.Validate. Reconcilers registered
+// afterwards are validated by Validate and Conduct.
+func (b *Builder[Parent]) BuildValidated() (*Conductor[Parent], error) {
+	d := b.Build()
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MustBuild is like BuildValidated, but panics if the configuration is invalid.
+func (b *Builder[Parent]) MustBuild() *Conductor[Parent] {
+	d, err := b.BuildValidated()
+	if err != nil {
+		panic(err)
 	}
+	return d
 }
@@ -2,13 +2,35 @@ package conductor
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -68,7 +90,1785 @@ func TestDirector(t *testing.T) {
 	if !mockReconciler.Called {
 		t.Errorf("Reconcile did not call Reconciler's Reconcile method")
 	}
-	if mockReconciler.Ctx != director.ctx || mockReconciler.Client != director.client || mockReconciler.Parent != director.parent {
+	// The context passed to the reconciler carries its logger
+	if mockReconciler.Ctx.Value(reconcilerLoggerKey{}) != "MockReconciler" || mockReconciler.Client != director.client || mockReconciler.Parent != director.parent {
 		t.Errorf("Reconcile did not pass the correct parameters to the Reconciler")
 	}
 }
+
+type ConditionReconciler struct {
+	Details   api.Descriptor
+	Condition *metav1.Condition
+	Called    bool
+}
+
+func (c *ConditionReconciler) Describe() api.Descriptor {
+	return c.Details
+}
+
+func (c *ConditionReconciler) Reconcile(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+	c.Called = true
+	if c.Condition != nil {
+		state, err := FetchState(ctx)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		state.AddCondition(*c.Condition)
+	}
+	return reconcile.Result{}, nil
+}
+
+func TestConductRequiredConditions(t *testing.T) {
+	ctx := context.Background()
+	parent := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+
+	gated := &ConditionReconciler{
+		Details: api.Descriptor{
+			Name: "Gated",
+			RequiredConditions: []api.ConditionRequirement{
+				{Type: "DatabaseReady", Status: metav1.ConditionTrue},
+			},
+		},
+	}
+
+	director := ForParent(parent).WithClient(fake.NewClientBuilder().Build()).Build()
+	director.Register(gated)
+
+	result, err := director.Conduct(ctx, parent)
+	if err != nil {
+		t.Fatalf("Conduct returned an unexpected error: %v", err)
+	}
+	if gated.Called {
+		t.Errorf("Conduct ran a reconciler whose required conditions were not met")
+	}
+	if result.RequeueAfter != DefaultGateRequeueAfter {
+		t.Errorf("Conduct did not requeue after skipping a gated reconciler, got %v", result)
+	}
+
+	// A condition added by an earlier reconciler satisfies the requirement
+	producer := &ConditionReconciler{
+		Details: api.Descriptor{Name: "Database"},
+		Condition: &metav1.Condition{
+			Type:   "DatabaseReady",
+			Status: metav1.ConditionTrue,
+		},
+	}
+	director = ForParent(parent).WithClient(fake.NewClientBuilder().Build()).Build()
+	director.Register(producer).Register(gated)
+
+	result, err = director.Conduct(ctx, parent)
+	if err != nil {
+		t.Fatalf("Conduct returned an unexpected error: %v", err)
+	}
+	if !gated.Called {
+		t.Errorf("Conduct did not run a reconciler whose required conditions were met")
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("Conduct requeued although all reconcilers ran, got %v", result)
+	}
+
+	// A condition on the parent status satisfies the requirement
+	gated.Called = false
+	gated.Details.RequiredConditions = []api.ConditionRequirement{
+		{Type: string(corev1.PodReady), Status: metav1.ConditionTrue},
+	}
+	parent.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+	}
+	director = ForParent(parent).WithClient(fake.NewClientBuilder().Build()).Build()
+	director.Register(gated)
+
+	if _, err := director.Conduct(ctx, parent); err != nil {
+		t.Fatalf("Conduct returned an unexpected error: %v", err)
+	}
+	if !gated.Called {
+		t.Errorf("Conduct did not consider the parent status conditions")
+	}
+}
+
+type FuncReconciler struct {
+	Name      string
+	DependsOn []string
+	Fn        func(ctx context.Context, c client.Client, parent *corev1.Pod) (reconcile.Result, error)
+}
+
+func (f *FuncReconciler) Describe() api.Descriptor {
+	return api.Descriptor{Name: f.Name, DependsOn: f.DependsOn}
+}
+
+func (f *FuncReconciler) Reconcile(ctx context.Context, c client.Client, parent *corev1.Pod) (reconcile.Result, error) {
+	return f.Fn(ctx, c, parent)
+}
+
+func TestConductReferences(t *testing.T) {
+	ctx := context.Background()
+	parent := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "default",
+			Annotations: map[string]string{"configRef": "app-config"},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"logLevel": "debug"},
+	}
+
+	references := []Reference[*corev1.Pod]{
+		{
+			Name: "Config",
+			Kind: ConfigMapReference,
+			KeyFn: func(parent *corev1.Pod) client.ObjectKey {
+				return client.ObjectKey{Name: parent.Annotations["configRef"], Namespace: parent.Namespace}
+			},
+		},
+		{
+			Name: "Credentials",
+			Kind: SecretReference,
+			KeyFn: func(parent *corev1.Pod) client.ObjectKey {
+				return client.ObjectKey{Name: parent.Annotations["secretRef"], Namespace: parent.Namespace}
+			},
+			Optional: true,
+		},
+	}
+
+	var logLevel string
+	var conditions []metav1.Condition
+	director := ForParent(parent).
+		WithClient(fake.NewClientBuilder().WithObjects(configMap).Build()).
+		WithReferences(references...).
+		WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+			conditions = c
+			return nil
+		}).
+		Build()
+	director.Register(&FuncReconciler{
+		Name: "Reader",
+		Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			refs, err := FetchReferences(ctx)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			logLevel, _ = refs.Value("Config", "logLevel")
+			return reconcile.Result{}, nil
+		},
+	})
+
+	_, err := director.Conduct(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", logLevel)
+
+	// A missing required reference is reported as a condition and an error
+	conditions = nil
+	parent.Annotations["configRef"] = "missing"
+	_, err = director.Conduct(ctx, parent)
+	assert.ErrorIs(t, err, ErrMissingReference)
+	require.Len(t, conditions, 1)
+	assert.Equal(t, "ConfigReferenceResolved", conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+}
+
+func TestConductDependencies(t *testing.T) {
+	type deps struct {
+		Greeting string
+	}
+
+	ctx := context.Background()
+	parent := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+
+	var greeting string
+	director := ForParent(parent).
+		WithClient(fake.NewClientBuilder().Build()).
+		WithDependencies(Provide(&deps{Greeting: "hello"})).
+		Build()
+	director.Register(&FuncReconciler{
+		Name: "Injected",
+		Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			injected, err := Inject[deps](ctx)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			greeting = injected.Greeting
+			return reconcile.Result{}, nil
+		},
+	})
+
+	_, err := director.Conduct(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", greeting)
+
+	_, err = Inject[deps](ctx)
+	assert.Error(t, err)
+}
+
+func TestConductSeq(t *testing.T) {
+	ctx := context.Background()
+	parent := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+
+	newDirector := func() *Conductor[*corev1.Pod] {
+		director := ForParent(parent).WithClient(fake.NewClientBuilder().Build()).Build()
+		director.
+			Register(&ConditionReconciler{Details: api.Descriptor{Name: "First"}}).
+			Register(&ConditionReconciler{Details: api.Descriptor{
+				Name:               "Gated",
+				RequiredConditions: []api.ConditionRequirement{{Type: "Missing", Status: metav1.ConditionTrue}},
+			}}).
+			Register(&FuncReconciler{
+				Name: "Failing",
+				Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+					return reconcile.Result{}, assert.AnError
+				},
+			})
+		return director
+	}
+
+	var names []string
+	var skipped []bool
+	var errs []error
+	for outcome, err := range newDirector().ConductSeq(ctx, parent) {
+		names = append(names, outcome.Descriptor.Name)
+		skipped = append(skipped, outcome.Skipped)
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []string{"First", "Gated", "Failing"}, names)
+	assert.Equal(t, []bool{false, true, false}, skipped)
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], assert.AnError)
+
+	// Stopping the iteration stops the run
+	names = nil
+	for outcome := range newDirector().ConductSeq(ctx, parent) {
+		names = append(names, outcome.Descriptor.Name)
+		break
+	}
+	assert.Equal(t, []string{"First"}, names)
+}
+
+type FinalizingReconciler struct {
+	FuncReconciler
+	Finalized *[]string
+}
+
+func (f *FinalizingReconciler) Finalize(_ context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+	*f.Finalized = append(*f.Finalized, f.Name)
+	return reconcile.Result{}, nil
+}
+
+func TestConductFinalizer(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var finalized []string
+	reconciled := 0
+	newReconciler := func(name string) *FinalizingReconciler {
+		return &FinalizingReconciler{
+			FuncReconciler: FuncReconciler{Name: name, Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+				reconciled++
+				return reconcile.Result{}, nil
+			}},
+			Finalized: &finalized,
+		}
+	}
+
+	const finalizer = "example.com/cleanup"
+	cond := ForParent(pod).WithClient(cli).WithFinalizer(finalizer).Build()
+	cond.Register(newReconciler("first"))
+	cond.Register(newReconciler("second"))
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reconciled)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+	assert.Contains(t, pod.Finalizers, finalizer)
+	assert.Empty(t, finalized)
+
+	require.NoError(t, cli.Delete(ctx, pod))
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+	require.False(t, pod.DeletionTimestamp.IsZero())
+
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reconciled, "the pipeline should not run while finalizing")
+	assert.Equal(t, []string{"second", "first"}, finalized)
+
+	err = cli.Get(ctx, client.ObjectKeyFromObject(pod), pod)
+	assert.True(t, apierrors.IsNotFound(err), "the parent should be gone once the finalizer is removed")
+}
+
+// CleaningReconciler stands for a reconciler whose children take Pending cleanups to disappear.
+type CleaningReconciler struct {
+	FuncReconciler
+	Pending int
+	Cleaned *[]string
+}
+
+func (c *CleaningReconciler) Cleanup(_ context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+	*c.Cleaned = append(*c.Cleaned, c.Name)
+	if c.Pending > 0 {
+		c.Pending--
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+func TestResyncInterval(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var ran []string
+	reconciler := func(name string, result reconcile.Result) *FuncReconciler {
+		return &FuncReconciler{Name: name, Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			ran = append(ran, name)
+			return result, nil
+		}}
+	}
+
+	// The smallest interval is used, without stopping the run
+	cond := ForParent(pod).WithClient(cli).Build()
+	cond.Register(WithResyncInterval[*corev1.Pod](reconciler("config", reconcile.Result{}), 10*time.Minute))
+	cond.Register(WithResyncInterval[*corev1.Pod](reconciler("certificate", reconcile.Result{}), 5*time.Minute))
+	cond.Register(reconciler("service", reconcile.Result{}))
+	result, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: 5 * time.Minute}, result)
+	assert.Equal(t, []string{"config", "certificate", "service"}, ran)
+
+	// Sooner requeues take precedence
+	cond = ForParent(pod).WithClient(cli).Build()
+	cond.Register(WithResyncInterval[*corev1.Pod](reconciler("config", reconcile.Result{}), 10*time.Minute))
+	cond.Register(reconciler("workload", reconcile.Result{RequeueAfter: time.Second}))
+	result, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Second}, result)
+
+	// Without a conductor, the interval is returned by the reconciler
+	result, err = WithResyncInterval[*corev1.Pod](reconciler("config", reconcile.Result{}), time.Minute).Reconcile(ctx, cli, pod)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, result)
+}
+
+func TestReadyCondition(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var conditions []metav1.Condition
+	results := map[string]reconcile.Result{}
+	errs := map[string]error{}
+	reconciler := func(name string) *FuncReconciler {
+		return &FuncReconciler{Name: name, Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			RecordResult(ctx, name, results[name], errs[name])
+			return results[name], errs[name]
+		}}
+	}
+	cond := ForParent(pod).WithClient(cli).
+		WithReadyCondition(DefaultReadyConditionType).
+		WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+			conditions = c
+			return nil
+		}).
+		Build()
+	cond.Register(reconciler("database"))
+	cond.Register(reconciler("workload"))
+
+	// Every reconciler completed
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	ready := meta.FindStatusCondition(conditions, DefaultReadyConditionType)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, "Reconciled", ready.Reason)
+
+	// A requeue stops the run but still reports the condition
+	results["database"] = reconcile.Result{RequeueAfter: time.Second}
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	ready = meta.FindStatusCondition(conditions, DefaultReadyConditionType)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "Progressing", ready.Reason)
+	assert.Equal(t, "database has not completed", ready.Message)
+
+	// So does an error
+	results["database"] = reconcile.Result{}
+	errs["workload"] = fmt.Errorf("quota exceeded")
+	_, err = cond.Conduct(ctx, pod)
+	require.Error(t, err)
+	ready = meta.FindStatusCondition(conditions, DefaultReadyConditionType)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "ReconcileError", ready.Reason)
+	assert.Equal(t, "quota exceeded", ready.Message)
+
+	// Conditions showing a reconciler is not done make the parent not ready
+	errs["workload"] = nil
+	cond.Register(&FuncReconciler{Name: "certificate", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		state, err := FetchState(ctx)
+		require.NoError(t, err)
+		state.AddCondition(metav1.Condition{Type: "certificateWaiting", Status: metav1.ConditionTrue, Reason: "IssuerNotReady", Message: "Waiting for the issuer"})
+		return reconcile.Result{}, nil
+	}})
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	ready = meta.FindStatusCondition(conditions, DefaultReadyConditionType)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "Waiting", ready.Reason)
+	assert.Equal(t, "certificateWaiting: Waiting for the issuer", ready.Message)
+}
+
+func TestParentRefresh(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", Generation: 1}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var seen []string
+	changeParent := &FuncReconciler{Name: "change", Fn: func(ctx context.Context, c client.Client, parent *corev1.Pod) (reconcile.Result, error) {
+		changed := &corev1.Pod{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(parent), changed); err != nil {
+			return reconcile.Result{}, err
+		}
+		changed.Labels = map[string]string{"version": "2"}
+		changed.Generation++
+		return reconcile.Result{}, c.Update(ctx, changed)
+	}}
+	observe := &FuncReconciler{Name: "observe", Fn: func(_ context.Context, _ client.Client, parent *corev1.Pod) (reconcile.Result, error) {
+		seen = append(seen, parent.Labels["version"])
+		return reconcile.Result{}, nil
+	}}
+
+	// Later reconcilers see the latest parent
+	cond := ForParent(pod).WithClient(cli).WithParentRefresh(RefreshBetweenReconcilers, false).Build()
+	cond.Register(changeParent)
+	cond.Register(observe)
+	result, err := cond.Conduct(ctx, pod.DeepCopy())
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, []string{"2"}, seen)
+
+	// Without a refresh, they see the parent passed to Conduct
+	cond = ForParent(pod).WithClient(cli).Build()
+	cond.Register(changeParent)
+	cond.Register(observe)
+	_, err = cond.Conduct(ctx, pod.DeepCopy())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", ""}, seen)
+
+	// A new generation requeues the parent instead
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+	cond = ForParent(pod).WithClient(cli).WithParentRefresh(RefreshBetweenReconcilers, true).Build()
+	cond.Register(changeParent)
+	cond.Register(observe)
+	result, err = cond.Conduct(ctx, pod.DeepCopy())
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+	assert.Equal(t, []string{"2", ""}, seen, "the reconcilers after the change should not run")
+}
+
+// DescribedReconciler stands for a reconciler with a custom descriptor.
+type DescribedReconciler struct {
+	FuncReconciler
+	Descriptor api.Descriptor
+}
+
+func (r *DescribedReconciler) Describe() api.Descriptor {
+	return r.Descriptor
+}
+
+func TestRequiredAPIs(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	serviceMonitor := schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	cli := fake.NewClientBuilder().WithObjects(pod).WithRESTMapper(mapper).Build()
+
+	var ran []string
+	var conditions []metav1.Condition
+	newConductor := func(availability *APIAvailability) *Conductor[*corev1.Pod] {
+		cond := ForParent(pod).WithClient(cli).
+			WithAPIAvailability(availability).
+			WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+				conditions = c
+				return nil
+			}).
+			Build()
+		cond.Register(&DescribedReconciler{
+			FuncReconciler: FuncReconciler{Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+				ran = append(ran, "monitoring")
+				return reconcile.Result{}, nil
+			}},
+			Descriptor: api.Descriptor{Name: "monitoring", RequiredAPIs: []schema.GroupVersionKind{serviceMonitor}},
+		})
+		cond.Register(&FuncReconciler{Name: "workload", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			ran = append(ran, "workload")
+			return reconcile.Result{}, nil
+		}})
+		return cond
+	}
+
+	// The reconciler is skipped while the API is missing, and the parent checked again later
+	availability := NewAPIAvailability(time.Minute)
+	result, err := newConductor(availability).Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, result)
+	assert.Equal(t, []string{"workload"}, ran)
+	unavailable := meta.FindStatusCondition(conditions, "monitoringUnavailable")
+	require.NotNil(t, unavailable)
+	assert.Equal(t, "APINotServed", unavailable.Reason)
+	assert.Equal(t, "The API server doesn't serve monitoring.coreos.com/v1, Kind=ServiceMonitor", unavailable.Message)
+
+	// The missing API is remembered until the recheck
+	mapper.Add(serviceMonitor, meta.RESTScopeNamespace)
+	ran = nil
+	_, err = newConductor(availability).Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"workload"}, ran)
+
+	// Once installed, the API is found
+	ran = nil
+	result, err = newConductor(nil).Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, []string{"monitoring", "workload"}, ran)
+	available, err := availability.Available(mapper, serviceMonitor, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, available)
+}
+
+func TestAPIWarnings(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/api/v1/namespaces/default/configmaps/deprecated" {
+			w.Header().Add("Warning", `299 - "v1 ConfigMap is deprecated"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL, ContentConfig: rest.ContentConfig{ContentType: runtime.ContentTypeJSON}}
+	WrapWarnings(config)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	cli, err := client.New(config, client.Options{Scheme: scheme.Scheme, Mapper: mapper})
+	require.NoError(t, err)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	registry := prometheus.NewRegistry()
+	var conditions []metav1.Condition
+	cond := ForParent(pod).WithClient(cli).
+		WithMetrics(registry).
+		WithAPIWarnings().
+		WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+			conditions = c
+			return nil
+		}).
+		Build()
+	for _, name := range []string{"deprecated", "current"} {
+		cond.Register(&FuncReconciler{Name: name, Fn: func(ctx context.Context, c client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			return reconcile.Result{}, c.Update(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}})
+		}})
+	}
+
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	warning := meta.FindStatusCondition(conditions, "deprecatedAPIWarning")
+	require.NotNil(t, warning)
+	assert.Equal(t, metav1.ConditionTrue, warning.Status)
+	assert.Equal(t, "v1 ConfigMap is deprecated", warning.Message)
+	assert.Nil(t, meta.FindStatusCondition(conditions, "currentAPIWarning"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cond.metrics.APIWarnings.WithLabelValues("deprecated")))
+}
+
+func TestReconcileError(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+	childKey := client.ObjectKey{Name: "parent-config", Namespace: "default"}
+
+	var childErr error
+	cond := ForParent(pod).WithClient(cli).Build()
+	cond.Register(&FuncReconciler{Name: "config", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{}, childErr
+	}})
+
+	// Errors are attributed to the reconciler and the parent
+	childErr = assert.AnError
+	_, err := cond.Conduct(ctx, pod)
+	var reconcileErr *ReconcileError
+	require.ErrorAs(t, err, &reconcileErr)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, "config", reconcileErr.Reconciler)
+	assert.Equal(t, client.ObjectKeyFromObject(pod), reconcileErr.Parent)
+	assert.Equal(t, client.ObjectKey{}, reconcileErr.Child)
+	assert.Equal(t, "reconciler config (parent default/parent): "+assert.AnError.Error(), err.Error())
+
+	// And to the child, when reported by the reconciler
+	childErr = reconciler.WithChildKey(assert.AnError, childKey)
+	_, err = cond.Conduct(ctx, pod)
+	require.ErrorAs(t, err, &reconcileErr)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, childKey, reconcileErr.Child)
+	assert.Equal(t, "reconciler config (parent default/parent, child default/parent-config): "+assert.AnError.Error(), err.Error())
+}
+
+func TestReconcilerLogger(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+	ctx := WithReconcileID(klog.NewContext(context.Background(), sink), "run-1")
+
+	cond := ForParent(pod).WithClient(cli).Build()
+	cond.Register(&FuncReconciler{Name: "config", Fn: func(ctx context.Context, _ client.Client, parent *corev1.Pod) (reconcile.Result, error) {
+		klog.FromContext(ctx).Info("from the context")
+		Logger(ctx, "config", parent).Info("from Logger")
+		return reconcile.Result{}, nil
+	}})
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, `"level"=0 "msg"="from the context" "reconcileID"="run-1" "reconciler"="config" "parent"={"name"="parent" "namespace"="default"}`, lines[0])
+	assert.Equal(t, `"level"=0 "msg"="from Logger" "reconcileID"="run-1" "reconciler"="config" "parent"={"name"="parent" "namespace"="default"}`, lines[1])
+
+	// Outside a conductor, Logger adds the values
+	lines = nil
+	Logger(ctx, "config", pod).Info("standalone")
+	assert.Equal(t, []string{`"level"=0 "msg"="standalone" "reconciler"="config" "parent"={"name"="parent" "namespace"="default"}`}, lines)
+}
+
+func TestWithLogger(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+	reconciler := func(name string) *FuncReconciler {
+		return &FuncReconciler{Name: name, Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			klog.FromContext(ctx).V(1).Info("debug")
+			return reconcile.Result{}, nil
+		}}
+	}
+
+	// The logger of the conductor takes precedence over the one of the context, and the verbosity of the logs of a
+	// reconciler can be raised
+	cond := ForParent(pod).WithClient(cli).
+		WithLogger(logger).
+		WithReconcilerVerbosity("config", 1).
+		Build()
+	cond.Register(reconciler("config"))
+	cond.Register(reconciler("workload"))
+	_, err := cond.Conduct(WithReconcileID(klog.NewContext(context.Background(), logr.Discard()), "run-1"), pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"level"=0 "msg"="debug" "reconcileID"="run-1" "reconciler"="config" "parent"={"name"="parent" "namespace"="default"}`}, lines)
+}
+
+func TestReconcileID(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var ids []types.UID
+	cond := ForParent(pod).WithClient(cli).Build()
+	for _, name := range []string{"config", "workload"} {
+		cond.Register(&FuncReconciler{Name: name, Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			ids = append(ids, ReconcileID(ctx))
+			return reconcile.Result{}, nil
+		}})
+	}
+
+	// Every reconciler of a run shares its ID
+	_, err := cond.Conduct(context.Background(), pod)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.NotEmpty(t, ids[0])
+	assert.Equal(t, ids[0], ids[1])
+
+	// Runs have different IDs
+	_, err = cond.Conduct(context.Background(), pod)
+	require.NoError(t, err)
+	require.Len(t, ids, 4)
+	assert.NotEqual(t, ids[0], ids[2])
+	assert.Empty(t, ReconcileID(context.Background()))
+}
+
+func TestConductTeardown(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var cleaned []string
+	noop := func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}
+	const finalizer = "example.com/teardown"
+	cond := ForParent(pod).WithClient(cli).WithFinalizer(finalizer).WithTeardown(true).Build()
+	cond.Register(&CleaningReconciler{FuncReconciler: FuncReconciler{Name: "volume", Fn: noop}, Cleaned: &cleaned})
+	cond.Register(&CleaningReconciler{FuncReconciler: FuncReconciler{Name: "workload", Fn: noop}, Pending: 1, Cleaned: &cleaned})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Empty(t, cleaned)
+
+	require.NoError(t, cli.Delete(ctx, pod))
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+
+	// The volume is only torn down once the workload is gone
+	result, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+	assert.Equal(t, []string{"workload"}, cleaned)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+	assert.Contains(t, pod.Finalizers, finalizer)
+
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"workload", "workload", "volume"}, cleaned)
+	assert.True(t, apierrors.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(pod), pod)))
+
+	// The teardown sequence requires a finalizer
+	err = ForParent(pod).WithClient(cli).WithTeardown(true).Build().Validate()
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+}
+
+func TestPatchStatusConditions(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).WithStatusSubresource(pod).Build()
+
+	ready := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Reconciled",
+		LastTransitionTime: metav1.Now()}
+	require.NoError(t, PatchStatusConditions(ctx, cli, pod, []metav1.Condition{ready}))
+
+	// A stale copy of the parent must not clobber the patched conditions.
+	stale := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", ResourceVersion: "1"}}
+	synced := metav1.Condition{Type: "Synced", Status: metav1.ConditionFalse, Reason: "Pending",
+		LastTransitionTime: metav1.Now()}
+	require.NoError(t, PatchStatusConditions(ctx, cli, stale, []metav1.Condition{synced}))
+
+	updated := &corev1.Pod{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), updated))
+	conditions := map[corev1.PodConditionType]corev1.ConditionStatus{}
+	for _, condition := range updated.Status.Conditions {
+		conditions[condition.Type] = condition.Status
+	}
+	assert.Equal(t, map[corev1.PodConditionType]corev1.ConditionStatus{
+		"Ready":  corev1.ConditionTrue,
+		"Synced": corev1.ConditionFalse,
+	}, conditions)
+	assert.Equal(t, updated.ResourceVersion, stale.ResourceVersion)
+	assert.Len(t, stale.Status.Conditions, 2)
+}
+
+func TestConductMetrics(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+	registry := prometheus.NewRegistry()
+
+	newConductor := func() *Conductor[*corev1.Pod] {
+		cond := ForParent(pod).WithClient(cli).WithMetrics(registry).Build()
+		cond.Register(&FuncReconciler{Name: "creator", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			RecordOperation(ctx, "creator", OperationCreate, nil)
+			return reconcile.Result{}, nil
+		}})
+		cond.Register(&FuncReconciler{Name: "failing", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			return reconcile.Result{}, assert.AnError
+		}})
+		return cond
+	}
+
+	// Building the conductor again must reuse the registered collectors.
+	for i := 0; i < 2; i++ {
+		_, err := newConductor().Conduct(ctx, pod)
+		require.ErrorIs(t, err, assert.AnError)
+	}
+
+	m := NewMetrics(registry)
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.ChildOperations.WithLabelValues("creator", string(OperationCreate))))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.ReconcileErrors.WithLabelValues("failing")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.ReconcileErrors.WithLabelValues("creator")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.ReconcileDuration))
+}
+
+func TestConductParallel(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	// a and b only complete once both started, which requires them to run concurrently.
+	started := make(chan struct{}, 2)
+	barrier := func(name string) func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			started <- struct{}{}
+			for len(started) < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			record(name)
+			return reconcile.Result{}, nil
+		}
+	}
+	step := func(name string, result reconcile.Result, err error) func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			record(name)
+			return result, err
+		}
+	}
+
+	t.Run("runs independent reconcilers concurrently", func(t *testing.T) {
+		order = nil
+		cond := ForParent(pod).WithClient(cli).WithParallelism(4).Build()
+		cond.Register(&FuncReconciler{Name: "c", DependsOn: []string{"a", "b"}, Fn: step("c", reconcile.Result{}, nil)})
+		cond.Register(&FuncReconciler{Name: "a", Fn: barrier("a")})
+		cond.Register(&FuncReconciler{Name: "b", Fn: barrier("b")})
+
+		result, err := cond.Conduct(ctx, pod)
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result)
+		require.Len(t, order, 3)
+		assert.ElementsMatch(t, []string{"a", "b"}, order[:2])
+		assert.Equal(t, "c", order[2])
+	})
+
+	t.Run("skips dependents of failed reconcilers", func(t *testing.T) {
+		order = nil
+		cond := ForParent(pod).WithClient(cli).WithParallelism(2).Build()
+		cond.Register(&FuncReconciler{Name: "failing", Fn: step("failing", reconcile.Result{}, assert.AnError)})
+		cond.Register(&FuncReconciler{Name: "dependent", DependsOn: []string{"failing"}, Fn: step("dependent", reconcile.Result{}, nil)})
+		cond.Register(&FuncReconciler{Name: "requeue", Fn: step("requeue", reconcile.Result{RequeueAfter: time.Minute}, nil)})
+		cond.Register(&FuncReconciler{Name: "independent", Fn: step("independent", reconcile.Result{}, nil)})
+
+		result, err := cond.Conduct(ctx, pod)
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, time.Minute, result.RequeueAfter)
+		assert.ElementsMatch(t, []string{"failing", "requeue", "independent"}, order)
+	})
+
+	t.Run("rejects invalid dependencies", func(t *testing.T) {
+		cond := ForParent(pod).WithClient(cli).WithParallelism(2).Build()
+		cond.Register(&FuncReconciler{Name: "a", DependsOn: []string{"b"}, Fn: step("a", reconcile.Result{}, nil)})
+		cond.Register(&FuncReconciler{Name: "b", DependsOn: []string{"a"}, Fn: step("b", reconcile.Result{}, nil)})
+		_, err := cond.Conduct(ctx, pod)
+		assert.ErrorIs(t, err, ErrDependencyCycle)
+
+		cond = ForParent(pod).WithClient(cli).WithParallelism(2).Build()
+		cond.Register(&FuncReconciler{Name: "a", DependsOn: []string{"missing"}, Fn: step("a", reconcile.Result{}, nil)})
+		_, err = cond.Conduct(ctx, pod)
+		assert.ErrorIs(t, err, ErrUnknownDependency)
+	})
+}
+
+func TestConductEvents(t *testing.T) {
+	ctx := WithReconcileID(context.Background(), "run-1")
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+	cond := ForParent(pod).WithClient(cli).WithEventRecorder(recorder).Build()
+	cond.Register(&FuncReconciler{Name: "ConfigMap", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		RecordOperation(ctx, "ConfigMap", OperationCreate, child)
+		RecordOperation(ctx, "ConfigMap", OperationNoOp, child)
+		RecordOperation(ctx, "ConfigMap", OperationDelete, child)
+		return reconcile.Result{}, nil
+	}})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+
+	close(recorder.Events)
+	var events []string
+	for event := range recorder.Events {
+		events = append(events, event)
+	}
+	assert.Equal(t, []string{
+		"Normal Created ConfigMap: Created ConfigMap default/config map[maestro.io/reconcile-id:run-1]",
+		"Normal Deleted ConfigMap: Deleted ConfigMap default/config map[maestro.io/reconcile-id:run-1]",
+	}, events)
+}
+
+func TestInspector(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+	inspector := NewInspector()
+
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+	cond := ForParent(pod).WithClient(cli).WithInspector(inspector).WithAggregationPolicy(RunAllAndReturnMinRequeueAfter).Build()
+	cond.Register(&FuncReconciler{Name: "ConfigMap", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		RecordOperation(ctx, "ConfigMap", OperationCreate, child)
+		RecordOperation(ctx, "ConfigMap", OperationNoOp, child)
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}})
+	cond.Register(&FuncReconciler{Name: "Failing", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{}, fmt.Errorf("boom")
+	}})
+
+	_, err := cond.Conduct(context.Background(), pod)
+	require.Error(t, err)
+
+	pipelines := inspector.Pipelines()
+	require.Len(t, pipelines, 1)
+	assert.Equal(t, "Pod", pipelines[0].Parent)
+	assert.Equal(t, []api.Descriptor{{Name: "ConfigMap"}, {Name: "Failing"}}, pipelines[0].Reconcilers)
+
+	statuses := pipelines[0].Parents["default/parent"]
+	require.Contains(t, statuses, "ConfigMap")
+	assert.Equal(t, "1m0s", statuses["ConfigMap"].RequeueAfter)
+	require.NotNil(t, statuses["ConfigMap"].LastAction)
+	assert.Equal(t, OperationCreate, statuses["ConfigMap"].LastAction.Operation)
+	assert.Equal(t, "ConfigMap default/config", statuses["ConfigMap"].LastAction.Child)
+	assert.Equal(t, "boom", statuses["Failing"].Error)
+
+	rec := httptest.NewRecorder()
+	inspector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/maestro", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"requeueAfter": "1m0s"`)
+
+	inspector.MaxParents = 1
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+	require.NoError(t, cli.Create(context.Background(), other))
+	_, _ = cond.Conduct(context.Background(), other)
+	parents := inspector.Pipelines()[0].Parents
+	assert.Len(t, parents, 1)
+	assert.Contains(t, parents, "default/other")
+}
+
+func TestLastRunReport(t *testing.T) {
+	ctx := WithReconcileID(context.Background(), "run-1")
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	cond := ForParent(pod).WithClient(cli).WithAggregationPolicy(RunAllAndReturnMinRequeueAfter).Build()
+	cond.Register(&FuncReconciler{Name: "ConfigMap", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}})
+	cond.Register(&FuncReconciler{Name: "Failing", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{}, fmt.Errorf("boom")
+	}})
+	assert.Equal(t, []api.Descriptor{{Name: "ConfigMap"}, {Name: "Failing"}}, cond.Reconcilers())
+
+	_, ok := cond.LastRunReport(client.ObjectKeyFromObject(pod))
+	assert.False(t, ok)
+
+	_, err := cond.Conduct(ctx, pod)
+	require.Error(t, err)
+
+	report, ok := cond.LastRunReport(client.ObjectKeyFromObject(pod))
+	require.True(t, ok)
+	assert.Equal(t, types.UID("run-1"), report.ReconcileID)
+	assert.Equal(t, err, report.Err)
+	require.Len(t, report.Outcomes, 2)
+	assert.Equal(t, "ConfigMap", report.Outcomes[0].Descriptor.Name)
+	assert.Equal(t, "Failing", report.Outcomes[1].Descriptor.Name)
+	require.Contains(t, report.Errors, "Failing")
+	assert.ErrorContains(t, report.Errors["Failing"], "boom")
+	assert.NotContains(t, report.Errors, "ConfigMap")
+
+	// Plans aren't reported
+	_, _ = cond.Plan(WithReconcileID(context.Background(), "plan-1"), pod)
+	report, _ = cond.LastRunReport(client.ObjectKeyFromObject(pod))
+	assert.Equal(t, types.UID("run-1"), report.ReconcileID)
+}
+
+type ChildReconciler struct {
+	FuncReconciler
+	ChildGVKs []schema.GroupVersionKind
+	Objects   []client.Object
+}
+
+func (c *ChildReconciler) Describe() api.Descriptor {
+	return api.Descriptor{Name: c.Name, ChildGVKs: c.ChildGVKs}
+}
+
+func (c *ChildReconciler) Children() []client.Object {
+	return c.Objects
+}
+
+func TestChildGVKs(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().Build()
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+	cond := ForParent(pod).WithClient(cli).Build()
+	cond.Register(&ChildReconciler{FuncReconciler: FuncReconciler{Name: "widgets"}, ChildGVKs: []schema.GroupVersionKind{widget}})
+	cond.Register(&ChildReconciler{FuncReconciler: FuncReconciler{Name: "configs"}, Objects: []client.Object{&corev1.ConfigMap{}}})
+	cond.Register(&ChildReconciler{FuncReconciler: FuncReconciler{Name: "more-configs"}, Objects: []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}}})
+	cond.Register(&FuncReconciler{Name: "no-children"})
+
+	assert.Len(t, cond.Children(), 2)
+
+	gvks, err := cond.ChildGVKs(cli.Scheme())
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{
+		widget,
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		corev1.SchemeGroupVersion.WithKind("Secret"),
+	}, gvks)
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Data:       map[string]string{"key": "old"},
+	}
+	stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod, existing, stale).Build()
+
+	cond := ForParent(pod).WithClient(cli).WithFinalizer("maestro.io/test").Build()
+	cond.Register(&FuncReconciler{Name: "Create", Fn: func(ctx context.Context, c client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{Requeue: true}, c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"}})
+	}})
+	cond.Register(&FuncReconciler{Name: "Update", Fn: func(ctx context.Context, c client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(existing), cm); err != nil {
+			return reconcile.Result{}, err
+		}
+		cm.Data = map[string]string{"key": "new"}
+		return reconcile.Result{}, c.Update(ctx, cm)
+	}})
+	cond.Register(&FuncReconciler{Name: "Delete", Fn: func(ctx context.Context, c client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		return reconcile.Result{}, c.Delete(ctx, stale.DeepCopy())
+	}})
+
+	plan, err := cond.Plan(ctx, pod)
+	require.NoError(t, err)
+	require.True(t, plan.HasChanges())
+	require.Len(t, plan.Outcomes, 3, "a requeue should not stop the plan")
+
+	var summary []string
+	for _, change := range plan.Changes {
+		summary = append(summary, change.String())
+	}
+	assert.Equal(t, []string{
+		"update Pod default/parent (conductor)",
+		"create ConfigMap default/new (Create)",
+		"update ConfigMap default/existing (Update)",
+		"delete ConfigMap default/stale (Delete)",
+	}, summary)
+	assert.Contains(t, plan.Changes[2].Diff, `"old"`)
+	assert.Contains(t, plan.Changes[2].Diff, `"new"`)
+	assert.Contains(t, plan.Changes[0].Diff, "maestro.io/test")
+	assert.Empty(t, pod.Finalizers, "the parent should be left untouched")
+
+	// Nothing was written.
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.ConfigMap{}))
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(existing), existing))
+	assert.Equal(t, "old", existing.Data["key"])
+	err = cli.Get(ctx, client.ObjectKey{Name: "new", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+	assert.Empty(t, pod.Finalizers)
+}
+
+func TestPlanYAMLDiff(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Data:       map[string]string{"key": "old"},
+	}
+	cli := fake.NewClientBuilder().WithObjects(pod, existing).Build()
+
+	cond := ForParent(pod).WithClient(cli).WithDiffRenderer(reconciler.YAMLDiffRenderer{}).Build()
+	cond.Register(&FuncReconciler{Name: "Update", Fn: func(ctx context.Context, c client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(existing), cm); err != nil {
+			return reconcile.Result{}, err
+		}
+		cm.Data = map[string]string{"key": "new"}
+		return reconcile.Result{}, c.Update(ctx, cm)
+	}})
+
+	plan, err := cond.Plan(ctx, pod)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	diff := plan.Changes[0].Diff
+	assert.Contains(t, diff, "--- current/default/existing")
+	assert.Contains(t, diff, "+++ desired/default/existing")
+	assert.Contains(t, diff, "-  key: old\n")
+	assert.Contains(t, diff, "+  key: new\n")
+}
+
+func TestConductAggregationPolicy(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	requeueAfter := func(after time.Duration) func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		return func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			return reconcile.Result{RequeueAfter: after}, nil
+		}
+	}
+	tests := []struct {
+		policy   AggregationPolicy
+		expected time.Duration
+		runs     int
+	}{
+		{policy: ReturnOnFirstRequeue, expected: time.Minute, runs: 1},
+		{policy: RunAllAndReturnMinRequeueAfter, expected: 10 * time.Second, runs: 3},
+		{policy: RunAllAndReturnMaxRequeueAfter, expected: time.Hour, runs: 3},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			handled := false
+			cond := ForParent(pod).WithClient(cli).WithAggregationPolicy(tt.policy).
+				WithStatusConditionsHandler(func(context.Context, client.Client, client.Object, []metav1.Condition) error {
+					handled = true
+					return nil
+				}).
+				Build()
+			cond.Register(&FuncReconciler{Name: "Minute", Fn: requeueAfter(time.Minute)})
+			cond.Register(&FuncReconciler{Name: "Hour", Fn: requeueAfter(time.Hour)})
+			cond.Register(&FuncReconciler{Name: "Seconds", Fn: requeueAfter(10 * time.Second)})
+
+			runs := 0
+			for _, err := range cond.ConductSeq(ctx, pod) {
+				require.NoError(t, err)
+				runs++
+			}
+			assert.Equal(t, tt.runs, runs)
+
+			result, err := cond.Conduct(ctx, pod)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.RequeueAfter)
+			assert.Equal(t, tt.policy != ReturnOnFirstRequeue, handled, "conditions are handled once every reconciler ran")
+		})
+	}
+}
+
+func TestConductPanicRecovery(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	cond := ForParent(pod).WithClient(cli).Build()
+	cond.Register(&FuncReconciler{Name: "Buggy", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		var m map[string]string
+		m["boom"] = "panic"
+		return reconcile.Result{}, nil
+	}})
+
+	state := &State{}
+	ctx, err := BindState(ctx, state)
+	require.NoError(t, err)
+	_, err = cond.Reconcile(ctx, cond.reconcilers[0])
+	require.ErrorIs(t, err, ErrReconcilerPanicked)
+	assert.Contains(t, err.Error(), "Buggy")
+
+	require.Len(t, state.Conditions, 1)
+	assert.Equal(t, "BuggyPanicked", state.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, state.Conditions[0].Status)
+	assert.Contains(t, state.Conditions[0].Message, "assignment to entry in nil map")
+	assert.LessOrEqual(t, len(state.Conditions[0].Message), MaxPanicStackLength+200)
+
+	// The run fails instead of crashing
+	_, err = cond.Conduct(context.Background(), pod)
+	require.ErrorIs(t, err, ErrReconcilerPanicked)
+}
+
+func TestConductReconcilerTimeout(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cli := fake.NewClientBuilder().WithObjects(pod).Build()
+
+	var conditions []metav1.Condition
+	cond := ForParent(pod).WithClient(cli).
+		WithReconcilerTimeout(10 * time.Millisecond).
+		WithAggregationPolicy(RunAllAndReturnMinRequeueAfter).
+		WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+			conditions = c
+			return nil
+		}).
+		Build()
+	cond.Register(&FuncReconciler{Name: "Hung", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		<-ctx.Done()
+		return reconcile.Result{}, ctx.Err()
+	}})
+	ran := false
+	cond.Register(&FuncReconciler{Name: "Next", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		ran = true
+		return reconcile.Result{}, nil
+	}})
+
+	result, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.True(t, ran, "a hung reconciler should not block the pipeline")
+
+	var types []string
+	for _, c := range conditions {
+		types = append(types, c.Type)
+	}
+	assert.Contains(t, types, "HungTimedOut")
+}
+
+func TestConductSortedConditions(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", Generation: 7}}
+
+	var conditions []metav1.Condition
+	cond := ForParent(pod).
+		WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).
+		WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+			conditions = c
+			return nil
+		}).
+		Build()
+	cond.Register(&FuncReconciler{
+		Name: "Writer",
+		Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			state, err := FetchState(ctx)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			state.AddCondition(metav1.Condition{Type: "Zeta", Status: metav1.ConditionTrue, Reason: "Set"})
+			state.AddCondition(metav1.Condition{Type: "Alpha", Status: metav1.ConditionTrue, Reason: "Set"})
+			// Appending directly bypasses the deduplication of AddCondition
+			state.Conditions = append(state.Conditions, metav1.Condition{Type: "Zeta", Status: metav1.ConditionFalse, Reason: "Reset"})
+			return reconcile.Result{}, nil
+		},
+	})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+
+	var types []string
+	for _, c := range conditions {
+		types = append(types, c.Type)
+		assert.Equal(t, int64(7), c.ObservedGeneration, c.Type)
+	}
+	assert.Equal(t, []string{"Alpha", "Zeta"}, types)
+	assert.Equal(t, metav1.ConditionFalse, conditions[1].Status)
+}
+
+func TestConductStaleConditionPruning(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{
+		// Removed was registered by a previous version of the pipeline
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", Annotations: map[string]string{
+			ConditionOwnersAnnotation: "App,Removed",
+		}},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: "RemovedReconciled", Status: corev1.ConditionTrue, Reason: "Reconciled"},
+			{Type: "ConfigProgressing", Status: corev1.ConditionTrue, Reason: "Progressing"},
+			{Type: "AppError", Status: corev1.ConditionTrue, Reason: "ReconcileError"},
+			{Type: "LaterError", Status: corev1.ConditionTrue, Reason: "ReconcileError"},
+			{Type: "Ready", Status: corev1.ConditionTrue, Reason: "Ready"},
+			{Type: "DatabaseAvailable", Status: corev1.ConditionTrue, Reason: "Available"},
+			{Type: "CacheWarm", Status: corev1.ConditionTrue, Reason: "Warm"},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithObjects(pod).WithStatusSubresource(pod).Build()
+
+	cond := ForParent(pod).
+		WithClient(cli).
+		WithStatusConditionsHandler(PatchStatusConditions).
+		WithStaleConditionPruning(true).
+		Build()
+	cond.Register(&FuncReconciler{
+		Name: "App",
+		Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			RecordResult(ctx, "App", reconcile.Result{}, nil)
+			return reconcile.Result{}, nil
+		},
+	})
+	cond.Register(&DescribedReconciler{
+		FuncReconciler: FuncReconciler{Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		}},
+		Descriptor: api.Descriptor{Name: "Database", Conditions: []string{"DatabaseAvailable"}},
+	})
+	cond.Register(&ConditionReconciler{Details: api.Descriptor{
+		Name:               "Later",
+		RequiredConditions: []api.ConditionRequirement{{Type: "Never", Status: metav1.ConditionTrue}},
+		Conditions:         []string{"CacheWarm"},
+	}})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+
+	updated := &corev1.Pod{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), updated))
+	var types []string
+	for _, condition := range updated.Status.Conditions {
+		types = append(types, string(condition.Type))
+	}
+	// The conditions of the removed reconciler, the error of App and the declared condition not set by Database are
+	// pruned, the skipped reconciler and user conditions are kept, even following the convention
+	assert.Equal(t, []string{"AppReconciled", "CacheWarm", "ConfigProgressing", "LaterError", "LaterWaiting", "Ready"}, types)
+	assert.Equal(t, "App,Database,Later,Removed", updated.Annotations[ConditionOwnersAnnotation])
+
+	// Removed is forgotten once its conditions are pruned
+	_, err = cond.Conduct(ctx, updated)
+	require.NoError(t, err)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), updated))
+	assert.Equal(t, "App,Database,Later", updated.Annotations[ConditionOwnersAnnotation])
+}
+
+func TestConductConditionOwnersPrefix(t *testing.T) {
+	ctx := context.Background()
+	// App was removed, Application still writes conditions without being registered on this conductor
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", Annotations: map[string]string{
+			ConditionOwnersAnnotation: "App,Database",
+		}},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: "AppReconciled", Status: corev1.ConditionTrue, Reason: "Reconciled"},
+			{Type: "ApplicationReady", Status: corev1.ConditionTrue, Reason: "Ready"},
+			{Type: "ApplicationError", Status: corev1.ConditionTrue, Reason: "ReconcileError"},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithObjects(pod).WithStatusSubresource(pod).Build()
+
+	cond := ForParent(pod).
+		WithClient(cli).
+		WithStatusConditionsHandler(PatchStatusConditions).
+		WithStaleConditionPruning(true).
+		Build()
+	cond.Register(&FuncReconciler{
+		Name: "Database",
+		Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			RecordResult(ctx, "Database", reconcile.Result{}, nil)
+			return reconcile.Result{}, nil
+		},
+	})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	updated := &corev1.Pod{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), updated))
+	assert.Equal(t, "App,Database", updated.Annotations[ConditionOwnersAnnotation])
+
+	// App is forgotten once its conditions are pruned, although conditions of Application start with its name, and
+	// the conditions of Application are never pruned
+	_, err = cond.Conduct(ctx, updated)
+	require.NoError(t, err)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(pod), updated))
+	assert.Equal(t, "Database", updated.Annotations[ConditionOwnersAnnotation])
+	var types []string
+	for _, condition := range updated.Status.Conditions {
+		types = append(types, string(condition.Type))
+	}
+	assert.Equal(t, []string{"ApplicationError", "ApplicationReady", "DatabaseReconciled"}, types)
+}
+
+func TestRegisterGated(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	gates, err := ParseFeatureGates("Experimental=false, Stable=true")
+	require.NoError(t, err)
+	_, err = ParseFeatureGates("Experimental")
+	assert.Error(t, err)
+
+	var ran []string
+	record := func(name string) *FuncReconciler {
+		return &FuncReconciler{Name: name, Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			ran = append(ran, name)
+			return reconcile.Result{}, nil
+		}}
+	}
+	cond := ForParent(pod).WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).Build()
+	cond.Register(record("Always"))
+	cond.RegisterGated(record("Experimental"), gates.Enabled("Experimental"))
+	cond.RegisterGated(record("Stable"), gates.Enabled("Stable"))
+	cond.RegisterGated(record("Unknown"), gates.Enabled("Unknown"))
+
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Always", "Stable"}, ran)
+
+	// Gates are evaluated on every run
+	gates["Experimental"] = true
+	ran = nil
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Always", "Experimental", "Stable"}, ran)
+}
+
+func TestRegisterIf(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	var ran []string
+	record := func(name string) *FuncReconciler {
+		return &FuncReconciler{Name: name, Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			ran = append(ran, name)
+			return reconcile.Result{}, nil
+		}}
+	}
+	monitoring := func(parent *corev1.Pod) bool {
+		return parent.Annotations["monitoring"] == "enabled"
+	}
+	cond := ForParent(pod).WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).Build()
+	cond.Register(record("App"))
+	cond.RegisterIf(monitoring, record("ServiceMonitor"), record("Dashboard"))
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"App"}, ran)
+
+	ran = nil
+	pod.Annotations = map[string]string{"monitoring": "enabled"}
+	_, err = cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"App", "ServiceMonitor", "Dashboard"}, ran)
+}
+
+func TestConductPhases(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	for _, parallelism := range []int{0, 2} {
+		t.Run(fmt.Sprintf("parallelism=%d", parallelism), func(t *testing.T) {
+			var mu sync.Mutex
+			var ran []string
+			requeue := true
+			record := func(name string, dependsOn ...string) *FuncReconciler {
+				return &FuncReconciler{Name: name, DependsOn: dependsOn, Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					ran = append(ran, name)
+					if name == "CRDs" && requeue {
+						return reconcile.Result{RequeueAfter: time.Minute}, nil
+					}
+					return reconcile.Result{}, nil
+				}}
+			}
+			var conditions []metav1.Condition
+			cond := ForParent(pod).
+				WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).
+				WithAggregationPolicy(RunAllAndReturnMinRequeueAfter).
+				WithParallelism(parallelism).
+				WithStatusConditionsHandler(func(_ context.Context, _ client.Client, _ client.Object, c []metav1.Condition) error {
+					conditions = c
+					return nil
+				}).
+				Build()
+			cond.RegisterInPhase("prerequisites", record("CRDs"))
+			// Dependencies on reconcilers of earlier phases are satisfied
+			cond.RegisterInPhase("workloads", record("Deployment", "Config"), record("Service"))
+			// Phases run in the order they were first registered into
+			cond.RegisterInPhase("prerequisites", record("Config"))
+
+			statuses := func() map[string]string {
+				statuses := map[string]string{}
+				for _, c := range conditions {
+					statuses[c.Type] = string(c.Status) + "/" + c.Reason
+				}
+				return statuses
+			}
+
+			// The workloads wait for the prerequisites to complete
+			var skipped []string
+			for outcome, err := range cond.ConductSeq(ctx, pod) {
+				require.NoError(t, err)
+				if outcome.Skipped {
+					skipped = append(skipped, outcome.Phase+"/"+outcome.Descriptor.Name)
+				}
+			}
+			assert.ElementsMatch(t, []string{"CRDs", "Config"}, ran)
+			assert.Equal(t, []string{"workloads/Deployment", "workloads/Service"}, skipped)
+			assert.Equal(t, map[string]string{
+				"prerequisitesPhaseCompleted": "False/InProgress",
+				"workloadsPhaseCompleted":     "False/Pending",
+			}, statuses())
+
+			ran = nil
+			requeue = false
+			result, err := cond.Conduct(ctx, pod)
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{}, result)
+			require.Len(t, ran, 4)
+			assert.ElementsMatch(t, []string{"Deployment", "Service"}, ran[2:])
+			assert.Equal(t, map[string]string{
+				"prerequisitesPhaseCompleted": "True/Completed",
+				"workloadsPhaseCompleted":     "True/Completed",
+			}, statuses())
+		})
+	}
+}
+
+func TestConductMiddlewares(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	var calls []string
+	trace := func(label string) api.Middleware[*corev1.Pod] {
+		return func(next api.Reconciler[*corev1.Pod]) api.Reconciler[*corev1.Pod] {
+			return api.Decorate(next, func(ctx context.Context, c client.Client, parent *corev1.Pod) (reconcile.Result, error) {
+				calls = append(calls, label+">"+next.Describe().Name)
+				result, err := next.Reconcile(ctx, c, parent)
+				calls = append(calls, label+"<"+next.Describe().Name)
+				return result, err
+			})
+		}
+	}
+
+	cond := ForParent(pod).
+		WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).
+		WithMiddlewares(trace("outer")).
+		Build()
+	cond.Use(trace("inner"), LoggingMiddleware[*corev1.Pod]())
+	cond.Register(&FuncReconciler{Name: "App", Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+		calls = append(calls, "App")
+		return reconcile.Result{}, nil
+	}})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer>App", "inner>App", "App", "inner<App", "outer<App"}, calls)
+}
+
+func TestConductHooks(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	var ran, audit []string
+	record := func(name string) *FuncReconciler {
+		return &FuncReconciler{Name: name, Fn: func(context.Context, client.Client, *corev1.Pod) (reconcile.Result, error) {
+			ran = append(ran, name)
+			return reconcile.Result{}, nil
+		}}
+	}
+	cond := ForParent(pod).
+		WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).
+		WithPreReconcileHook(func(_ context.Context, _ *corev1.Pod, desc api.Descriptor) error {
+			switch desc.Name {
+			case "Skipped":
+				return ErrSkipReconciler
+			case "Denied":
+				return assert.AnError
+			}
+			return nil
+		}).
+		WithPostReconcileHook(func(_ context.Context, _ *corev1.Pod, desc api.Descriptor, _ reconcile.Result, err error) {
+			audit = append(audit, fmt.Sprintf("%s:%v", desc.Name, err != nil))
+		}).
+		Build()
+	cond.Register(record("Allowed"))
+	cond.Register(record("Skipped"))
+	cond.Register(record("Denied"))
+
+	_, err := cond.Conduct(ctx, pod)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, []string{"Allowed"}, ran)
+	assert.Equal(t, []string{"Allowed:false", "Skipped:false", "Denied:true"}, audit)
+}
+
+type contractReconciler struct {
+	FuncReconciler
+	contract Contract
+}
+
+func (c *contractReconciler) Contract() Contract {
+	return c.contract
+}
+
+func TestConductContracts(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	password := NewKey[string]("password")
+
+	producer := &contractReconciler{
+		FuncReconciler: FuncReconciler{Name: "Secret", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			return reconcile.Result{}, SetOutput(ctx, password, "s3cret")
+		}},
+		contract: Contract{Outputs: []Port{password}},
+	}
+	var consumed string
+	consumer := &contractReconciler{
+		FuncReconciler: FuncReconciler{Name: "Database", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+			var err error
+			consumed, err = GetInput(ctx, password)
+			return reconcile.Result{}, err
+		}},
+		contract: Contract{Inputs: []Port{password}},
+	}
+	newConductor := func() *Conductor[*corev1.Pod] {
+		return ForParent(pod).WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).Build()
+	}
+
+	cond := newConductor()
+	cond.Register(producer)
+	cond.Register(consumer)
+	require.NoError(t, cond.Validate())
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", consumed)
+
+	// Inputs must be produced earlier
+	cond = newConductor()
+	cond.Register(consumer)
+	cond.Register(producer)
+	require.ErrorIs(t, cond.Validate(), ErrContractViolation)
+	_, err = cond.Conduct(ctx, pod)
+	require.ErrorIs(t, err, ErrContractViolation)
+
+	// Phases are taken into account
+	cond = newConductor()
+	cond.RegisterInPhase("workloads", &FuncReconciler{Name: "Deployment"})
+	cond.RegisterInPhase("prerequisites", producer)
+	cond.RegisterInPhase("workloads", consumer)
+	require.ErrorIs(t, cond.Validate(), ErrContractViolation)
+
+	// Inputs must have the type of the output
+	cond = newConductor()
+	cond.Register(producer)
+	cond.Register(&contractReconciler{
+		FuncReconciler: FuncReconciler{Name: "Typed"},
+		contract:       Contract{Inputs: []Port{NewKey[[]byte]("password")}},
+	})
+	err = cond.Validate()
+	require.ErrorIs(t, err, ErrContractViolation)
+	assert.Contains(t, err.Error(), "[]uint8")
+}
+
+func TestConductContextPropagation(t *testing.T) {
+	type key struct{}
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.WithValue(context.Background(), key{}, "value"), deadline)
+	defer cancel()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	cond := ForParent(pod).WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).Build()
+	cond.Register(&FuncReconciler{Name: "Check", Fn: func(ctx context.Context, _ client.Client, _ *corev1.Pod) (reconcile.Result, error) {
+		got, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, deadline, got)
+		assert.Equal(t, "value", ctx.Value(key{}))
+		_, err := FetchState(ctx)
+		return reconcile.Result{}, err
+	}})
+
+	_, err := cond.Conduct(ctx, pod)
+	require.NoError(t, err)
+	// The State is bound to a derived context only
+	_, err = FetchState(ctx)
+	assert.Error(t, err)
+}
+
+func TestBuildValidated(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+
+	_, err := ForParent(pod).BuildValidated()
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+	assert.Panics(t, func() { ForParent(pod).MustBuild() })
+	_, err = ForParent(pod).Build().Conduct(context.Background(), pod)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+
+	cond, err := ForParent(pod).WithClient(fake.NewClientBuilder().WithObjects(pod).Build()).BuildValidated()
+	require.NoError(t, err)
+	require.NotNil(t, cond)
+}
+
+type validatingReconciler struct {
+	FuncReconciler
+	err error
+}
+
+func (v *validatingReconciler) Validate() error {
+	return v.err
+}
+
+func TestRegisterValidation(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	newConductor := func() *Conductor[*corev1.Pod] {
+		return ForParent(pod).WithClient(fake.NewClientBuilder().Build()).Build()
+	}
+
+	cond := newConductor()
+	cond.Register(&validatingReconciler{FuncReconciler: FuncReconciler{Name: "valid"}})
+	cond.Register(&ChildReconciler{FuncReconciler: FuncReconciler{Name: "configs"}, Objects: []client.Object{&corev1.ConfigMap{}}})
+	require.NoError(t, cond.Validate())
+
+	// Validator errors are reported
+	cond = newConductor()
+	cond.Register(&validatingReconciler{FuncReconciler: FuncReconciler{Name: "invalid"}, err: reconciler.ErrInvalidConfiguration})
+	require.ErrorIs(t, cond.Validate(), reconciler.ErrInvalidConfiguration)
+	_, err := cond.Conduct(context.Background(), pod)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+
+	// Names must be unique
+	cond = newConductor()
+	cond.Register(&FuncReconciler{Name: "configs"})
+	cond.Register(&FuncReconciler{Name: "configs"})
+	require.ErrorContains(t, cond.Validate(), `duplicate reconciler name "configs"`)
+
+	// Declared conditions must be unique and stabilities known
+	cond = newConductor()
+	cond.Register(&DescribedReconciler{Descriptor: api.Descriptor{Name: "a", Conditions: []string{"Available"}, Stability: api.StabilityBeta}})
+	cond.Register(&DescribedReconciler{Descriptor: api.Descriptor{Name: "b", Conditions: []string{"Available"}}})
+	require.ErrorContains(t, cond.Validate(), `condition "Available" declared by both a and b`)
+	cond = newConductor()
+	cond.Register(&DescribedReconciler{Descriptor: api.Descriptor{Name: "a", Stability: "Experimental"}})
+	require.ErrorContains(t, cond.Validate(), `a has an unknown stability "Experimental"`)
+
+	// Children must be registered in the scheme
+	cond = ForParent(pod).WithClient(fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()).Build()
+	cond.Register(&ChildReconciler{FuncReconciler: FuncReconciler{Name: "configs"}, Objects: []client.Object{&corev1.ConfigMap{}}})
+	require.ErrorIs(t, cond.Validate(), reconciler.ErrInvalidConfiguration)
+}
+
+// TypedReconciler stands for a reconciler managing children it doesn't own.
+type TypedReconciler struct {
+	FuncReconciler
+	Types []client.Object
+}
+
+func (t *TypedReconciler) ChildTypes() []client.Object {
+	return t.Types
+}
+
+func TestValidateScheme(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	cond := ForParent(pod).WithClient(fake.NewClientBuilder().Build()).Build()
+	cond.Register(&ChildReconciler{FuncReconciler: FuncReconciler{Name: "configs"}, Objects: []client.Object{&corev1.ConfigMap{}}})
+	cond.Register(&TypedReconciler{FuncReconciler: FuncReconciler{Name: "roles"}, Types: []client.Object{&rbacv1.ClusterRole{}}})
+	require.NoError(t, cond.Validate())
+
+	// Every missing type is reported
+	s := runtime.NewScheme()
+	require.NoError(t, rbacv1.AddToScheme(s))
+	err := cond.ValidateScheme(s)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+	assert.ErrorContains(t, err, "the parent *v1.Pod is not registered in the scheme")
+	assert.ErrorContains(t, err, "configs manages *v1.ConfigMap, not registered in the scheme")
+	assert.NotContains(t, err.Error(), "ClusterRole")
+
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, cond.ValidateScheme(s))
+
+	// Children not owned by the parent are checked at registration too
+	s = runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	cond = ForParent(pod).WithClient(fake.NewClientBuilder().WithScheme(s).Build()).Build()
+	cond.Register(&TypedReconciler{FuncReconciler: FuncReconciler{Name: "roles"}, Types: []client.Object{&rbacv1.ClusterRole{}}})
+	require.ErrorContains(t, cond.Validate(), "roles manages *v1.ClusterRole, not registered in the scheme")
+}
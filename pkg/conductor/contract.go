@@ -0,0 +1,140 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ethan-gallant/maestro/api"
+)
+
+// ErrContractViolation is returned when a reconciler requires an input no earlier reconciler produces.
+var ErrContractViolation = errors.New("reconciler contract violation")
+
+// Port is a typed value produced or consumed by reconcilers, see Key.
+type Port interface {
+	// Name is the name of the value.
+	Name() string
+	// Type is the type of the value.
+	Type() reflect.Type
+}
+
+// Key identifies a typed value passed between reconcilers.
+type Key[T any] struct {
+	name string
+}
+
+var _ Port = Key[any]{}
+
+// NewKey returns the key of a value of type T passed between reconcilers.
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns the name of the key.
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Type returns the type of the values of the key.
+func (k Key[T]) Type() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// Contract declares the values a reconciler consumes and produces.
+type Contract struct {
+	// Inputs are the values the reconciler requires, produced by earlier reconcilers.
+	Inputs []Port
+	// Outputs are the values the reconciler produces with SetOutput.
+	Outputs []Port
+}
+
+// ContractDescriber is implemented by reconcilers declaring a Contract. The conductor checks at registration that every
+// input is produced by a reconciler running earlier: registered before it in the same phase, or in an earlier phase.
+type ContractDescriber interface {
+	Contract() Contract
+}
+
+// SetOutput publishes the value of an output of the reconciler in the State bound to the context.
+func SetOutput[T any](ctx context.Context, key Key[T], value T) error {
+	state, err := FetchState(ctx)
+	if err != nil {
+		return err
+	}
+	SetValue(state, portValueKey(key), value)
+	return nil
+}
+
+// GetInput returns the value of an input of the reconciler, published by an earlier reconciler with SetOutput. It fails
+// if the value was not published, e.g. because the producer is disabled or returned early.
+func GetInput[T any](ctx context.Context, key Key[T]) (T, error) {
+	state, err := FetchState(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value, ok := GetValue[T](state, portValueKey(key))
+	if !ok {
+		return value, fmt.Errorf("input %q was not produced", key.Name())
+	}
+	return value, nil
+}
+
+// portValueKey returns the key of the value of a port in the State.
+func portValueKey(port Port) string {
+	return "maestro.io/port/" + port.Name()
+}
+
+// producer is a reconciler producing an output.
+type producer struct {
+	reconciler string
+	phase      string
+	typ        reflect.Type
+}
+
+// checkContract checks the inputs of a reconciler registered in the phase against the outputs of the reconcilers
+// registered before it, then records its outputs. Violations are kept for Validate.
+func (d *Conductor[Parent]) checkContract(phase string, reconciler api.Reconciler[Parent]) {
+	describer, ok := reconciler.(ContractDescriber)
+	if !ok {
+		return
+	}
+	contract := describer.Contract()
+	name := reconciler.Describe().Name
+	for _, input := range contract.Inputs {
+		produced, ok := d.outputs[input.Name()]
+		switch {
+		case !ok:
+			d.errs = append(d.errs, fmt.Errorf("%w: %s requires %q, not produced by an earlier reconciler",
+				ErrContractViolation, name, input.Name()))
+		case produced.typ != input.Type():
+			d.errs = append(d.errs, fmt.Errorf("%w: %s requires %q of type %s, produced as %s by %s",
+				ErrContractViolation, name, input.Name(), input.Type(), produced.typ, produced.reconciler))
+		case d.phaseIndex(produced.phase) > d.phaseIndex(phase):
+			d.errs = append(d.errs, fmt.Errorf("%w: %s requires %q, produced by %s in the later phase %q",
+				ErrContractViolation, name, input.Name(), produced.reconciler, produced.phase))
+		}
+	}
+
+	if d.outputs == nil {
+		d.outputs = map[string]producer{}
+	}
+	for _, output := range contract.Outputs {
+		if _, ok := d.outputs[output.Name()]; !ok {
+			d.outputs[output.Name()] = producer{reconciler: name, phase: phase, typ: output.Type()}
+		}
+	}
+}
+
+// phaseIndex returns the position of the phase in the run order, the order in which phases were first registered into.
+func (d *Conductor[Parent]) phaseIndex(phase string) int {
+	seen := map[string]bool{}
+	for _, name := range d.phases {
+		if name == phase {
+			return len(seen)
+		}
+		seen[name] = true
+	}
+	return len(seen)
+}
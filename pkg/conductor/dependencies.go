@@ -0,0 +1,34 @@
+package conductor
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/pkg/binder"
+)
+
+// Dependency binds a value into the context of every Conduct run.
+type Dependency func(ctx context.Context) (context.Context, error)
+
+// Provide returns a Dependency that binds the value by its type, to be retrieved by reconcilers with Inject.
+// Only one value per type can be provided; group related dependencies (clients, clock, feature gates) in a struct.
+func Provide[T any](value *T) Dependency {
+	return func(ctx context.Context) (context.Context, error) {
+		return (&binder.StaticBindable[T]{}).BindToContext(ctx, value)
+	}
+}
+
+// Inject returns the value of type T provided to the conductor.
+func Inject[T any](ctx context.Context) (*T, error) {
+	return (&binder.StaticBindable[T]{}).FromContext(ctx)
+}
+
+// bindDependencies binds every dependency into the context.
+func (d *Conductor[Parent]) bindDependencies(ctx context.Context) (context.Context, error) {
+	for _, dependency := range d.dependencies {
+		var err error
+		if ctx, err = dependency(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
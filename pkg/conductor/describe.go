@@ -0,0 +1,126 @@
+package conductor
+
+import (
+	"slices"
+
+	"github.com/ethan-gallant/maestro/api"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PipelineDescription describes the pipeline of a conductor, as returned by DescribePipeline.
+type PipelineDescription struct {
+	// Parent is the kind of the parent.
+	Parent schema.GroupVersionKind
+	// Reconcilers are the registered reconcilers, in registration order.
+	Reconcilers []ReconcilerDescription
+	// Conditions are the condition Types the conductor can add to the parent for the whole pipeline, such as the ready
+	// condition and the conditions of the references and phases.
+	Conditions []string
+}
+
+// ReconcilerDescription describes a registered reconciler.
+type ReconcilerDescription struct {
+	api.Descriptor
+	// Phase is the phase of the reconciler, see RegisterInPhase.
+	Phase string
+	// Children are the kinds of the children of the reconciler: the ChildGVKs of its Descriptor, followed by the types
+	// returned by its ChildTypes or Children resolved through the scheme.
+	Children []schema.GroupVersionKind
+	// Conditions are the condition Types the reconciler and the conductor can add to the parent for the reconciler,
+	// including those declared in the Descriptor and through api.ConditionDescriber.
+	Conditions []string
+}
+
+// DescribePipeline describes the registered reconcilers and the conditions the pipeline can produce, resolving the
+// kinds of the parent and the children through the scheme. It is used to document the pipeline, see the docgen
+// package.
+func (d *Conductor[Parent]) DescribePipeline(scheme *runtime.Scheme) (PipelineDescription, error) {
+	parent, err := apiutil.GVKForObject(d.parent, scheme)
+	if err != nil {
+		return PipelineDescription{}, err
+	}
+	desc := PipelineDescription{Parent: parent}
+
+	if d.readyCondition != "" {
+		desc.Conditions = append(desc.Conditions, d.readyCondition)
+	}
+	for _, ref := range d.references {
+		desc.Conditions = append(desc.Conditions, ref.Name+referenceConditionSuffix)
+	}
+	for i, r := range d.reconcilers {
+		phase := d.phases[i]
+		if phase != DefaultPhase && !slices.Contains(desc.Conditions, phase+phaseConditionSuffix) {
+			desc.Conditions = append(desc.Conditions, phase+phaseConditionSuffix)
+		}
+		children, err := reconcilerChildren(r, scheme)
+		if err != nil {
+			return PipelineDescription{}, err
+		}
+		desc.Reconcilers = append(desc.Reconcilers, ReconcilerDescription{
+			Descriptor: r.Describe(),
+			Phase:      phase,
+			Children:   children,
+			Conditions: d.reconcilerConditions(r),
+		})
+	}
+	return desc, nil
+}
+
+// reconcilerChildren returns the kinds of the children of the reconciler, without duplicates.
+func reconcilerChildren[Parent client.Object](r api.Reconciler[Parent], scheme *runtime.Scheme) ([]schema.GroupVersionKind, error) {
+	gvks := slices.Clone(r.Describe().ChildGVKs)
+	var children []client.Object
+	if describer, ok := r.(api.ChildTypeDescriber); ok {
+		children = describer.ChildTypes()
+	} else if describer, ok := r.(api.ChildDescriber); ok {
+		children = describer.Children()
+	}
+	for _, child := range children {
+		// The kind of unstructured children is only known once they are built, see api.Descriptor.ChildGVKs.
+		if _, ok := child.(runtime.Unstructured); ok {
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(child, scheme)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(gvks, gvk) {
+			gvks = append(gvks, gvk)
+		}
+	}
+	return gvks, nil
+}
+
+// reconcilerConditions returns the condition Types that can be added to the parent for the reconciler: those declared
+// in its Descriptor and by the reconciler, followed by those of the conductor depending on its configuration.
+func (d *Conductor[Parent]) reconcilerConditions(r api.Reconciler[Parent]) []string {
+	desc := r.Describe()
+	conditions := slices.Clone(desc.Conditions)
+	if describer, ok := r.(api.ConditionDescriber); ok {
+		conditions = append(conditions, describer.Conditions()...)
+	}
+	if len(desc.RequiredConditions) > 0 {
+		conditions = append(conditions, desc.Name+"Waiting")
+	}
+	if len(desc.RequiredAPIs) > 0 {
+		conditions = append(conditions, desc.Name+"Unavailable")
+	}
+	if d.reconcilerTimeout > 0 {
+		conditions = append(conditions, desc.Name+"TimedOut")
+	}
+	if d.apiWarnings {
+		conditions = append(conditions, desc.Name+apiWarningConditionSuffix)
+	}
+	conditions = append(conditions, desc.Name+"Panicked")
+
+	var unique []string
+	for _, condition := range conditions {
+		if !slices.Contains(unique, condition) {
+			unique = append(unique, condition)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,105 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultAPIRecheckInterval is the duration after which an API found missing is looked up again.
+const DefaultAPIRecheckInterval = 5 * time.Minute
+
+// APIAvailability remembers which kinds the API server serves, looked up through a REST mapper, so the RequiredAPIs
+// of the reconcilers aren't discovered on every run. Missing kinds are looked up again after the RecheckInterval, so
+// a CRD installed later is picked up. It should be shared across runs. The zero value is ready to use.
+type APIAvailability struct {
+	// RecheckInterval is the duration after which a missing kind is looked up again. Defaults to
+	// DefaultAPIRecheckInterval.
+	RecheckInterval time.Duration
+
+	mu      sync.Mutex
+	missing map[schema.GroupVersionKind]time.Time
+}
+
+// NewAPIAvailability returns an APIAvailability looking up missing kinds again after the interval.
+func NewAPIAvailability(recheckInterval time.Duration) *APIAvailability {
+	return &APIAvailability{RecheckInterval: recheckInterval}
+}
+
+// Available returns whether the kind is served, as of the last lookup through the mapper for missing kinds. Served
+// kinds are left to the cache of the mapper.
+func (a *APIAvailability) Available(mapper meta.RESTMapper, gvk schema.GroupVersionKind, now time.Time) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if checked, ok := a.missing[gvk]; ok && now.Sub(checked) < a.recheckInterval() {
+		return false, nil
+	}
+
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		if a.missing == nil {
+			a.missing = map[schema.GroupVersionKind]time.Time{}
+		}
+		a.missing[gvk] = now
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	delete(a.missing, gvk)
+	return true, nil
+}
+
+func (a *APIAvailability) recheckInterval() time.Duration {
+	if a.RecheckInterval <= 0 {
+		return DefaultAPIRecheckInterval
+	}
+	return a.RecheckInterval
+}
+
+// apisAvailable checks the RequiredAPIs of the descriptor. When one isn't served, an Unavailable condition is added to
+// the State for the reconciler and a resync is requested, to check again once the APIs may be installed.
+func (d *Conductor[Parent]) apisAvailable(ctx context.Context, desc api.Descriptor) (bool, error) {
+	if len(desc.RequiredAPIs) == 0 {
+		return true, nil
+	}
+	availability := d.apis
+	if availability == nil {
+		availability = &APIAvailability{}
+	}
+
+	var missing []string
+	for _, gvk := range desc.RequiredAPIs {
+		available, err := availability.Available(d.client.RESTMapper(), gvk, time.Now())
+		if err != nil {
+			return false, err
+		}
+		if !available {
+			missing = append(missing, gvk.String())
+		}
+	}
+	if len(missing) == 0 {
+		return true, nil
+	}
+
+	d.logger(ctx).V(1).Info("skipping reconciler, required APIs not served", "reconciler", desc.Name, "missing", missing)
+	if state, err := FetchState(ctx); err == nil {
+		state.AddCondition(metav1.Condition{
+			Type:    fmt.Sprintf("%sUnavailable", desc.Name),
+			Status:  metav1.ConditionTrue,
+			Reason:  "APINotServed",
+			Message: fmt.Sprintf("The API server doesn't serve %s", strings.Join(missing, ", ")),
+			LastTransitionTime: metav1.Time{
+				Time: time.Now(),
+			},
+		})
+	}
+	RequestResync(ctx, availability.recheckInterval())
+	return false, nil
+}
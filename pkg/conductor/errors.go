@@ -0,0 +1,55 @@
+package conductor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileError is an error returned by a reconciler run by the conductor, with the context attributing it: the
+// Descriptor name of the reconciler, the key of the parent and, if the reconciler reported it with
+// reconciler.WithChildKey, the key of the child. It wraps the error of the reconciler.
+type ReconcileError struct {
+	Reconciler string
+	Parent     client.ObjectKey
+	// Child is the zero key if the child is unknown.
+	Child client.ObjectKey
+	Err   error
+}
+
+func (e *ReconcileError) Error() string {
+	if e.Child.Name != "" {
+		return fmt.Sprintf("reconciler %s (parent %s, child %s): %v", e.Reconciler, e.Parent, e.Child, e.Err)
+	}
+	return fmt.Sprintf("reconciler %s (parent %s): %v", e.Reconciler, e.Parent, e.Err)
+}
+
+func (e *ReconcileError) Unwrap() error {
+	return e.Err
+}
+
+// wrapError wraps the error of the named reconciler in a ReconcileError, unless nil or already wrapped, e.g. by the
+// conductor of a nested pipeline.
+func (d *Conductor[Parent]) wrapError(name string, err error) error {
+	var reconcileErr *ReconcileError
+	if err == nil || errors.As(err, &reconcileErr) {
+		return err
+	}
+	wrapped := &ReconcileError{Reconciler: name, Parent: client.ObjectKeyFromObject(d.parent), Err: err}
+	var childErr *reconciler.ChildError
+	if errors.As(err, &childErr) {
+		wrapped.Child = childErr.Child
+	}
+	return wrapped
+}
+
+// errorMessage returns the message of the error, without the context of a ReconcileError, for the conditions of the
+// parent.
+func errorMessage(err error) string {
+	if reconcileErr, ok := err.(*ReconcileError); ok {
+		return reconcileErr.Err.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,40 @@
+package conductor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FeatureGates enables reconcilers by feature name, for staged rollouts of new reconcilers with RegisterGated.
+// Features not listed are disabled.
+type FeatureGates map[string]bool
+
+// ParseFeatureGates parses feature gates in the format of the --feature-gates flag of Kubernetes components, e.g.
+// "NewChild=true,Legacy=false", as read from a flag or an environment variable. An empty string enables nothing.
+func ParseFeatureGates(value string) (FeatureGates, error) {
+	gates := FeatureGates{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, enabled, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid feature gate %q: expected <name>=<bool>", entry)
+		}
+		parsed, err := strconv.ParseBool(strings.TrimSpace(enabled))
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature gate %q: %w", entry, err)
+		}
+		gates[strings.TrimSpace(name)] = parsed
+	}
+	return gates, nil
+}
+
+// Enabled returns the gate of the named feature, to be passed to RegisterGated.
+func (g FeatureGates) Enabled(feature string) func() bool {
+	return func() bool {
+		return g[feature]
+	}
+}
@@ -0,0 +1,72 @@
+package conductor
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ensureFinalizer adds the finalizer to the parent if it's missing.
+func (d *Conductor[Parent]) ensureFinalizer(ctx context.Context) error {
+	if controllerutil.ContainsFinalizer(d.parent, d.finalizer) {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(d.parent.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(d.parent, d.finalizer)
+	if err := d.client.Patch(ctx, d.parent, patch); err != nil {
+		return err
+	}
+	d.logger(ctx).V(1).Info("added finalizer", "parent", client.ObjectKeyFromObject(d.parent), "finalizer", d.finalizer)
+	return nil
+}
+
+// finalize runs the Finalize hook of every enabled reconciler in reverse registration order, followed by its Cleanup
+// hook with the teardown sequence, then removes the finalizer from the parent once all of them completed.
+func (d *Conductor[Parent]) finalize(ctx context.Context, reconcilers []api.Reconciler[Parent], emit func(ReconcilerOutcome, error) bool) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(d.parent, d.finalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	for i := len(reconcilers) - 1; i >= 0; i-- {
+		var hooks []api.ReconcileFunc[Parent]
+		if finalizer, ok := reconcilers[i].(api.Finalizer[Parent]); ok {
+			hooks = append(hooks, finalizer.Finalize)
+		}
+		if cleaner, ok := reconcilers[i].(api.Cleaner[Parent]); ok && d.teardown {
+			hooks = append(hooks, cleaner.Cleanup)
+		}
+
+		desc := reconcilers[i].Describe()
+		for _, hook := range hooks {
+			start := time.Now()
+			result, err := d.runFinalizer(ctx, desc.Name, hook)
+			outcome := ReconcilerOutcome{Descriptor: desc, Result: result, Duration: time.Since(start)}
+			if emit != nil && !emit(outcome, err) {
+				return result, err
+			}
+			if shouldReturn(result, err) {
+				return result, err
+			}
+		}
+	}
+
+	patch := client.MergeFromWithOptions(d.parent.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(d.parent, d.finalizer)
+	if err := d.client.Patch(ctx, d.parent, patch); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	d.logger(ctx).V(1).Info("removed finalizer", "parent", client.ObjectKeyFromObject(d.parent), "finalizer", d.finalizer)
+	return reconcile.Result{}, nil
+}
+
+// runFinalizer invokes a Finalize or Cleanup hook of the named reconciler, recovering its panics like Reconcile.
+func (d *Conductor[Parent]) runFinalizer(ctx context.Context, name string, hook api.ReconcileFunc[Parent]) (result reconcile.Result, err error) {
+	defer d.planner.attribute(name)()
+	defer recoverPanic(ctx, name, &err)
+	return hook(ctx, d.client, d.parent)
+}
@@ -0,0 +1,54 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// requirementsMet checks the RequiredConditions of the descriptor against the conditions added to the State during
+// this run, falling back to the conditions on the parent status. When a requirement is not met, a Waiting condition
+// is added to the State for the reconciler.
+func (d *Conductor[Parent]) requirementsMet(ctx context.Context, state *State, desc api.Descriptor) (bool, error) {
+	if len(desc.RequiredConditions) == 0 {
+		return true, nil
+	}
+
+	parentConditions, err := reconciler.ConditionsFromObject(d.parent)
+	if err != nil {
+		return false, err
+	}
+
+	var unmet []string
+	for _, requirement := range desc.RequiredConditions {
+		condition := state.FindCondition(requirement.Type)
+		if condition == nil {
+			condition = meta.FindStatusCondition(parentConditions, requirement.Type)
+		}
+		if condition == nil || condition.Status != requirement.Status {
+			unmet = append(unmet, fmt.Sprintf("%s=%s", requirement.Type, requirement.Status))
+		}
+	}
+
+	if len(unmet) == 0 {
+		return true, nil
+	}
+
+	d.logger(ctx).V(1).Info("skipping reconciler, required conditions not met", "reconciler", desc.Name, "unmet", unmet)
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sWaiting", desc.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "RequiredConditionsNotMet",
+		Message: fmt.Sprintf("Waiting for conditions: %s", strings.Join(unmet, ", ")),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+	return false, nil
+}
@@ -0,0 +1,49 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ErrSkipReconciler is returned by a PreReconcileHook to skip the reconciler without failing the run.
+var ErrSkipReconciler = errors.New("reconciler skipped by hook")
+
+// PreReconcileHook is called before each reconciler. Returning ErrSkipReconciler skips the reconciler, any other
+// error fails it without running it.
+type PreReconcileHook[Parent any] func(ctx context.Context, parent Parent, desc api.Descriptor) error
+
+// PostReconcileHook is called after each reconciler with its result and error, including the errors of the
+// PreReconcileHooks.
+type PostReconcileHook[Parent any] func(ctx context.Context, parent Parent, desc api.Descriptor, result reconcile.Result, err error)
+
+// runHooked runs the reconciler between the pre and post reconcile hooks.
+func (d *Conductor[Parent]) runHooked(ctx context.Context, reconciler api.Reconciler[Parent]) (reconcile.Result, error) {
+	if len(d.preHooks) == 0 && len(d.postHooks) == 0 {
+		return reconciler.Reconcile(ctx, d.client, d.parent)
+	}
+
+	desc := reconciler.Describe()
+	result, err := d.runPreHooks(ctx, desc)
+	if err == nil {
+		result, err = reconciler.Reconcile(ctx, d.client, d.parent)
+	} else if errors.Is(err, ErrSkipReconciler) {
+		err = nil
+	}
+	for _, hook := range d.postHooks {
+		hook(ctx, d.parent, desc, result, err)
+	}
+	return result, err
+}
+
+// runPreHooks runs the pre reconcile hooks in order, stopping at the first error.
+func (d *Conductor[Parent]) runPreHooks(ctx context.Context, desc api.Descriptor) (reconcile.Result, error) {
+	for _, hook := range d.preHooks {
+		if err := hook(ctx, d.parent, desc); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
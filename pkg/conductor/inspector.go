@@ -0,0 +1,198 @@
+package conductor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultInspectorMaxParents is the number of parents an Inspector keeps the state of per pipeline when MaxParents is
+// not set.
+const DefaultInspectorMaxParents = 1000
+
+// Inspector records the last run of the reconcilers of the conductors built with WithInspector, and serves it as JSON
+// over HTTP to debug why a child isn't updated without going through the logs. It is typically mounted on the metrics
+// server of the manager:
+//
+//	inspector := conductor.NewInspector()
+//	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Metrics: metricsserver.Options{
+//		ExtraHandlers: map[string]http.Handler{"/debug/maestro": inspector},
+//	}})
+//
+// The state of each parent is kept in memory until MaxParents parents of the pipeline were reconciled more recently.
+type Inspector struct {
+	// MaxParents is the number of parents kept per pipeline, the least recently reconciled being dropped first.
+	// Defaults to DefaultInspectorMaxParents.
+	MaxParents int
+
+	mu        sync.Mutex
+	pipelines map[string]*pipelineRecord
+}
+
+// NewInspector returns an empty Inspector.
+func NewInspector() *Inspector {
+	return &Inspector{pipelines: map[string]*pipelineRecord{}}
+}
+
+// PipelineStatus is the state of the pipeline of the conductors reconciling a kind of parent, as served by the
+// Inspector.
+type PipelineStatus struct {
+	// Parent is the kind of the parent.
+	Parent      string           `json:"parent"`
+	Reconcilers []api.Descriptor `json:"reconcilers"`
+	// Parents are the last runs of the reconcilers, by parent key then reconciler name.
+	Parents map[string]map[string]ReconcilerStatus `json:"parents"`
+}
+
+// ReconcilerStatus is the last run of a reconciler for a parent.
+type ReconcilerStatus struct {
+	Time         time.Time `json:"time"`
+	Duration     string    `json:"duration"`
+	Requeue      bool      `json:"requeue,omitempty"`
+	RequeueAfter string    `json:"requeueAfter,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	// LastAction is the last operation recorded by the reconciler on a child, which may be older than the run.
+	LastAction *ActionStatus `json:"lastAction,omitempty"`
+}
+
+// ActionStatus is an operation performed by a reconciler on a child, see RecordOperation.
+type ActionStatus struct {
+	Time      time.Time `json:"time"`
+	Operation Operation `json:"operation"`
+	// Child is the kind and key of the child, empty for resources outside the cluster.
+	Child string `json:"child,omitempty"`
+}
+
+type pipelineRecord struct {
+	reconcilers []api.Descriptor
+	parents     map[string]*parentRecord
+}
+
+type parentRecord struct {
+	seen        time.Time
+	reconcilers map[string]ReconcilerStatus
+}
+
+// Pipelines returns the state of the pipelines, sorted by parent kind.
+func (i *Inspector) Pipelines() []PipelineStatus {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	pipelines := make([]PipelineStatus, 0, len(i.pipelines))
+	for kind, pipeline := range i.pipelines {
+		status := PipelineStatus{
+			Parent:      kind,
+			Reconcilers: pipeline.reconcilers,
+			Parents:     make(map[string]map[string]ReconcilerStatus, len(pipeline.parents)),
+		}
+		for key, parent := range pipeline.parents {
+			reconcilers := make(map[string]ReconcilerStatus, len(parent.reconcilers))
+			for name, reconciler := range parent.reconcilers {
+				reconcilers[name] = reconciler
+			}
+			status.Parents[key] = reconcilers
+		}
+		pipelines = append(pipelines, status)
+	}
+	sort.Slice(pipelines, func(a, b int) bool {
+		return pipelines[a].Parent < pipelines[b].Parent
+	})
+	return pipelines
+}
+
+// ServeHTTP writes the Pipelines as JSON.
+func (i *Inspector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(i.Pipelines()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// recordRun records the result of a run of a reconciler, keeping its last action.
+func (i *Inspector) recordRun(kind string, descriptors []api.Descriptor, parent client.ObjectKey, name string,
+	start time.Time, result reconcile.Result, err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	record := i.parent(kind, parent, start)
+	i.pipelines[kind].reconcilers = descriptors
+	status := ReconcilerStatus{
+		Time:       start,
+		Duration:   time.Since(start).String(),
+		Requeue:    result.Requeue,
+		LastAction: record.reconcilers[name].LastAction,
+	}
+	if result.RequeueAfter > 0 {
+		status.RequeueAfter = result.RequeueAfter.String()
+	}
+	if err != nil {
+		status.Error = errorMessage(err)
+	}
+	record.reconcilers[name] = status
+}
+
+// recordAction records an operation of a reconciler on a child as its last action.
+func (i *Inspector) recordAction(kind string, parent client.ObjectKey, name string, action ActionStatus) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	record := i.parent(kind, parent, action.Time)
+	status := record.reconcilers[name]
+	status.LastAction = &action
+	record.reconcilers[name] = status
+}
+
+// parent returns the record of the parent, creating it and dropping the least recently seen parent of the pipeline
+// past MaxParents. The caller must hold the lock.
+func (i *Inspector) parent(kind string, key client.ObjectKey, now time.Time) *parentRecord {
+	if i.pipelines == nil {
+		i.pipelines = map[string]*pipelineRecord{}
+	}
+	pipeline, ok := i.pipelines[kind]
+	if !ok {
+		pipeline = &pipelineRecord{parents: map[string]*parentRecord{}}
+		i.pipelines[kind] = pipeline
+	}
+	record, ok := pipeline.parents[key.String()]
+	if !ok {
+		record = &parentRecord{seen: now, reconcilers: map[string]ReconcilerStatus{}}
+		pipeline.parents[key.String()] = record
+		i.evict(pipeline)
+	}
+	record.seen = now
+	return record
+}
+
+// evict drops the least recently seen parents of the pipeline past MaxParents.
+func (i *Inspector) evict(pipeline *pipelineRecord) {
+	maxParents := i.MaxParents
+	if maxParents <= 0 {
+		maxParents = DefaultInspectorMaxParents
+	}
+	for len(pipeline.parents) > maxParents {
+		var oldest string
+		for key, parent := range pipeline.parents {
+			if oldest == "" || parent.seen.Before(pipeline.parents[oldest].seen) {
+				oldest = key
+			}
+		}
+		delete(pipeline.parents, oldest)
+	}
+}
+
+// inspect records the run of the reconciler in the Inspector of the conductor, if any.
+func (d *Conductor[Parent]) inspect(name string, start time.Time, result reconcile.Result, err error) {
+	if d.inspector == nil {
+		return
+	}
+	d.inspector.recordRun(objectKind(d.parent, d.client.Scheme()), d.Reconcilers(), client.ObjectKeyFromObject(d.parent),
+		name, start, result, err)
+}
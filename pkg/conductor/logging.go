@@ -0,0 +1,93 @@
+package conductor
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type reconcilerLoggerKey struct{}
+
+// runLoggerKey marks the contexts of a run of a conductor, whose logger was set by the conductor.
+type runLoggerKey struct{}
+
+// logger returns the logger set with WithLogger, or else the logger of the context, e.g. the one controller-runtime
+// passes to Reconcile. Within a run, the logger of the context derives from the former.
+func (d *Conductor[Parent]) logger(ctx context.Context) logr.Logger {
+	if d.log.GetSink() == nil || ctx.Value(runLoggerKey{}) == any(d) {
+		return klog.FromContext(ctx)
+	}
+	return d.log
+}
+
+// withRunLogger returns a context with the logger of the run.
+func (d *Conductor[Parent]) withRunLogger(ctx context.Context, log logr.Logger) context.Context {
+	return context.WithValue(klog.NewContext(ctx, log), runLoggerKey{}, any(d))
+}
+
+// withReconcilerLogger returns a context whose logger has the name of the reconciler and the key of the parent, so
+// the log lines of the reconciler are attributable without it adding them. The verbosity of the logger is shifted as
+// set with WithReconcilerVerbosity.
+func (d *Conductor[Parent]) withReconcilerLogger(ctx context.Context, name string) context.Context {
+	log := d.logger(ctx).WithValues("reconciler", name, "parent", client.ObjectKeyFromObject(d.parent))
+	if shift := d.verbosity[name]; shift != 0 && log.GetSink() != nil {
+		log = logr.New(&shiftedSink{sink: log.GetSink(), shift: shift})
+	}
+	ctx = klog.NewContext(ctx, log)
+	return context.WithValue(ctx, reconcilerLoggerKey{}, name)
+}
+
+// Logger returns the logger of the context with the name of the reconciler and the key of the parent. Within a
+// conductor, they were added to the logger of the context before the reconciler was called, and are not added again.
+func Logger(ctx context.Context, name string, parent client.Object) logr.Logger {
+	log := klog.FromContext(ctx)
+	if injected, ok := ctx.Value(reconcilerLoggerKey{}).(string); ok && injected == name {
+		return log
+	}
+	return log.WithValues("reconciler", name, "parent", client.ObjectKeyFromObject(parent))
+}
+
+// shiftedSink lowers the level of the log lines written to the sink by shift, or raises it for a negative shift.
+type shiftedSink struct {
+	sink  logr.LogSink
+	shift int
+}
+
+var _ logr.CallDepthLogSink = &shiftedSink{}
+
+func (s *shiftedSink) level(level int) int {
+	return max(level-s.shift, 0)
+}
+
+func (s *shiftedSink) Init(info logr.RuntimeInfo) {
+	s.sink.Init(info)
+}
+
+func (s *shiftedSink) Enabled(level int) bool {
+	return s.sink.Enabled(s.level(level))
+}
+
+func (s *shiftedSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(s.level(level), msg, keysAndValues...)
+}
+
+func (s *shiftedSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *shiftedSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &shiftedSink{sink: s.sink.WithValues(keysAndValues...), shift: s.shift}
+}
+
+func (s *shiftedSink) WithName(name string) logr.LogSink {
+	return &shiftedSink{sink: s.sink.WithName(name), shift: s.shift}
+}
+
+func (s *shiftedSink) WithCallDepth(depth int) logr.LogSink {
+	if sink, ok := s.sink.(logr.CallDepthLogSink); ok {
+		return &shiftedSink{sink: sink.WithCallDepth(depth), shift: s.shift}
+	}
+	return s
+}
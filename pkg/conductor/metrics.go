@@ -0,0 +1,94 @@
+package conductor
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Metrics holds the Prometheus collectors recording the activity of the reconcilers, labelled by Descriptor name.
+type Metrics struct {
+	// ReconcileDuration observes the duration of every reconciler run by the conductor.
+	ReconcileDuration *prometheus.HistogramVec
+	// ReconcileErrors counts the reconciler runs that returned an error.
+	ReconcileErrors *prometheus.CounterVec
+	// ChildOperations counts the create, update, delete and no-op outcomes reported by the reconcilers.
+	ChildOperations *prometheus.CounterVec
+	// DefaultsMismatches counts the child comparisons whose differences were only the defaults of the API server, as
+	// shown by a dry-run. A high rate calls for CompareOpts or a cache of the dry-runs.
+	DefaultsMismatches *prometheus.CounterVec
+	// APIWarnings counts the warnings returned by the API server to the reconcilers, such as the deprecation of an API
+	// version. Warnings are only captured once the transport is wrapped with WrapWarnings.
+	APIWarnings *prometheus.CounterVec
+}
+
+// registeredMetrics caches the Metrics per registerer, as conductors are usually built once per reconcile.
+var registeredMetrics sync.Map
+
+// NewMetrics returns Metrics registered against the registerer, or controller-runtime's metrics registry if nil.
+// Collectors already registered by a previous call are reused. It panics if the registration fails otherwise, like
+// prometheus.MustRegister.
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	if registerer == nil {
+		registerer = crmetrics.Registry
+	}
+	if existing, ok := registeredMetrics.Load(registerer); ok {
+		return existing.(*Metrics)
+	}
+
+	m := &Metrics{
+		ReconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "maestro_reconcile_duration_seconds",
+			Help: "Duration of the reconciler runs.",
+		}, []string{"reconciler"}),
+		ReconcileErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "maestro_reconcile_errors_total",
+			Help: "Total number of reconciler runs that returned an error.",
+		}, []string{"reconciler"}),
+		ChildOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "maestro_child_operations_total",
+			Help: "Total number of operations performed on child objects.",
+		}, []string{"reconciler", "operation"}),
+		DefaultsMismatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "maestro_defaults_mismatches_total",
+			Help: "Total number of child comparisons that only differed by the defaults of the API server.",
+		}, []string{"reconciler"}),
+		APIWarnings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "maestro_api_warnings_total",
+			Help: "Total number of warnings returned by the API server to the reconcilers.",
+		}, []string{"reconciler"}),
+	}
+	m.ReconcileDuration = register(registerer, m.ReconcileDuration)
+	m.ReconcileErrors = register(registerer, m.ReconcileErrors)
+	m.ChildOperations = register(registerer, m.ChildOperations)
+	m.DefaultsMismatches = register(registerer, m.DefaultsMismatches)
+	m.APIWarnings = register(registerer, m.APIWarnings)
+
+	actual, _ := registeredMetrics.LoadOrStore(registerer, m)
+	return actual.(*Metrics)
+}
+
+// register registers the collector, returning the existing one if it was already registered.
+func register[C prometheus.Collector](registerer prometheus.Registerer, collector C) C {
+	if err := registerer.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
+
+// observeReconcile records the duration and error (if any) of a reconciler run.
+func (m *Metrics) observeReconcile(name string, duration time.Duration, err error) {
+	m.ReconcileDuration.WithLabelValues(name).Observe(duration.Seconds())
+	if err != nil {
+		m.ReconcileErrors.WithLabelValues(name).Inc()
+	}
+}
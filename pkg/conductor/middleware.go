@@ -0,0 +1,29 @@
+package conductor
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LoggingMiddleware logs the start and the outcome of every reconcile at verbosity 1, with its duration and result.
+// The logger is taken from the context of the reconcile.
+func LoggingMiddleware[Parent client.Object]() api.Middleware[Parent] {
+	return func(next api.Reconciler[Parent]) api.Reconciler[Parent] {
+		return api.Decorate(next, func(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+			log := Logger(ctx, next.Describe().Name, parent).V(1)
+			log.Info("reconciling")
+			start := time.Now()
+			result, err := next.Reconcile(ctx, k8sCli, parent)
+			if err != nil {
+				log.Info("reconcile failed", "duration", time.Since(start), "error", err.Error())
+				return result, err
+			}
+			log.Info("reconciled", "duration", time.Since(start), "requeue", result.Requeue, "requeueAfter", result.RequeueAfter)
+			return result, nil
+		})
+	}
+}
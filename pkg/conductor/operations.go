@@ -0,0 +1,101 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/binder"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Operation is the action a reconciler took on a child object.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+	OperationNoOp   Operation = "noop"
+)
+
+// eventReasons are the reasons of the Events recorded on the parent for each Operation. No-ops aren't recorded.
+var eventReasons = map[Operation]string{
+	OperationCreate: "Created",
+	OperationUpdate: "Updated",
+	OperationDelete: "Deleted",
+}
+
+// operationRecorder reports the operations of the reconcilers as metrics, Events on the parent and in the Inspector.
+type operationRecorder struct {
+	metrics     *Metrics
+	recorder    record.EventRecorder
+	inspector   *Inspector
+	parent      client.Object
+	scheme      *runtime.Scheme
+	reconcileID types.UID
+}
+
+var operationBinder = binder.StaticBindable[operationRecorder]{}
+
+// RecordOperation reports an operation performed by the named reconciler on a child, which may be nil for resources
+// outside the cluster. It is counted in the Metrics and recorded as an Event on the parent, when configured on the
+// conductor, annotated with the ReconcileID of the run, and kept as the last action of the reconciler by the Inspector.
+// Without a conductor it does nothing.
+func RecordOperation(ctx context.Context, name string, operation Operation, child client.Object) {
+	r, err := operationBinder.FromContext(ctx)
+	if err != nil {
+		return
+	}
+
+	if r.metrics != nil {
+		r.metrics.ChildOperations.WithLabelValues(name, string(operation)).Inc()
+	}
+	if r.inspector != nil && operation != OperationNoOp {
+		action := ActionStatus{Time: time.Now(), Operation: operation}
+		if child != nil {
+			action.Child = objectKind(child, r.scheme) + " " + client.ObjectKeyFromObject(child).String()
+		}
+		r.inspector.recordAction(objectKind(r.parent, r.scheme), client.ObjectKeyFromObject(r.parent), name, action)
+	}
+
+	reason, ok := eventReasons[operation]
+	if r.recorder == nil || !ok {
+		return
+	}
+	annotations := map[string]string{ReconcileIDAnnotation: string(r.reconcileID)}
+	if child == nil {
+		r.recorder.AnnotatedEventf(r.parent, annotations, corev1.EventTypeNormal, reason, "%s: %s external resource", name, reason)
+		return
+	}
+	r.recorder.AnnotatedEventf(r.parent, annotations, corev1.EventTypeNormal, reason, "%s: %s %s %s",
+		name, reason, objectKind(child, r.scheme), client.ObjectKeyFromObject(child))
+}
+
+// RecordDefaultsMismatch reports that the child of the named reconciler differed from the desired one only by the
+// defaults of the API server, as shown by a dry-run. It is counted in the Metrics, when configured on the conductor.
+func RecordDefaultsMismatch(ctx context.Context, name string) {
+	r, err := operationBinder.FromContext(ctx)
+	if err != nil || r.metrics == nil {
+		return
+	}
+	r.metrics.DefaultsMismatches.WithLabelValues(name).Inc()
+}
+
+// objectKind returns the kind of the object, looked up in the scheme for typed objects without type metadata.
+func objectKind(obj runtime.Object, scheme *runtime.Scheme) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	if scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, scheme); err == nil {
+			return gvk.Kind
+		}
+	}
+	return fmt.Sprintf("%T", obj)
+}
@@ -0,0 +1,41 @@
+package conductor
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcilerOutcome is the outcome of a single reconciler during a run of the pipeline.
+type ReconcilerOutcome struct {
+	// Descriptor is the descriptor of the reconciler.
+	Descriptor api.Descriptor
+	// Phase is the phase of the reconciler, see RegisterInPhase.
+	Phase string
+	// Result is the result returned by the reconciler.
+	Result reconcile.Result
+	// Skipped is true if the reconciler was not run, e.g. because its RequiredConditions were not met.
+	Skipped bool
+	// Duration is how long the reconciler took.
+	Duration time.Duration
+}
+
+// ConductSeq runs the pipeline like Conduct, yielding the outcome of each reconciler as it completes.
+// Errors not tied to a reconciler (e.g. a missing reference) are yielded with an empty outcome.
+// Stopping the iteration stops the run before the remaining reconcilers and the status conditions handler.
+func (d *Conductor[Parent]) ConductSeq(ctx context.Context, parent Parent) iter.Seq2[ReconcilerOutcome, error] {
+	return func(yield func(ReconcilerOutcome, error) bool) {
+		yieldedErr, stopped := false, false
+		_, err := d.conduct(ctx, parent, func(outcome ReconcilerOutcome, err error) bool {
+			yieldedErr = yieldedErr || err != nil
+			stopped = !yield(outcome, err)
+			return !stopped
+		})
+		if err != nil && !yieldedErr && !stopped {
+			yield(ReconcilerOutcome{}, err)
+		}
+	}
+}
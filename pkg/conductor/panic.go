@@ -0,0 +1,47 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// ErrReconcilerPanicked is returned when a reconciler panicked. The panic is recovered, so it doesn't crash the
+// controller.
+var ErrReconcilerPanicked = errors.New("reconciler panicked")
+
+// MaxPanicStackLength is the maximum length of the stack trace recorded in a `<Name>Panicked` condition.
+const MaxPanicStackLength = 4096
+
+// recoverPanic recovers a panic of the named reconciler, converting it into an ErrReconcilerPanicked error and a
+// `<Name>Panicked` condition holding the truncated stack trace. It must be deferred.
+func recoverPanic(ctx context.Context, name string, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	*err = fmt.Errorf("%w: %s: %v", ErrReconcilerPanicked, name, recovered)
+	klog.FromContext(ctx).Error(*err, "recovered panic", "reconciler", name, "stack", string(stack))
+
+	if len(stack) > MaxPanicStackLength {
+		stack = append(stack[:MaxPanicStackLength:MaxPanicStackLength], "\n... (truncated)"...)
+	}
+	if state, stateErr := FetchState(ctx); stateErr == nil {
+		state.AddCondition(metav1.Condition{
+			Type:    fmt.Sprintf("%sPanicked", name),
+			Status:  metav1.ConditionTrue,
+			Reason:  "Panicked",
+			Message: fmt.Sprintf("panic: %v\n%s", recovered, stack),
+			LastTransitionTime: metav1.Time{
+				Time: time.Now(),
+			},
+		})
+	}
+}
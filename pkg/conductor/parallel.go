@@ -0,0 +1,189 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	// ErrUnknownDependency is returned when a Descriptor depends on a reconciler that is not registered.
+	ErrUnknownDependency = errors.New("unknown reconciler dependency")
+	// ErrDependencyCycle is returned when the DependsOn of the registered reconcilers form a cycle.
+	ErrDependencyCycle = errors.New("reconciler dependency cycle")
+)
+
+// dependencyGraph returns, for every descriptor, the indexes of the descriptors depending on it and the number of
+// dependencies it waits for. Dependencies on completed reconcilers (of earlier phases) are already satisfied. It fails
+// on unknown dependencies and cycles.
+func dependencyGraph(descs []api.Descriptor, completed map[string]bool) ([][]int, []int, error) {
+	index := make(map[string]int, len(descs))
+	for i, desc := range descs {
+		index[desc.Name] = i
+	}
+
+	dependents := make([][]int, len(descs))
+	pending := make([]int, len(descs))
+	for i, desc := range descs {
+		for _, name := range desc.DependsOn {
+			if completed[name] {
+				continue
+			}
+			j, ok := index[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: %s depends on %s", ErrUnknownDependency, desc.Name, name)
+			}
+			dependents[j] = append(dependents[j], i)
+			pending[i]++
+		}
+	}
+
+	// Kahn's algorithm: every descriptor is visited only if the graph is acyclic.
+	remaining := slices.Clone(pending)
+	queue := make([]int, 0, len(descs))
+	for i, count := range remaining {
+		if count == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for visited := 0; visited < len(queue); visited++ {
+		for _, j := range dependents[queue[visited]] {
+			if remaining[j]--; remaining[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	if len(queue) != len(descs) {
+		var cyclic []string
+		for i, count := range remaining {
+			if count > 0 {
+				cyclic = append(cyclic, descs[i].Name)
+			}
+		}
+		return nil, nil, fmt.Errorf("%w between %v", ErrDependencyCycle, cyclic)
+	}
+	return dependents, pending, nil
+}
+
+// runParallel runs the enabled reconcilers on a pool of d.parallelism workers, starting each one once its dependencies
+// completed. Reconcilers are started in registration order when several are ready. Outcomes are emitted from the
+// calling goroutine. The return values follow runSequential: the run stops when a reconciler failed or requeued
+// (unless the AggregationPolicy runs every reconciler), after the reconcilers not depending on it completed.
+func (d *Conductor[Parent]) runParallel(ctx context.Context, state *State, reconcilers []api.Reconciler[Parent], completed map[string]bool, emit func(ReconcilerOutcome, error) bool) (reconcile.Result, bool, bool, error) {
+	descs := make([]api.Descriptor, len(reconcilers))
+	for i, r := range reconcilers {
+		descs[i] = r.Describe()
+	}
+	dependents, pending, err := dependencyGraph(descs, completed)
+	if err != nil {
+		return reconcile.Result{}, false, true, err
+	}
+
+	type completion struct {
+		index    int
+		result   reconcile.Result
+		err      error
+		duration time.Duration
+	}
+	completions := make(chan completion)
+
+	var (
+		ready    []int
+		blocked  = make([]bool, len(descs))
+		running  int
+		result   reconcile.Result
+		errs     []error
+		gated    bool
+		failed   bool
+		stopped  bool
+		emitting = func(outcome ReconcilerOutcome, err error) {
+			if emit != nil && !stopped && !emit(outcome, err) {
+				stopped = true
+			}
+		}
+	)
+	for i, count := range pending {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	// block prevents the dependents of a reconciler from running. Skipped dependents are emitted as such.
+	var block func(i int, skipped bool)
+	block = func(i int, skipped bool) {
+		for _, j := range dependents[i] {
+			if blocked[j] {
+				continue
+			}
+			blocked[j] = true
+			if skipped {
+				emitting(ReconcilerOutcome{Descriptor: descs[j], Skipped: true}, nil)
+			}
+			block(j, skipped)
+		}
+	}
+
+	for (len(ready) > 0 && !stopped) || running > 0 {
+		for len(ready) > 0 && !stopped && running < d.parallelism {
+			i := ready[0]
+			ready = ready[1:]
+
+			met, err := d.requirementsMet(ctx, state, descs[i])
+			if err != nil {
+				errs = append(errs, err)
+				failed = true
+				block(i, false)
+				continue
+			}
+			if !met {
+				gated = true
+				emitting(ReconcilerOutcome{Descriptor: descs[i], Skipped: true}, nil)
+				block(i, true)
+				continue
+			}
+
+			running++
+			go func(i int) {
+				start := time.Now()
+				result, err := d.Reconcile(ctx, reconcilers[i])
+				completions <- completion{index: i, result: result, err: err, duration: time.Since(start)}
+			}(i)
+		}
+		if running == 0 {
+			continue
+		}
+
+		c := <-completions
+		running--
+		if d.metrics != nil {
+			d.metrics.observeReconcile(descs[c.index].Name, c.duration, c.err)
+		}
+		emitting(ReconcilerOutcome{Descriptor: descs[c.index], Result: c.result, Duration: c.duration}, c.err)
+		result = d.aggregation.merge(result, c.result)
+		if c.err != nil {
+			errs = append(errs, c.err)
+		}
+		if shouldReturn(c.result, c.err) && (c.err != nil || d.aggregation.stopsOnRequeue()) {
+			failed = true
+			block(c.index, false)
+			continue
+		}
+
+		for _, j := range dependents[c.index] {
+			if pending[j]--; pending[j] == 0 && !blocked[j] {
+				ready = append(ready, j)
+			}
+		}
+		slices.Sort(ready)
+	}
+
+	if failed || stopped {
+		return result, gated, true, errors.Join(errs...)
+	}
+	return result, gated, false, nil
+}
@@ -0,0 +1,126 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultPhase is the phase of the reconcilers registered without one.
+const DefaultPhase = ""
+
+// phaseConditionSuffix is the suffix of the conditions reporting the progress of the phases.
+const phaseConditionSuffix = "PhaseCompleted"
+
+// phase is a group of reconcilers run together.
+type phase[Parent client.Object] struct {
+	name        string
+	reconcilers []api.Reconciler[Parent]
+}
+
+// RegisterInPhase registers reconcilers in the named phase. Phases run in the order they were first registered into,
+// each one completing before the next starts: when a reconciler of a phase requeues (with a run-all
+// AggregationPolicy) or is gated by its RequiredConditions, the following phases wait for the next reconcile.
+// Within a phase, reconcilers run sequentially or in parallel like the other reconcilers. Reconcilers registered with
+// Register belong to the DefaultPhase. A `<Phase>PhaseCompleted` condition reports the progress of every named phase.
+func (d *Conductor[Parent]) RegisterInPhase(phase string, reconcilers ...api.Reconciler[Parent]) api.Conductor[Parent] {
+	return d.register(phase, nil, reconcilers...)
+}
+
+// enabledPhases returns the enabled reconcilers grouped by phase, with the phases in order.
+func (d *Conductor[Parent]) enabledPhases(parent Parent) []phase[Parent] {
+	var phases []phase[Parent]
+	index := map[string]int{}
+	for i, reconciler := range d.reconcilers {
+		name := d.phases[i]
+		j, ok := index[name]
+		if !ok {
+			j = len(phases)
+			index[name] = j
+			phases = append(phases, phase[Parent]{name: name})
+		}
+		if d.enabled(i, parent) {
+			phases[j].reconcilers = append(phases[j].reconcilers, reconciler)
+		}
+	}
+	return phases
+}
+
+// runPhases runs the phases in order, following runSequential for the return values. A phase that requeued or was
+// gated stops the run before the next phases, which are emitted as skipped.
+func (d *Conductor[Parent]) runPhases(ctx context.Context, state *State, phases []phase[Parent], emit func(ReconcilerOutcome, error) bool) (reconcile.Result, bool, bool, error) {
+	aggregated := reconcile.Result{}
+	gated := false
+	completed := map[string]bool{}
+	for i, phase := range phases {
+		phaseEmit := emit
+		if emit != nil {
+			phaseEmit = func(outcome ReconcilerOutcome, err error) bool {
+				outcome.Phase = phase.name
+				return emit(outcome, err)
+			}
+		}
+
+		if i > 0 && d.parentRefresh != RefreshNever {
+			changed, err := d.refreshParent(ctx)
+			if err != nil || changed {
+				return reconcile.Result{Requeue: changed}, gated, true, err
+			}
+		}
+
+		var result reconcile.Result
+		var phaseGated, stop bool
+		var err error
+		if d.parallelism > 1 {
+			result, phaseGated, stop, err = d.runParallel(ctx, state, phase.reconcilers, completed, phaseEmit)
+		} else {
+			result, phaseGated, stop, err = d.runSequential(ctx, state, phase.reconcilers, phaseEmit)
+		}
+		if stop {
+			return result, gated || phaseGated, true, err
+		}
+		gated = gated || phaseGated
+		aggregated = d.aggregation.merge(aggregated, result)
+		for _, reconciler := range phase.reconcilers {
+			completed[reconciler.Describe().Name] = true
+		}
+
+		if phaseGated || shouldReturn(result, nil) {
+			addPhaseCondition(state, phase.name, metav1.ConditionFalse, "InProgress",
+				"Some reconcilers of the phase requeued or are waiting for their required conditions")
+			for _, next := range phases[i+1:] {
+				addPhaseCondition(state, next.name, metav1.ConditionFalse, "Pending",
+					fmt.Sprintf("Waiting for phase %q to complete", phase.name))
+				for _, reconciler := range next.reconcilers {
+					if emit != nil && !emit(ReconcilerOutcome{Descriptor: reconciler.Describe(), Phase: next.name, Skipped: true}, nil) {
+						return aggregated, gated, true, nil
+					}
+				}
+			}
+			return aggregated, gated, false, nil
+		}
+		addPhaseCondition(state, phase.name, metav1.ConditionTrue, "Completed", "All reconcilers of the phase completed")
+	}
+	return aggregated, gated, false, nil
+}
+
+// addPhaseCondition adds the `<Phase>PhaseCompleted` condition of a named phase to the State.
+func addPhaseCondition(state *State, name string, status metav1.ConditionStatus, reason, message string) {
+	if name == DefaultPhase {
+		return
+	}
+	state.AddCondition(metav1.Condition{
+		Type:    name + phaseConditionSuffix,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
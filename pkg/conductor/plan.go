@@ -0,0 +1,294 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PlannedChange is a write a reconciler would perform.
+type PlannedChange struct {
+	// Reconciler is the name of the reconciler performing the write. It is empty for writes made by the conductor
+	// itself, such as finalizers and status conditions.
+	Reconciler string
+	// Operation is the kind of write.
+	Operation Operation
+	// Subresource is the subresource written to (e.g. "status"), if any.
+	Subresource string
+	// Kind is the kind of the object.
+	Kind string
+	// Key is the key of the object. For DeleteAllOf, only the namespace is set.
+	Key client.ObjectKey
+	// Diff is the difference between the current and the written object for creates and updates, rendered with the
+	// DiffRenderer of the conductor. The data of Secrets is redacted.
+	Diff string
+}
+
+// String returns a one-line summary of the change.
+func (c PlannedChange) String() string {
+	target := c.Kind
+	if c.Subresource != "" {
+		target += "/" + c.Subresource
+	}
+	by := c.Reconciler
+	if by == "" {
+		by = "conductor"
+	}
+	return fmt.Sprintf("%s %s %s (%s)", c.Operation, target, c.Key, by)
+}
+
+// Plan is the report of the writes a run of the pipeline would perform.
+type Plan struct {
+	// Changes are the planned writes, in the order they were made.
+	Changes []PlannedChange
+	// Outcomes are the outcomes of the reconcilers run while planning.
+	Outcomes []ReconcilerOutcome
+}
+
+// HasChanges returns true if any write is planned.
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// String returns a human-readable report of the changes, with their diffs.
+func (p *Plan) String() string {
+	if !p.HasChanges() {
+		return "No changes."
+	}
+	var b strings.Builder
+	for _, change := range p.Changes {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+		if change.Diff != "" {
+			b.WriteString(change.Diff)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// Plan runs the pipeline for the parent without persisting any write, and reports every create, update and delete
+// the reconcilers would perform. Reads and dry-run writes reach the cluster, so reconcilers observe it as it is: a
+// reconciler depending on an object planned to be created by a previous one sees it missing.
+// Reconcilers run sequentially and requeues don't stop the run, so every reconciler is planned. Metrics and Events are
+// not recorded, and the parent is left untouched. The returned plan holds the changes collected until an error, if any.
+func (d *Conductor[Parent]) Plan(ctx context.Context, parent Parent) (*Plan, error) {
+	parent = parent.DeepCopyObject().(Parent)
+	renderer := d.diffRenderer
+	if renderer == nil {
+		renderer = reconciler.CmpDiffRenderer{Opts: []cmp.Option{reconciler.IgnoreManagedFields(), reconciler.IgnoreTypeMeta()}}
+	}
+	if _, ok := renderer.(reconciler.RedactingDiffRenderer); !ok {
+		renderer = reconciler.RedactingDiffRenderer{Renderer: renderer}
+	}
+	planner := &planningClient{Client: d.client, renderer: renderer}
+	planned := *d
+	planned.client = planner
+	planned.parallelism = 0
+	planned.metrics = nil
+	planned.recorder = nil
+	planned.inspector = nil
+	planned.reports = nil
+	planned.planner = planner
+	planned.aggregation = RunAllAndReturnMinRequeueAfter
+
+	plan := &Plan{}
+	_, err := planned.conduct(ctx, parent, func(outcome ReconcilerOutcome, _ error) bool {
+		plan.Outcomes = append(plan.Outcomes, outcome)
+		return true
+	})
+	plan.Changes = planner.changes
+	return plan, err
+}
+
+// planningClient records writes instead of sending them. Dry-run writes are passed through.
+type planningClient struct {
+	client.Client
+	renderer   reconciler.DiffRenderer
+	mu         sync.Mutex
+	reconciler string
+	changes    []PlannedChange
+}
+
+// attribute attributes the writes to the named reconciler until the returned function is called. It is a no-op on a
+// nil planningClient, so the conductor can call it unconditionally.
+func (c *planningClient) attribute(name string) func() {
+	if c == nil {
+		return func() {}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reconciler = name
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.reconciler = ""
+	}
+}
+
+// record records a write of the desired object, diffing it against the current one.
+func (c *planningClient) record(operation Operation, subresource string, current, desired client.Object) {
+	change := PlannedChange{
+		Operation:   operation,
+		Subresource: subresource,
+		Kind:        objectKind(desired, c.Scheme()),
+		Key:         client.ObjectKeyFromObject(desired),
+	}
+	if operation != OperationDelete {
+		diff, err := c.renderer.RenderDiff(current, desired)
+		if err != nil {
+			diff = fmt.Sprintf("unable to render diff: %v", err)
+		}
+		change.Diff = diff
+	}
+	c.append(change)
+}
+
+func (c *planningClient) append(change PlannedChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	change.Reconciler = c.reconciler
+	c.changes = append(c.changes, change)
+}
+
+// current returns the object as stored in the cluster, or an empty object if it can't be read.
+func (c *planningClient) current(ctx context.Context, obj client.Object) client.Object {
+	current := emptyLike(obj)
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return emptyLike(obj)
+	}
+	return current
+}
+
+// patched returns the current object with the patch applied, falling back to obj for unsupported patch types.
+func (c *planningClient) patched(current, obj client.Object, patch client.Patch) client.Object {
+	// The patch is computed against obj, then applied onto the current object.
+	data, err := patch.Data(obj)
+	if err != nil {
+		return obj
+	}
+	patched := current.DeepCopyObject().(client.Object)
+	if err := reconciler.ApplyPatch(patched, client.RawPatch(patch.Type(), data)); err != nil {
+		return obj
+	}
+	return patched
+}
+
+func (c *planningClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	options := &client.CreateOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.Client.Create(ctx, obj, opts...)
+	}
+	c.record(OperationCreate, "", nil, obj)
+	return nil
+}
+
+func (c *planningClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	options := &client.UpdateOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+	c.record(OperationUpdate, "", c.current(ctx, obj), obj)
+	return nil
+}
+
+func (c *planningClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	options := &client.PatchOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	current := c.current(ctx, obj)
+	c.record(OperationUpdate, "", current, c.patched(current, obj, patch))
+	return nil
+}
+
+func (c *planningClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	options := &client.DeleteOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.Client.Delete(ctx, obj, opts...)
+	}
+	c.record(OperationDelete, "", obj, obj)
+	return nil
+}
+
+func (c *planningClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	options := &client.DeleteAllOfOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.Client.DeleteAllOf(ctx, obj, opts...)
+	}
+	c.append(PlannedChange{
+		Operation: OperationDelete,
+		Kind:      objectKind(obj, c.Scheme()),
+		Key:       client.ObjectKey{Namespace: options.Namespace},
+	})
+	return nil
+}
+
+func (c *planningClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *planningClient) SubResource(subresource string) client.SubResourceClient {
+	return &planningSubResourceClient{SubResourceClient: c.Client.SubResource(subresource), planner: c, subresource: subresource}
+}
+
+// planningSubResourceClient records the writes to a subresource instead of sending them.
+type planningSubResourceClient struct {
+	client.SubResourceClient
+	planner     *planningClient
+	subresource string
+}
+
+func (c *planningSubResourceClient) Create(ctx context.Context, obj, subresource client.Object, opts ...client.SubResourceCreateOption) error {
+	options := &client.SubResourceCreateOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.SubResourceClient.Create(ctx, obj, subresource, opts...)
+	}
+	c.planner.record(OperationCreate, c.subresource, obj, obj)
+	return nil
+}
+
+func (c *planningSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	options := &client.SubResourceUpdateOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.SubResourceClient.Update(ctx, obj, opts...)
+	}
+	c.planner.record(OperationUpdate, c.subresource, c.planner.current(ctx, obj), obj)
+	return nil
+}
+
+func (c *planningSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	options := &client.SubResourcePatchOptions{}
+	options.ApplyOptions(opts)
+	if isDryRun(options.DryRun) {
+		return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	}
+	current := c.planner.current(ctx, obj)
+	c.planner.record(OperationUpdate, c.subresource, current, c.planner.patched(current, obj, patch))
+	return nil
+}
+
+// isDryRun returns true if the dry-run values of write options request a dry-run.
+func isDryRun(dryRun []string) bool {
+	return slices.Contains(dryRun, metav1.DryRunAll)
+}
+
+// emptyLike returns a new, empty object of the type of obj.
+func emptyLike(obj client.Object) client.Object {
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+}
@@ -0,0 +1,195 @@
+package conductor
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedConditionSuffixes are the suffixes of the condition Types written by the conductor and the reconcilers of
+// this module, following the `<Name><Suffix>` convention. Conditions with these suffixes are owned by maestro when
+// `<Name>` is a condition owner (see ConditionOwnersAnnotation), and pruned when stale, if stale condition pruning is
+// enabled.
+var ManagedConditionSuffixes = []string{
+	"Reconciled", "Error", "Waiting", "ForeignOwner", "Panicked", "TimedOut", "Throttled", "Conflicting", "Progressing",
+	"Unavailable", "NamespaceTerminating", "Blocked",
+}
+
+// referenceConditionSuffix is the suffix of the conditions written for references.
+const referenceConditionSuffix = "ReferenceResolved"
+
+// ConditionOwnersAnnotation lists, comma-separated, the names of the reconcilers, references and phases the
+// conductor writes conditions for. It is kept on the parent when stale condition pruning is enabled, so the conditions
+// of a reconciler are still recognized as owned by maestro once it is unregistered, while conditions written by others
+// are never pruned, whatever their suffix.
+const ConditionOwnersAnnotation = reconciler.AnnotationPrefix + "condition-owners"
+
+// conditionPruning tracks what a run produces, to find the stale conditions of the parent.
+type conditionPruning struct {
+	// reconcilers are the names of the enabled reconcilers.
+	reconcilers map[string]bool
+	// references are the names of the registered references.
+	references map[string]bool
+	// phases are the names of the phases.
+	phases map[string]bool
+	// ran are the names of the reconcilers run.
+	ran map[string]bool
+	// declared are the names of the registered reconcilers by the condition Types declared in their Descriptor.
+	declared map[string]string
+	// owners are the condition owners, see ConditionOwnersAnnotation.
+	owners map[string]bool
+}
+
+// enablePruning enables stale condition pruning for the run, with the names of the enabled reconcilers, their phases,
+// the registered references and the condition owners.
+func (d *Conductor[Parent]) enablePruning(state *State, phases []phase[Parent], owners map[string]bool) {
+	pruning := &conditionPruning{
+		reconcilers: map[string]bool{},
+		references:  make(map[string]bool, len(d.references)),
+		phases:      make(map[string]bool, len(phases)),
+		ran:         map[string]bool{},
+		declared:    map[string]string{},
+		owners:      owners,
+	}
+	for _, r := range d.reconcilers {
+		desc := r.Describe()
+		for _, condition := range desc.Conditions {
+			pruning.declared[condition] = desc.Name
+		}
+	}
+	for _, phase := range phases {
+		pruning.phases[phase.name] = true
+		for _, reconciler := range phase.reconcilers {
+			pruning.reconcilers[reconciler.Describe().Name] = true
+		}
+	}
+	for _, reference := range d.references {
+		pruning.references[reference.Name] = true
+	}
+	state.Lock()
+	defer state.Unlock()
+	state.pruning = pruning
+}
+
+// recordConditionOwners adds the names of the registered reconcilers, references and phases to the
+// ConditionOwnersAnnotation of the parent, patching it when they changed. Names no longer registered are kept while the
+// parent has a condition they own, i.e. until their conditions are pruned. It returns the condition owners.
+func (d *Conductor[Parent]) recordConditionOwners(ctx context.Context) (map[string]bool, error) {
+	owners := map[string]bool{}
+	for i, r := range d.reconcilers {
+		owners[r.Describe().Name] = true
+		if d.phases[i] != DefaultPhase {
+			owners[d.phases[i]] = true
+		}
+	}
+	for _, reference := range d.references {
+		owners[reference.Name] = true
+	}
+
+	conditions, err := reconciler.ConditionsFromObject(d.parent)
+	if err != nil {
+		return nil, err
+	}
+	recorded := d.parent.GetAnnotations()[ConditionOwnersAnnotation]
+	for _, name := range strings.Split(recorded, ",") {
+		if name == "" || owners[name] {
+			continue
+		}
+		for _, condition := range conditions {
+			if ownsCondition(name, condition.Type) {
+				owners[name] = true
+				break
+			}
+		}
+	}
+
+	value := strings.Join(slices.Sorted(maps.Keys(owners)), ",")
+	if value == recorded {
+		return owners, nil
+	}
+	patch := client.MergeFrom(d.parent.DeepCopyObject().(client.Object))
+	annotations := d.parent.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ConditionOwnersAnnotation] = value
+	d.parent.SetAnnotations(annotations)
+	if err := d.client.Patch(ctx, d.parent, patch); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// ownsCondition returns true if the condition Type is `<name><Suffix>`, for one of the suffixes of the conditions written
+// for a reconciler, phase or reference. Conditions merely starting with the name, such as those of another owner whose
+// name shares a prefix, are not owned by it.
+func ownsCondition(name, conditionType string) bool {
+	suffix, ok := strings.CutPrefix(conditionType, name)
+	if !ok {
+		return false
+	}
+	return suffix == phaseConditionSuffix || suffix == referenceConditionSuffix || suffix == apiWarningConditionSuffix ||
+		slices.Contains(ManagedConditionSuffixes, suffix)
+}
+
+// markRan records that the named reconciler was run, if pruning is enabled for the State bound to the context.
+func markRan(ctx context.Context, name string) {
+	state, err := FetchState(ctx)
+	if err != nil {
+		return
+	}
+	state.Lock()
+	defer state.Unlock()
+	if state.pruning != nil {
+		state.pruning.ran[name] = true
+	}
+}
+
+// IsStale returns true if the condition Type is owned by maestro but no longer produced. Conditions owned by maestro
+// are those declared in the Conditions of the Descriptor of a registered reconciler, and those following the
+// `<Name><Suffix>` convention (see ManagedConditionSuffixes) where `<Name>` is a condition owner recorded on the
+// parent (see ConditionOwnersAnnotation). They are stale when they belong to a reconciler, phase or reference not
+// registered anymore (or disabled by its gate), or to a reconciler run without setting them, e.g. a `<Name>Error`
+// condition after a successful reconcile. Conditions of reconcilers not run, such as those after a requeue, and
+// conditions written by others are kept. It always returns false unless pruning is enabled with
+// WithStaleConditionPruning. StatusConditionHandlers remove the stale conditions from the parent, as done by
+// PatchStatusConditions.
+func (s *State) IsStale(conditionType string) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.pruning == nil {
+		return false
+	}
+	for _, condition := range s.Conditions {
+		if condition.Type == conditionType {
+			return false
+		}
+	}
+
+	if name, ok := s.pruning.declared[conditionType]; ok {
+		return !s.pruning.reconcilers[name] || s.pruning.ran[name]
+	}
+	owned := func(suffix string) (string, bool) {
+		name, ok := strings.CutSuffix(conditionType, suffix)
+		return name, ok && name != "" && s.pruning.owners[name]
+	}
+	if name, ok := owned(phaseConditionSuffix); ok {
+		return !s.pruning.phases[name]
+	}
+	if name, ok := owned(referenceConditionSuffix); ok {
+		return !s.pruning.references[name]
+	}
+	if name, ok := owned(apiWarningConditionSuffix); ok {
+		return !s.pruning.reconcilers[name]
+	}
+	for _, suffix := range ManagedConditionSuffixes {
+		if name, ok := owned(suffix); ok {
+			return !s.pruning.reconcilers[name] || s.pruning.ran[name]
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package conductor
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/pkg/binder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// liveReader is the uncached reader of the conductor, bound into the context of every run.
+type liveReader struct {
+	client.Reader
+}
+
+var readerBinder = binder.StaticBindable[liveReader]{}
+
+// APIReader returns the uncached reader set on the conductor with WithAPIReader, if any. Reconcilers use it to read
+// objects straight from the API server, e.g. a child that was just created or updated and may be stale in the cache.
+func APIReader(ctx context.Context) (client.Reader, bool) {
+	r, err := readerBinder.FromContext(ctx)
+	if err != nil {
+		return nil, false
+	}
+	return r.Reader, true
+}
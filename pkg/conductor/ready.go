@@ -0,0 +1,70 @@
+package conductor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultReadyConditionType is the Type of the aggregate condition, as expected by `kubectl wait --for=condition=Ready`.
+const DefaultReadyConditionType = "Ready"
+
+// notReadySuffixes are the suffixes of the condition Types reported with a True status by reconcilers that didn't
+// reach their desired state.
+var notReadySuffixes = []string{
+	"Error", "Waiting", "ForeignOwner", "Panicked", "TimedOut", "Throttled", "Conflicting", "Progressing", "Blocked",
+}
+
+// addReadyCondition adds the aggregate ready condition to the State, if enabled, from the conditions of the run and
+// its outcome: the parent is ready once every reconciler completed without error, requeue or pending child.
+func (d *Conductor[Parent]) addReadyCondition(state *State, result reconcile.Result, err error) {
+	if d.readyCondition == "" {
+		return
+	}
+	condition := metav1.Condition{
+		Type:   d.readyCondition,
+		Status: metav1.ConditionFalse,
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	}
+	if err != nil {
+		condition.Reason = "ReconcileError"
+		condition.Message = errorMessage(err)
+	} else if reason, message := notReady(state.sortedConditions()); reason != "" {
+		condition.Reason = reason
+		condition.Message = message
+	} else if shouldReturn(result, nil) {
+		condition.Reason = "Progressing"
+		condition.Message = "Waiting for the reconcilers to complete"
+	} else {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Reconciled"
+		condition.Message = "All reconcilers completed"
+	}
+	state.AddCondition(condition)
+}
+
+// notReady returns the reason and message of the first condition showing that the parent is not ready, if any.
+func notReady(conditions []metav1.Condition) (string, string) {
+	for _, condition := range conditions {
+		if name, ok := strings.CutSuffix(condition.Type, "Reconciled"); ok && name != "" && condition.Status == metav1.ConditionFalse {
+			return "Progressing", fmt.Sprintf("%s has not completed", name)
+		}
+		if name, ok := strings.CutSuffix(condition.Type, referenceConditionSuffix); ok && name != "" && condition.Status == metav1.ConditionFalse {
+			return "ReferenceNotResolved", condition.Message
+		}
+		if condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		for _, suffix := range notReadySuffixes {
+			if name, ok := strings.CutSuffix(condition.Type, suffix); ok && name != "" {
+				return suffix, fmt.Sprintf("%s: %s", condition.Type, condition.Message)
+			}
+		}
+	}
+	return "", ""
+}
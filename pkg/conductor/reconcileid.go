@@ -0,0 +1,55 @@
+package conductor
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// ReconcileIDAnnotation is the annotation of the Events recorded on the parent holding the ID of the run.
+const ReconcileIDAnnotation = reconciler.AnnotationPrefix + "reconcile-id"
+
+type reconcileIDKey struct{}
+
+// reconcileID is the ID bound to the context, and whether it was added to the logger of the context.
+type reconcileID struct {
+	id     types.UID
+	logged bool
+}
+
+// WithReconcileID returns a context whose Conduct runs have the ID, e.g. to correlate them with a request of another
+// system.
+func WithReconcileID(ctx context.Context, id types.UID) context.Context {
+	return context.WithValue(ctx, reconcileIDKey{}, reconcileID{id: id})
+}
+
+// ReconcileID returns the ID of the Conduct run of the context, correlating the actions of its reconcilers, or an empty
+// string outside of a run.
+func ReconcileID(ctx context.Context) types.UID {
+	bound, _ := ctx.Value(reconcileIDKey{}).(reconcileID)
+	return bound.id
+}
+
+// bindReconcileID binds the ID of the run to the context and adds it to the logger of the context, unless already
+// there. The ID is the one of the context (set with WithReconcileID or by the run of an outer conductor), or else the
+// reconcile ID of controller-runtime, already in the logger it passes to Reconcile, or else a new one.
+func (d *Conductor[Parent]) bindReconcileID(ctx context.Context) context.Context {
+	bound, ok := ctx.Value(reconcileIDKey{}).(reconcileID)
+	if !ok {
+		bound.id = controller.ReconcileIDFromContext(ctx)
+		bound.logged = bound.id != ""
+	}
+	if bound.id == "" {
+		bound.id = uuid.NewUUID()
+	}
+
+	log := d.logger(ctx)
+	if !bound.logged || d.log.GetSink() != nil {
+		log = log.WithValues("reconcileID", bound.id)
+	}
+	bound.logged = true
+	return context.WithValue(d.withRunLogger(ctx, log), reconcileIDKey{}, bound)
+}
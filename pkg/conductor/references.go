@@ -0,0 +1,158 @@
+package conductor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/binder"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrMissingReference = errors.New("referenced object not found")
+
+var referencesBinder = binder.StaticBindable[References]{}
+
+type ReferenceKind string
+
+const (
+	// ConfigMapReference resolves the reference to a ConfigMap, combining its Data and BinaryData
+	ConfigMapReference ReferenceKind = "ConfigMap"
+	// SecretReference resolves the reference to a Secret, decoding its Data
+	SecretReference ReferenceKind = "Secret"
+)
+
+// Reference describes a ConfigMap or Secret referenced by the parent that is loaded before the reconcilers run.
+type Reference[Parent any] struct {
+	// Name identifies the reference in References and in the conditions reported for it.
+	Name string // required
+	// Kind is the kind of object being referenced.
+	Kind ReferenceKind // required
+	// KeyFn returns the key of the referenced object, typically from a configRef or secretRef in the parent spec.
+	// An empty name means the parent does not reference an object.
+	KeyFn func(parent Parent) client.ObjectKey // required
+	// Optional allows the referenced object to be missing or not referenced at all.
+	Optional bool // optional
+}
+
+// References holds the decoded contents of the objects referenced by the parent, keyed by Reference name.
+type References struct {
+	data map[string]map[string]string
+}
+
+// Data returns the decoded contents of the named reference, or nil if it was not resolved.
+func (r *References) Data(name string) map[string]string {
+	return r.data[name]
+}
+
+// Value returns a single key of the named reference.
+func (r *References) Value(name, key string) (string, bool) {
+	value, ok := r.data[name][key]
+	return value, ok
+}
+
+// FetchReferences returns the References resolved by the conductor for the current run.
+func FetchReferences(ctx context.Context) (*References, error) {
+	return referencesBinder.FromContext(ctx)
+}
+
+// resolveReferences loads every registered reference, adding a condition for each of them to the State.
+// Missing required references result in ErrMissingReference once all references were attempted.
+func (d *Conductor[Parent]) resolveReferences(ctx context.Context, state *State) (context.Context, error) {
+	references := &References{
+		data: make(map[string]map[string]string, len(d.references)),
+	}
+
+	var missing []string
+	for _, ref := range d.references {
+		data, err := d.resolveReference(ctx, ref)
+		if errors.Is(err, ErrMissingReference) && ref.Optional {
+			continue
+		}
+		if errors.Is(err, ErrMissingReference) {
+			missing = append(missing, ref.Name)
+			state.AddCondition(referenceCondition(ref.Name, metav1.ConditionFalse, "NotFound", err.Error()))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		references.data[ref.Name] = data
+		state.AddCondition(referenceCondition(ref.Name, metav1.ConditionTrue, "Resolved", "Reference resolved successfully"))
+	}
+
+	ctx, err := referencesBinder.BindToContext(ctx, references)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		return ctx, fmt.Errorf("%w: %v", ErrMissingReference, missing)
+	}
+	return ctx, nil
+}
+
+func (d *Conductor[Parent]) resolveReference(ctx context.Context, ref Reference[Parent]) (map[string]string, error) {
+	key := ref.KeyFn(d.parent)
+	if key.Name == "" {
+		return nil, fmt.Errorf("%w: %s is not referenced by the parent", ErrMissingReference, ref.Kind)
+	}
+
+	return LoadReferenceData(ctx, d.client, ref.Kind, key)
+}
+
+// LoadReferenceData fetches a ConfigMap or Secret and returns its decoded contents.
+// A missing object results in an error wrapping ErrMissingReference.
+func LoadReferenceData(ctx context.Context, k8sCli client.Reader, kind ReferenceKind, key client.ObjectKey) (map[string]string, error) {
+	switch kind {
+	case ConfigMapReference:
+		configMap := &corev1.ConfigMap{}
+		if err := k8sCli.Get(ctx, key, configMap); err != nil {
+			return nil, wrapNotFound(err, kind, key)
+		}
+		data := make(map[string]string, len(configMap.Data)+len(configMap.BinaryData))
+		for k, v := range configMap.Data {
+			data[k] = v
+		}
+		for k, v := range configMap.BinaryData {
+			data[k] = string(v)
+		}
+		return data, nil
+	case SecretReference:
+		secret := &corev1.Secret{}
+		if err := k8sCli.Get(ctx, key, secret); err != nil {
+			return nil, wrapNotFound(err, kind, key)
+		}
+		data := make(map[string]string, len(secret.Data))
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported reference kind %q", kind)
+	}
+}
+
+func wrapNotFound(err error, kind ReferenceKind, key client.ObjectKey) error {
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: %s %s", ErrMissingReference, kind, key)
+	}
+	return err
+}
+
+func referenceCondition(name string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    fmt.Sprintf("%sReferenceResolved", name),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	}
+}
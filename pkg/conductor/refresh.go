@@ -0,0 +1,42 @@
+package conductor
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ParentRefresh configures when the parent is read again during a run, so later reconcilers of long pipelines don't
+// act on an outdated spec.
+type ParentRefresh string
+
+const (
+	// RefreshNever runs every reconciler on the parent passed to Conduct (default)
+	RefreshNever ParentRefresh = ""
+	// RefreshBetweenPhases reads the parent again before each phase but the first
+	RefreshBetweenPhases ParentRefresh = "between-phases"
+	// RefreshBetweenReconcilers reads the parent again before each reconciler but the first. With parallel execution,
+	// the parent is only read again between phases.
+	RefreshBetweenReconcilers ParentRefresh = "between-reconcilers"
+)
+
+// refreshParent reads the parent again, from the API reader if any. It returns true, keeping the parent of the run,
+// if its generation changed and the run must then requeue.
+func (d *Conductor[Parent]) refreshParent(ctx context.Context) (bool, error) {
+	var reader client.Reader = d.client
+	if d.apiReader != nil {
+		reader = d.apiReader
+	}
+	fresh := d.parent.DeepCopyObject().(Parent)
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(d.parent), fresh); err != nil {
+		return false, err
+	}
+	if d.requeueOnChange && fresh.GetGeneration() != d.parent.GetGeneration() {
+		klog.FromContext(ctx).V(1).Info("parent changed during the run, requeueing",
+			"parent", client.ObjectKeyFromObject(d.parent), "generation", fresh.GetGeneration())
+		return true, nil
+	}
+	d.parent = fresh
+	return false, nil
+}
@@ -0,0 +1,124 @@
+package conductor
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// MaxRunReports is the number of parents whose last RunReport is kept by a conductor, the least recently reconciled
+// being dropped first.
+const MaxRunReports = 1000
+
+// RunReport is the report of a run of the pipeline for a parent, see Conductor.LastRunReport.
+type RunReport struct {
+	// ReconcileID is the ReconcileID of the run.
+	ReconcileID types.UID
+	// Start is when the run started.
+	Start time.Time
+	// Duration is how long the run took.
+	Duration time.Duration
+	// Outcomes are the outcomes of the reconcilers, in the order they completed.
+	Outcomes []ReconcilerOutcome
+	// Errors are the errors returned by the reconcilers, by name.
+	Errors map[string]error
+	// Result and Err are the result and error of the run.
+	Result reconcile.Result
+	Err    error
+}
+
+// Reconcilers returns the Descriptor of the registered reconcilers, in registration order.
+func (d *Conductor[Parent]) Reconcilers() []api.Descriptor {
+	descriptors := make([]api.Descriptor, 0, len(d.reconcilers))
+	for _, r := range d.reconcilers {
+		descriptors = append(descriptors, r.Describe())
+	}
+	return descriptors
+}
+
+// LastRunReport returns the report of the last run of the pipeline for the parent, if it is one of the MaxRunReports
+// parents reconciled most recently by the conductor. Runs of Plan aren't reported.
+func (d *Conductor[Parent]) LastRunReport(key client.ObjectKey) (RunReport, bool) {
+	if d.reports == nil {
+		return RunReport{}, false
+	}
+	return d.reports.get(key)
+}
+
+// runReports holds the last RunReport of the parents.
+type runReports struct {
+	mu      sync.Mutex
+	reports map[client.ObjectKey]*RunReport
+}
+
+func newRunReports() *runReports {
+	return &runReports{reports: map[client.ObjectKey]*RunReport{}}
+}
+
+func (r *runReports) get(key client.ObjectKey) (RunReport, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report, ok := r.reports[key]
+	if !ok {
+		return RunReport{}, false
+	}
+	copied := *report
+	copied.Outcomes = slices.Clone(report.Outcomes)
+	copied.Errors = make(map[string]error, len(report.Errors))
+	for name, err := range report.Errors {
+		copied.Errors[name] = err
+	}
+	return copied, true
+}
+
+func (r *runReports) put(key client.ObjectKey, report *RunReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports[key] = report
+	for len(r.reports) > MaxRunReports {
+		var oldest client.ObjectKey
+		var oldestStart time.Time
+		for k, report := range r.reports {
+			if oldestStart.IsZero() || report.Start.Before(oldestStart) {
+				oldest, oldestStart = k, report.Start
+			}
+		}
+		delete(r.reports, oldest)
+	}
+}
+
+// report starts the RunReport of a run for the parent. The returned emit function collects the outcomes before passing
+// them to emit, if any, and the returned function completes the report with the result of the run.
+func (d *Conductor[Parent]) report(parent Parent, emit func(ReconcilerOutcome, error) bool) (
+	func(ReconcilerOutcome, error) bool, func(ctx context.Context, result reconcile.Result, err error)) {
+	if d.reports == nil {
+		return emit, func(context.Context, reconcile.Result, error) {}
+	}
+
+	var mu sync.Mutex
+	report := &RunReport{Start: time.Now(), Errors: map[string]error{}}
+	collect := func(outcome ReconcilerOutcome, err error) bool {
+		if outcome.Descriptor.Name != "" {
+			mu.Lock()
+			report.Outcomes = append(report.Outcomes, outcome)
+			if err != nil {
+				report.Errors[outcome.Descriptor.Name] = err
+			}
+			mu.Unlock()
+		}
+		return emit == nil || emit(outcome, err)
+	}
+	done := func(ctx context.Context, result reconcile.Result, err error) {
+		report.ReconcileID = ReconcileID(ctx)
+		report.Duration = time.Since(report.Start)
+		report.Result, report.Err = result, err
+		d.reports.put(client.ObjectKeyFromObject(parent), report)
+	}
+	return collect, done
+}
@@ -0,0 +1,49 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RecordResult adds a `<name>Reconciled` or `<name>Error` condition to the State bound to the context,
+// depending on the outcome of a reconcile. Without a State it does nothing.
+func RecordResult(ctx context.Context, name string, result reconcile.Result, err error) {
+	state, stateErr := FetchState(ctx)
+	if stateErr != nil {
+		return
+	}
+
+	if err != nil {
+		state.AddCondition(metav1.Condition{
+			Type:    fmt.Sprintf("%sError", name),
+			Status:  metav1.ConditionTrue,
+			Reason:  "ReconcileError",
+			Message: err.Error(),
+			LastTransitionTime: metav1.Time{
+				Time: time.Now(),
+			},
+		})
+		return
+	}
+
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sReconciled", name),
+		Status:  conditionFromResult(result),
+		Reason:  "Reconciled",
+		Message: "Reconciled successfully",
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
+
+func conditionFromResult(result reconcile.Result) metav1.ConditionStatus {
+	if result.Requeue || result.RequeueAfter > 0 {
+		return metav1.ConditionFalse
+	}
+	return metav1.ConditionTrue
+}
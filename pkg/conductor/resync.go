@@ -0,0 +1,123 @@
+package conductor
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RequestResync requests the parent to be reconciled again after the interval, to detect drift of the children. The
+// conductor requeues the parent after the smallest interval requested during the run, unless the run requeues sooner,
+// without stopping the run like a RequeueAfter result would. It returns false without a conductor State, in which case
+// the caller should return the interval as its RequeueAfter.
+func RequestResync(ctx context.Context, interval time.Duration) bool {
+	state, err := FetchState(ctx)
+	if err != nil {
+		return false
+	}
+	state.RequestResync(interval)
+	return true
+}
+
+// RequestResync records a resync interval, keeping the smallest one. Nested States share the interval.
+func (s *State) RequestResync(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	store := s.store()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.resync == 0 || interval < store.resync {
+		store.resync = interval
+	}
+}
+
+// Resync returns the smallest resync interval requested during the run, or zero if none was.
+func (s *State) Resync() time.Duration {
+	store := s.store()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.resync
+}
+
+// withResync returns the result of a successful run, requeued after the resync interval of the State unless it
+// requeues sooner.
+func withResync(result reconcile.Result, state *State) reconcile.Result {
+	resync := state.Resync()
+	if resync == 0 || result.Requeue || (result.RequeueAfter > 0 && result.RequeueAfter <= resync) {
+		return result
+	}
+	result.RequeueAfter = resync
+	return result
+}
+
+// Resyncing is a reconciler requesting a resync of the parent after the Interval whenever the Inner reconciler
+// succeeds without requeueing, see RequestResync.
+type Resyncing[Parent client.Object] struct {
+	Inner    api.Reconciler[Parent]
+	Interval time.Duration
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Resyncing[client.Object]{}
+	_ api.Finalizer[client.Object]  = &Resyncing[client.Object]{}
+	_ api.Cleaner[client.Object]    = &Resyncing[client.Object]{}
+)
+
+// WithResyncInterval wraps the reconciler so the parent is reconciled again after the interval, to detect drift of
+// its children more often (or less) than the resync period of the controller.
+func WithResyncInterval[Parent client.Object](inner api.Reconciler[Parent], interval time.Duration) *Resyncing[Parent] {
+	return &Resyncing[Parent]{Inner: inner, Interval: interval}
+}
+
+// Reconcile runs the inner reconciler, and requests a resync if it succeeded without requeueing.
+func (r *Resyncing[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.Inner.Reconcile(ctx, k8sCli, parent)
+	if err != nil || shouldReturn(result, nil) || r.Interval <= 0 {
+		return result, err
+	}
+	if !RequestResync(ctx, r.Interval) {
+		result.RequeueAfter = r.Interval
+	}
+	return result, nil
+}
+
+// Describe returns the descriptor of the inner reconciler.
+func (r *Resyncing[Parent]) Describe() api.Descriptor {
+	return r.Inner.Describe()
+}
+
+// Children returns the children of the inner reconciler, if it declares any.
+func (r *Resyncing[Parent]) Children() []client.Object {
+	if describer, ok := r.Inner.(api.ChildDescriber); ok {
+		return describer.Children()
+	}
+	return nil
+}
+
+// Validate validates the inner reconciler, if it implements api.Validator.
+func (r *Resyncing[Parent]) Validate() error {
+	if validator, ok := r.Inner.(api.Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// Finalize finalizes the inner reconciler, if it implements api.Finalizer.
+func (r *Resyncing[Parent]) Finalize(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if finalizer, ok := r.Inner.(api.Finalizer[Parent]); ok {
+		return finalizer.Finalize(ctx, k8sCli, parent)
+	}
+	return reconcile.Result{}, nil
+}
+
+// Cleanup cleans up the inner reconciler, if it implements api.Cleaner.
+func (r *Resyncing[Parent]) Cleanup(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if cleaner, ok := r.Inner.(api.Cleaner[Parent]); ok {
+		return cleaner.Cleanup(ctx, k8sCli, parent)
+	}
+	return reconcile.Result{}, nil
+}
@@ -2,10 +2,16 @@ package conductor
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethan-gallant/maestro/pkg/binder"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var contextBinder = binder.StaticBindable[State]{}
@@ -13,28 +19,125 @@ var contextBinder = binder.StaticBindable[State]{}
 type State struct {
 	Conditions []metav1.Condition
 	sync.Mutex
-	ctx context.Context
+	// previous are the conditions of the parent before the run, whose LastTransitionTime is kept when the status of
+	// a condition doesn't change.
+	previous []metav1.Condition
+	// generation is the generation of the parent, stamped as the ObservedGeneration of the conditions.
+	generation int64
+	// pruning tracks the run to find stale conditions, when enabled.
+	pruning *conditionPruning
+	// values are the values published with SetValue, shared with the nested States.
+	values *valueStore
 }
 
+// valueStore holds the values published in a State.
+type valueStore struct {
+	mu     sync.RWMutex
+	values map[string]any
+	// resync is the smallest resync interval requested during the run.
+	resync time.Duration
+}
+
+// newState returns a State for a run on the parent.
+func newState(parent client.Object) *State {
+	// Parents without conditions, or with unreadable ones, have no previous conditions.
+	previous, _ := reconciler.ConditionsFromObject(parent)
+	return &State{
+		Conditions: []metav1.Condition{},
+		previous:   previous,
+		generation: parent.GetGeneration(),
+	}
+}
+
+// AddCondition sets the condition with meta.SetStatusCondition semantics: it replaces the condition of the same Type
+// if any, and keeps the LastTransitionTime when the Status didn't change, including from the conditions the parent
+// had before the run. The ObservedGeneration defaults to the generation of the parent.
 func (s *State) AddCondition(condition metav1.Condition) {
 	s.Lock()
 	defer s.Unlock()
-	s.Conditions = append(s.Conditions, condition)
+	if condition.ObservedGeneration == 0 {
+		condition.ObservedGeneration = s.generation
+	}
+	if meta.FindStatusCondition(s.Conditions, condition.Type) == nil {
+		if previous := meta.FindStatusCondition(s.previous, condition.Type); previous != nil && previous.Status == condition.Status {
+			condition.LastTransitionTime = previous.LastTransitionTime
+		}
+	}
+	meta.SetStatusCondition(&s.Conditions, condition)
 }
 
-func (s *State) UpdateContext(ctx context.Context) {
+// FindCondition returns the condition of the given type, or nil if none was added.
+func (s *State) FindCondition(conditionType string) *metav1.Condition {
 	s.Lock()
 	defer s.Unlock()
-	s.ctx = ctx
+	if condition := meta.FindStatusCondition(s.Conditions, conditionType); condition != nil {
+		found := *condition
+		return &found
+	}
+	return nil
 }
 
-func BindState(ctx context.Context, state *State) (context.Context, error) {
-	ctx, err := contextBinder.BindToContext(ctx, state)
-	if err != nil {
-		return nil, err
+// sortedConditions returns the conditions of the State deduplicated by Type, the last one winning, stamped with the
+// generation of the parent and sorted by Type, so the conditions handed to the StatusConditionHandler are stable.
+func (s *State) sortedConditions() []metav1.Condition {
+	s.Lock()
+	defer s.Unlock()
+	conditions := make([]metav1.Condition, 0, len(s.Conditions))
+	for _, condition := range s.Conditions {
+		if condition.ObservedGeneration == 0 {
+			condition.ObservedGeneration = s.generation
+		}
+		meta.SetStatusCondition(&conditions, condition)
 	}
-	state.UpdateContext(ctx) // back-reference the context in the state
-	return ctx, nil
+	sortConditions(conditions)
+	return conditions
+}
+
+// sortConditions sorts the conditions by Type.
+func sortConditions(conditions []metav1.Condition) {
+	slices.SortStableFunc(conditions, func(a, b metav1.Condition) int {
+		return strings.Compare(a.Type, b.Type)
+	})
+}
+
+// store returns the value store of the State, creating it if needed.
+func (s *State) store() *valueStore {
+	s.Lock()
+	defer s.Unlock()
+	if s.values == nil {
+		s.values = &valueStore{values: map[string]any{}}
+	}
+	return s.values
+}
+
+// Nested returns an empty State sharing the values of s, for reconcilers running other reconcilers and rolling up
+// their conditions, such as the composite reconciler.
+func (s *State) Nested() *State {
+	return &State{values: s.store()}
+}
+
+// SetValue publishes a value in the State under the key, so later reconcilers of the run can consume it with GetValue
+// instead of reading the cluster again, e.g. a generated password or the clusterIP assigned to a Service. Values only
+// live for the run.
+func SetValue[T any](state *State, key string, value T) {
+	store := state.store()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.values[key] = value
+}
+
+// GetValue returns the value published under the key, and false if there is none or it is not of type T.
+func GetValue[T any](state *State, key string) (T, bool) {
+	store := state.store()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	value, ok := store.values[key].(T)
+	return value, ok
+}
+
+// BindState binds the State to the returned context, which must be passed to the reconcilers for them to access it.
+func BindState(ctx context.Context, state *State) (context.Context, error) {
+	return contextBinder.BindToContext(ctx, state)
 }
 
 func ClearState(ctx context.Context) context.Context {
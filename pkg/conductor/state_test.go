@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestAddCondition(t *testing.T) {
@@ -25,9 +27,9 @@ func TestAddCondition(t *testing.T) {
 	state.AddCondition(condition1)
 	state.AddCondition(condition2)
 
-	assert.Len(t, state.Conditions, 2)
-	assert.Contains(t, state.Conditions, condition1)
-	assert.Contains(t, state.Conditions, condition2)
+	require.Len(t, state.Conditions, 2)
+	assert.Equal(t, condition1.Status, state.FindCondition("Ready").Status)
+	assert.Equal(t, condition2.Status, state.FindCondition("Synced").Status)
 }
 
 func TestBindState(t *testing.T) {
@@ -96,5 +98,63 @@ func TestConcurrentAddCondition(t *testing.T) {
 		return counter == 100
 	}, 5*time.Second, 100*time.Millisecond)
 
-	assert.Len(t, state.Conditions, 200)
+	assert.Len(t, state.Conditions, 2)
+}
+
+func TestAddConditionMerge(t *testing.T) {
+	before := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	parent := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "example.com/v1", "kind": "App"}}
+	parent.SetGeneration(3)
+	require.NoError(t, reconciler.SetObjectConditions(parent, []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", LastTransitionTime: before},
+		{Type: "Synced", Status: metav1.ConditionTrue, Reason: "Synced", LastTransitionTime: before},
+	}))
+	state := newState(parent)
+
+	now := metav1.NewTime(time.Now())
+	state.AddCondition(metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Failed", LastTransitionTime: now})
+	state.AddCondition(metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", LastTransitionTime: now})
+	state.AddCondition(metav1.Condition{Type: "Synced", Status: metav1.ConditionFalse, Reason: "Failed", LastTransitionTime: now})
+
+	// Conditions of the same type are replaced
+	require.Len(t, state.Conditions, 2)
+	ready := state.FindCondition("Ready")
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, int64(3), ready.ObservedGeneration)
+	// The status flipped within the run, so the transition time is the one of the flip
+	assert.Equal(t, now, ready.LastTransitionTime)
+
+	synced := state.FindCondition("Synced")
+	require.NotNil(t, synced)
+	assert.Equal(t, now, synced.LastTransitionTime)
+
+	// A status unchanged from the parent keeps its transition time
+	state = newState(parent)
+	state.AddCondition(metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", LastTransitionTime: now})
+	assert.Equal(t, before, state.FindCondition("Ready").LastTransitionTime)
+}
+
+func TestStateValues(t *testing.T) {
+	state := &State{}
+
+	_, ok := GetValue[string](state, "password")
+	assert.False(t, ok)
+
+	SetValue(state, "password", "s3cret")
+	password, ok := GetValue[string](state, "password")
+	assert.True(t, ok)
+	assert.Equal(t, "s3cret", password)
+
+	// Values of another type are not returned
+	_, ok = GetValue[int](state, "password")
+	assert.False(t, ok)
+
+	// Nested States share the values
+	nested := state.Nested()
+	SetValue(nested, "clusterIP", "10.0.0.1")
+	clusterIP, ok := GetValue[string](state, "clusterIP")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1", clusterIP)
+	assert.Empty(t, nested.Conditions)
 }
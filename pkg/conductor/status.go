@@ -0,0 +1,60 @@
+package conductor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ StatusConditionHandler = PatchStatusConditions
+
+// PatchStatusConditions is a StatusConditionHandler merging the collected conditions into the parent's
+// .status.conditions and patching the status subresource. The parent is re-fetched and the patch retried on conflicts.
+// Conditions keep their LastTransitionTime when their status did not change, and no request is made when nothing
+// changed. Stale conditions are removed when pruning is enabled (see State.IsStale). The conditions of the parent are
+// kept sorted by Type, so its status renders the same on every run. Parents implementing reconciler.ConditionsAccessor
+// are accessed directly, others through their unstructured representation.
+func PatchStatusConditions(ctx context.Context, c client.Client, parent client.Object, conditions []metav1.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := parent.DeepCopyObject().(client.Object)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(parent), current); err != nil {
+			return err
+		}
+
+		existing, err := reconciler.ConditionsFromObject(current)
+		if err != nil {
+			return err
+		}
+		merged := append([]metav1.Condition{}, existing...)
+		for _, condition := range conditions {
+			meta.SetStatusCondition(&merged, condition)
+		}
+		if state, err := FetchState(ctx); err == nil {
+			merged = slices.DeleteFunc(merged, func(condition metav1.Condition) bool {
+				return state.IsStale(condition.Type)
+			})
+		}
+		sortConditions(merged)
+		if equality.Semantic.DeepEqual(existing, merged) {
+			return nil
+		}
+
+		base := current.DeepCopyObject().(client.Object)
+		if err := reconciler.SetObjectConditions(current, merged); err != nil {
+			return err
+		}
+		patch := client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
+		if err := c.Status().Patch(ctx, current, patch); err != nil {
+			return err
+		}
+
+		parent.SetResourceVersion(current.GetResourceVersion())
+		return reconciler.SetObjectConditions(parent, merged)
+	})
+}
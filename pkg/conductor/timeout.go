@@ -0,0 +1,42 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RunWithTimeout calls fn with a context cancelled after the timeout. When the timeout is exceeded, the parent is
+// requeued (with the backoff of the controller) instead of failing, and a `<name>TimedOut` condition is added to the
+// State bound to the context. A timeout of zero or less calls fn without a deadline.
+func RunWithTimeout(ctx context.Context, name string, timeout time.Duration, fn func(ctx context.Context) (reconcile.Result, error)) (reconcile.Result, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := fn(timeoutCtx)
+	// Only the deadline of this reconciler counts, not the cancellation of the parent context.
+	if err == nil || timeoutCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return result, err
+	}
+
+	klog.FromContext(ctx).V(1).Info("reconciler timed out", "reconciler", name, "timeout", timeout, "error", err.Error())
+	if state, stateErr := FetchState(ctx); stateErr == nil {
+		state.AddCondition(metav1.Condition{
+			Type:    fmt.Sprintf("%sTimedOut", name),
+			Status:  metav1.ConditionTrue,
+			Reason:  "Timeout",
+			Message: fmt.Sprintf("timed out after %s: %v", timeout, err),
+			LastTransitionTime: metav1.Time{
+				Time: time.Now(),
+			},
+		})
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
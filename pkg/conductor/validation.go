@@ -0,0 +1,103 @@
+package conductor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Validate returns the errors found in the configuration of the conductor, such as a missing client or a teardown
+// sequence without a finalizer, and when registering the reconcilers, such as contract violations or reconcilers
+// failing their api.Validator check. It should be called at startup; Conduct fails with the same errors.
+func (d *Conductor[Parent]) Validate() error {
+	var errs []error
+	if d.client == nil {
+		errs = append(errs, fmt.Errorf("%w: the conductor has no client", reconciler.ErrInvalidConfiguration))
+	}
+	if d.teardown && d.finalizer == "" {
+		errs = append(errs, fmt.Errorf("%w: the teardown sequence requires a finalizer", reconciler.ErrInvalidConfiguration))
+	}
+	return errors.Join(append(errs, d.errs...)...)
+}
+
+// ValidateScheme returns the types of the parent and of the children of the registered reconcilers (see
+// api.ChildTypeDescriber) that are not registered in the scheme, e.g. the one of the manager, as an aggregate error.
+// The children are already checked against the scheme of the client when the reconcilers are registered, but a
+// controller can call it at startup with the scheme it builds the client from, so missing registrations are reported
+// before the first reconcile of a child.
+func (d *Conductor[Parent]) ValidateScheme(scheme *runtime.Scheme) error {
+	var errs []error
+	if _, err := apiutil.GVKForObject(d.parent, scheme); err != nil {
+		errs = append(errs, fmt.Errorf("%w: the parent %T is not registered in the scheme: %w",
+			reconciler.ErrInvalidConfiguration, d.parent, err))
+	}
+	for _, r := range d.reconcilers {
+		errs = append(errs, checkScheme(r, scheme)...)
+	}
+	return errors.Join(errs...)
+}
+
+// checkScheme returns an error for each type of the children of the reconciler not registered in the scheme.
+func checkScheme[Parent client.Object](r api.Reconciler[Parent], scheme *runtime.Scheme) []error {
+	var children []client.Object
+	if describer, ok := r.(api.ChildTypeDescriber); ok {
+		children = describer.ChildTypes()
+	} else if describer, ok := r.(api.ChildDescriber); ok {
+		children = describer.Children()
+	}
+
+	var errs []error
+	for _, child := range children {
+		// The kind of unstructured children is only known once they are built
+		if _, ok := child.(runtime.Unstructured); ok {
+			continue
+		}
+		if _, err := apiutil.GVKForObject(child, scheme); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s manages %T, not registered in the scheme: %w",
+				reconciler.ErrInvalidConfiguration, r.Describe().Name, child, err))
+		}
+	}
+	return errs
+}
+
+// checkReconciler checks a reconciler being registered: its name and the Conditions it declares must be unique, its
+// Stability valid, the types of its children must be registered in the scheme of the client, and it must pass its own
+// api.Validator check, if implemented. Violations are kept for Validate.
+func (d *Conductor[Parent]) checkReconciler(r api.Reconciler[Parent]) {
+	desc := r.Describe()
+	name := desc.Name
+	for _, registered := range d.reconcilers {
+		if name != "" && registered.Describe().Name == name {
+			d.errs = append(d.errs, fmt.Errorf("%w: duplicate reconciler name %q", reconciler.ErrInvalidConfiguration, name))
+			break
+		}
+	}
+	for _, condition := range desc.Conditions {
+		for _, registered := range d.reconcilers {
+			if slices.Contains(registered.Describe().Conditions, condition) {
+				d.errs = append(d.errs, fmt.Errorf("%w: condition %q declared by both %s and %s",
+					reconciler.ErrInvalidConfiguration, condition, registered.Describe().Name, name))
+			}
+		}
+	}
+	if !desc.Stability.Valid() {
+		d.errs = append(d.errs, fmt.Errorf("%w: %s has an unknown stability %q",
+			reconciler.ErrInvalidConfiguration, name, desc.Stability))
+	}
+
+	if d.client != nil {
+		d.errs = append(d.errs, checkScheme(r, d.client.Scheme())...)
+	}
+
+	if validator, ok := r.(api.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			d.errs = append(d.errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+// apiWarningConditionSuffix is the suffix of the conditions reporting the warnings returned by the API server.
+const apiWarningConditionSuffix = "APIWarning"
+
+type warningHandlerKey struct{}
+
+// WrapWarnings wraps the transport of the config so the warnings returned by the API server, such as the deprecation
+// of an API version, are passed to the rest.WarningHandler bound to the context of the request with
+// WithWarningHandler. The handler of the config, e.g. controller-runtime's logger, still receives every warning. It
+// must be called before the clients are built from the config, typically before creating the manager.
+func WrapWarnings(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &warningTransport{next: rt}
+	})
+}
+
+// WithWarningHandler returns a context whose requests to the API server pass the warnings returned to the handler,
+// once the transport of the client is wrapped with WrapWarnings.
+func WithWarningHandler(ctx context.Context, handler rest.WarningHandler) context.Context {
+	return context.WithValue(ctx, warningHandlerKey{}, handler)
+}
+
+// warningHandlerFrom returns the handler bound to the context, if any.
+func warningHandlerFrom(ctx context.Context) rest.WarningHandler {
+	handler, _ := ctx.Value(warningHandlerKey{}).(rest.WarningHandler)
+	return handler
+}
+
+// warningTransport passes the warnings of the responses to the handler bound to the context of the request.
+type warningTransport struct {
+	next http.RoundTripper
+}
+
+func (t *warningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	handler := warningHandlerFrom(req.Context())
+	if handler == nil || err != nil {
+		return resp, err
+	}
+
+	if collector, ok := handler.(*warningCollector); ok && req.Method != http.MethodGet {
+		collector.markWrite()
+	}
+	warnings, _ := utilnet.ParseWarningHeaders(resp.Header.Values("Warning"))
+	for _, warning := range warnings {
+		handler.HandleWarningHeader(warning.Code, warning.Agent, warning.Text)
+	}
+	return resp, nil
+}
+
+// warningCollector collects the warnings returned to a reconciler, forwarding them to the handler bound by the caller
+// of the conductor, if any.
+type warningCollector struct {
+	next rest.WarningHandler
+
+	mu       sync.Mutex
+	warnings []string
+	wrote    bool
+}
+
+// collectWarnings binds a warningCollector to the context.
+func collectWarnings(ctx context.Context) (context.Context, *warningCollector) {
+	collector := &warningCollector{next: warningHandlerFrom(ctx)}
+	return WithWarningHandler(ctx, collector), collector
+}
+
+func (c *warningCollector) HandleWarningHeader(code int, agent string, text string) {
+	if c.next != nil {
+		c.next.HandleWarningHeader(code, agent, text)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, warning := range c.warnings {
+		if warning == text {
+			return
+		}
+	}
+	c.warnings = append(c.warnings, text)
+}
+
+func (c *warningCollector) markWrite() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wrote = true
+}
+
+// reportWarnings reports the warnings returned to the reconciler as an APIWarning condition and in the Metrics. As
+// warnings are mostly returned for writes, the condition is kept on the parent until the reconciler writes without
+// warnings, when it is set to False.
+func (d *Conductor[Parent]) reportWarnings(ctx context.Context, desc api.Descriptor, collector *warningCollector) {
+	collector.mu.Lock()
+	warnings, wrote := collector.warnings, collector.wrote
+	collector.mu.Unlock()
+
+	conditionType := desc.Name + apiWarningConditionSuffix
+	condition := metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "APIServerWarning",
+		Message: strings.Join(warnings, "; "),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	}
+	if len(warnings) > 0 {
+		d.logger(ctx).Info("API server returned warnings", "reconciler", desc.Name, "warnings", warnings)
+		if d.metrics != nil {
+			d.metrics.APIWarnings.WithLabelValues(desc.Name).Add(float64(len(warnings)))
+		}
+	} else {
+		if !wrote {
+			return
+		}
+		conditions, err := reconciler.ConditionsFromObject(d.parent)
+		if err != nil || !meta.IsStatusConditionTrue(conditions, conditionType) {
+			return
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoWarnings"
+		condition.Message = fmt.Sprintf("The API server returned no warning to %s", desc.Name)
+	}
+
+	if state, err := FetchState(ctx); err == nil {
+		state.AddCondition(condition)
+	}
+}
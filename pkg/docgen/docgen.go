@@ -0,0 +1,177 @@
+// Package docgen renders the documentation of maestro pipelines as Markdown, with a Mermaid graph of the reconcilers,
+// from the Descriptor of the reconcilers registered with the conductors.
+package docgen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Pipeline is implemented by the conductors, see conductor.Conductor.DescribePipeline.
+type Pipeline interface {
+	DescribePipeline(scheme *runtime.Scheme) (conductor.PipelineDescription, error)
+}
+
+// Generate writes the Markdown documentation of the pipelines to w, in order, resolving their kinds through the scheme.
+func Generate(w io.Writer, scheme *runtime.Scheme, pipelines ...Pipeline) error {
+	descriptions := make([]conductor.PipelineDescription, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		desc, err := pipeline.DescribePipeline(scheme)
+		if err != nil {
+			return err
+		}
+		descriptions = append(descriptions, desc)
+	}
+	return Markdown(w, descriptions...)
+}
+
+// Markdown writes a section per pipeline to w, with a Mermaid graph of its reconcilers followed by a subsection per
+// reconciler listing its description, stability, owner, phase, children, dependencies, requirements and conditions.
+func Markdown(w io.Writer, pipelines ...conductor.PipelineDescription) error {
+	var b strings.Builder
+	for i, pipeline := range pipelines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", pipeline.Parent.Kind)
+		fmt.Fprintf(&b, "Reconciles `%s`.\n\n", formatGVK(pipeline.Parent))
+		if len(pipeline.Conditions) > 0 {
+			fmt.Fprintf(&b, "Conditions: %s\n\n", codeList(pipeline.Conditions))
+		}
+		b.WriteString("```mermaid\n")
+		writeMermaid(&b, pipeline)
+		b.WriteString("```\n")
+
+		for _, r := range pipeline.Reconcilers {
+			fmt.Fprintf(&b, "\n### %s\n\n", r.Name)
+			if r.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", r.Description)
+			}
+			if r.Stability != "" {
+				fmt.Fprintf(&b, "- **Stability**: %s\n", r.Stability)
+			}
+			if r.Owner != "" {
+				fmt.Fprintf(&b, "- **Owner**: %s\n", r.Owner)
+			}
+			if r.Phase != conductor.DefaultPhase {
+				fmt.Fprintf(&b, "- **Phase**: %s\n", r.Phase)
+			}
+			if len(r.Children) > 0 {
+				fmt.Fprintf(&b, "- **Children**: %s\n", codeList(formatGVKs(r.Children)))
+			}
+			if len(r.DependsOn) > 0 {
+				fmt.Fprintf(&b, "- **Depends on**: %s\n", codeList(r.DependsOn))
+			}
+			if len(r.RequiredConditions) > 0 {
+				fmt.Fprintf(&b, "- **Required conditions**: %s\n", codeList(formatRequirements(r.RequiredConditions)))
+			}
+			if len(r.RequiredAPIs) > 0 {
+				fmt.Fprintf(&b, "- **Required APIs**: %s\n", codeList(formatGVKs(r.RequiredAPIs)))
+			}
+			if len(r.Conditions) > 0 {
+				fmt.Fprintf(&b, "- **Conditions**: %s\n", codeList(r.Conditions))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Mermaid writes a Mermaid flowchart of the reconcilers of the pipeline to w: a node per reconciler in registration
+// order, grouped by phase, with an edge from each dependency to its dependents.
+func Mermaid(w io.Writer, pipeline conductor.PipelineDescription) error {
+	var b strings.Builder
+	writeMermaid(&b, pipeline)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMermaid(b *strings.Builder, pipeline conductor.PipelineDescription) {
+	b.WriteString("flowchart TD\n")
+	ids := make(map[string]string, len(pipeline.Reconcilers))
+	for i, r := range pipeline.Reconcilers {
+		ids[r.Name] = fmt.Sprintf("r%d", i)
+	}
+
+	// Phases run in the order they were first registered into.
+	var phases []string
+	byPhase := map[string][]conductor.ReconcilerDescription{}
+	for _, r := range pipeline.Reconcilers {
+		if _, ok := byPhase[r.Phase]; !ok {
+			phases = append(phases, r.Phase)
+		}
+		byPhase[r.Phase] = append(byPhase[r.Phase], r)
+	}
+	for _, phase := range phases {
+		indent := "    "
+		if phase != conductor.DefaultPhase {
+			fmt.Fprintf(b, "    subgraph %s[\"%s\"]\n", mermaidID("phase", phase), mermaidLabel(phase))
+			indent += "    "
+		}
+		for _, r := range byPhase[phase] {
+			fmt.Fprintf(b, "%s%s[\"%s\"]\n", indent, ids[r.Name], mermaidLabel(r.Name))
+		}
+		if phase != conductor.DefaultPhase {
+			b.WriteString("    end\n")
+		}
+	}
+
+	for _, r := range pipeline.Reconcilers {
+		for _, dependency := range r.DependsOn {
+			if id, ok := ids[dependency]; ok {
+				fmt.Fprintf(b, "    %s --> %s\n", id, ids[r.Name])
+			}
+		}
+	}
+}
+
+// mermaidID returns a node identifier made of the prefix and the letters and digits of the name.
+func mermaidID(prefix, name string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+// mermaidLabel escapes the quotes of a node label.
+func mermaidLabel(label string) string {
+	return strings.ReplaceAll(label, `"`, "#quot;")
+}
+
+// formatGVK formats the kind like the apiVersion and kind of a manifest, e.g. apps/v1 Deployment.
+func formatGVK(gvk schema.GroupVersionKind) string {
+	return gvk.GroupVersion().String() + " " + gvk.Kind
+}
+
+func formatGVKs(gvks []schema.GroupVersionKind) []string {
+	formatted := make([]string, 0, len(gvks))
+	for _, gvk := range gvks {
+		formatted = append(formatted, formatGVK(gvk))
+	}
+	return formatted
+}
+
+func formatRequirements(requirements []api.ConditionRequirement) []string {
+	formatted := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", requirement.Type, requirement.Status))
+	}
+	return formatted
+}
+
+// codeList formats the values as a comma-separated list of inline code.
+func codeList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, "`"+value+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,98 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type monitorReconciler struct{}
+
+func (monitorReconciler) Describe() api.Descriptor {
+	return api.Descriptor{
+		Name:         "Monitor",
+		DependsOn:    []string{"Config"},
+		Conditions:   []string{"MonitoringReady"},
+		Stability:    api.StabilityBeta,
+		Owner:        "observability-team",
+		RequiredAPIs: []schema.GroupVersionKind{{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}},
+	}
+}
+
+func (monitorReconciler) Reconcile(context.Context, client.Client, *appsv1.Deployment) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func TestGenerate(t *testing.T) {
+	config := simple.FromReconcileFunc(func(_ context.Context, parent *appsv1.Deployment) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Config", Description: "Renders the configuration of the app."}).
+		Build()
+
+	cond := conductor.ForParent(&appsv1.Deployment{}).
+		WithClient(fake.NewClientBuilder().Build()).
+		WithReadyCondition(conductor.DefaultReadyConditionType).
+		Build()
+	cond.Register(config)
+	cond.RegisterInPhase("Observability", monitorReconciler{})
+
+	var out bytes.Buffer
+	require.NoError(t, Generate(&out, scheme.Scheme, cond))
+	assert.Equal(t, "## Deployment\n"+
+		"\n"+
+		"Reconciles `apps/v1 Deployment`.\n"+
+		"\n"+
+		"Conditions: `Ready`, `ObservabilityPhaseCompleted`\n"+
+		"\n"+
+		"```mermaid\n"+
+		"flowchart TD\n"+
+		"    r0[\"Config\"]\n"+
+		"    subgraph phaseObservability[\"Observability\"]\n"+
+		"        r1[\"Monitor\"]\n"+
+		"    end\n"+
+		"    r0 --> r1\n"+
+		"```\n"+
+		"\n"+
+		"### Config\n"+
+		"\n"+
+		"Renders the configuration of the app.\n"+
+		"\n"+
+		"- **Children**: `v1 ConfigMap`\n"+
+		"- **Conditions**: `ConfigReconciled`, `ConfigError`, `ConfigForeignOwner`, `ConfigNamespaceTerminating`, "+
+		"`ConfigBlocked`, `ConfigPanicked`\n"+
+		"\n"+
+		"### Monitor\n"+
+		"\n"+
+		"- **Stability**: Beta\n"+
+		"- **Owner**: observability-team\n"+
+		"- **Phase**: Observability\n"+
+		"- **Depends on**: `Config`\n"+
+		"- **Required APIs**: `monitoring.coreos.com/v1 ServiceMonitor`\n"+
+		"- **Conditions**: `MonitoringReady`, `MonitorUnavailable`, `MonitorPanicked`\n", out.String())
+}
+
+func TestGenerateUnregisteredParent(t *testing.T) {
+	cond := conductor.ForParent(&appsv1.Deployment{}).WithClient(fake.NewClientBuilder().Build()).Build()
+	var out bytes.Buffer
+	assert.Error(t, Generate(&out, scheme.Scheme, cond, conductor.ForParent(&unregistered{}).Build()))
+	assert.Empty(t, out.String())
+}
+
+type unregistered struct {
+	corev1.ConfigMap
+}
@@ -0,0 +1,152 @@
+package maestrotest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// update is true when the tests run with `go test -maestrotest.update`, to write the golden files instead of comparing
+// them. The flag is namespaced so it doesn't conflict with the `-update` flag commonly defined by test packages.
+var update = flag.Bool("maestrotest.update", false, "update the golden files of maestrotest")
+
+// Golden compares the children produced by a ReconcileFn for parent fixtures against golden YAML files, so a change to
+// the generated manifests shows up as a readable YAML diff. Run `go test -maestrotest.update` to write the golden
+// files after an intended change, and review them like any other change.
+type Golden[Parent client.Object, Child client.Object] struct {
+	// Dir is the directory of the golden files, relative to the test package, typically testdata.
+	Dir string // required
+	// ReconcileFn returns the desired child for a parent, e.g. the ReconcileFn of a simple reconciler.
+	ReconcileFn func(ctx context.Context, parent Parent) (Child, error) // required
+	// Parents are the parent fixtures by name. The child of each is compared against the `<Dir>/<name>.yaml` file.
+	Parents map[string]Parent // required
+	// Scheme sets the apiVersion and kind of typed children without type metadata. Defaults to the client-go scheme.
+	Scheme *runtime.Scheme // optional
+}
+
+// Run runs the ReconcileFn for every parent fixture, in a subtest named after the fixture, and asserts that the YAML
+// of the child matches its golden file.
+func (g Golden[Parent, Child]) Run(t *testing.T) {
+	t.Helper()
+	names := make([]string, 0, len(g.Parents))
+	for name := range g.Parents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		parent := g.Parents[name]
+		t.Run(name, func(t *testing.T) {
+			child, err := g.ReconcileFn(context.Background(), parent)
+			if !assert.NoError(t, err) {
+				return
+			}
+			AssertGolden(t, filepath.Join(g.Dir, name+".yaml"), g.typed(child))
+		})
+	}
+}
+
+// typed sets the apiVersion and kind of the child from the scheme, if it has no type metadata.
+func (g Golden[Parent, Child]) typed(child Child) client.Object {
+	if isNil(child) || !child.GetObjectKind().GroupVersionKind().Empty() {
+		return child
+	}
+	s := g.Scheme
+	if s == nil {
+		s = scheme.Scheme
+	}
+	gvk, err := apiutil.GVKForObject(child, s)
+	if err != nil {
+		return child
+	}
+	typed := child.DeepCopyObject().(client.Object)
+	typed.GetObjectKind().SetGroupVersionKind(gvk)
+	return typed
+}
+
+// AssertGolden asserts that the YAML of the object matches the golden file at path, relative to the test package.
+// With `go test -maestrotest.update`, the file is written instead. A nil object renders as an empty file.
+func AssertGolden(t testing.TB, path string, obj client.Object) bool {
+	t.Helper()
+	actual, err := renderGolden(obj)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("rendering %s: %v", path, err))
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return assert.Fail(t, err.Error())
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			return assert.Fail(t, err.Error())
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return assert.Fail(t, fmt.Sprintf("golden file %s is missing, run the test with -maestrotest.update to create it", path))
+	}
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+	if string(expected) == actual {
+		return true
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(actual),
+		FromFile: path,
+		ToFile:   "actual",
+		Context:  3,
+	})
+	return assert.Fail(t, fmt.Sprintf("%s doesn't match, run the test with -maestrotest.update to update it", path), "\n%s", diff)
+}
+
+// renderGolden renders the object as YAML, without the fields never set on desired objects: the managedFields, a null
+// creationTimestamp and an empty status.
+func renderGolden(obj client.Object) (string, error) {
+	if isNil(obj) {
+		return "", nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", err
+	}
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	if timestamp, ok, _ := unstructured.NestedFieldNoCopy(content, "metadata", "creationTimestamp"); ok && timestamp == nil {
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	}
+	if status, ok := content["status"].(map[string]any); ok && len(status) == 0 {
+		delete(content, "status")
+	}
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// isNil returns true if the object is nil, including a nil pointer of a concrete type.
+func isNil(obj client.Object) bool {
+	if obj == nil {
+		return true
+	}
+	value := reflect.ValueOf(obj)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
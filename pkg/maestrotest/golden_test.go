@@ -0,0 +1,58 @@
+package maestrotest
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Test packages importing maestrotest commonly define their own -update flag.
+var _ = flag.Bool("update", false, "update the golden files")
+
+func renderConfig(_ context.Context, parent *appsv1.Deployment) (*corev1.ConfigMap, error) {
+	if parent.Spec.Paused {
+		return nil, nil
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-config", Namespace: parent.Namespace},
+		Data:       map[string]string{"app": parent.Name, "strategy": string(parent.Spec.Strategy.Type)},
+	}, nil
+}
+
+func TestGolden(t *testing.T) {
+	Golden[*appsv1.Deployment, *corev1.ConfigMap]{
+		Dir:         filepath.Join("testdata", "golden"),
+		ReconcileFn: renderConfig,
+		Parents: map[string]*appsv1.Deployment{
+			"recreate": {
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}},
+			},
+			"paused": {
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Paused: true},
+			},
+		},
+	}.Run(t)
+}
+
+func TestAssertGoldenMismatch(t *testing.T) {
+	child, err := renderConfig(context.Background(), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}})
+	require.NoError(t, err)
+
+	mock := &testing.T{}
+	assert.False(t, AssertGolden(mock, filepath.Join("testdata", "golden", "recreate.yaml"), child))
+	assert.False(t, AssertGolden(mock, filepath.Join("testdata", "golden", "missing.yaml"), child))
+	assert.True(t, mock.Failed())
+
+	rendered, err := renderGolden(child)
+	require.NoError(t, err)
+	assert.Equal(t, "data:\n  app: other\n  strategy: \"\"\nmetadata:\n  name: other-config\n  namespace: default\n", rendered)
+}
@@ -0,0 +1,396 @@
+// Package maestrotest provides a Harness to unit test maestro pipelines: it runs a conductor against a fake client and
+// records the reconcilers run, the conditions added and the writes made, with assertion helpers.
+package maestrotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Action is a write made to the fake client during a run.
+type Action struct {
+	// Verb is the write, e.g. create, update, patch or delete.
+	Verb string
+	// SubResource is the subresource written, e.g. status, if any.
+	SubResource string
+	// Kind is the kind of the object written.
+	Kind string
+	Key  client.ObjectKey
+}
+
+func (a Action) String() string {
+	verb := a.Verb
+	if a.SubResource != "" {
+		verb += "/" + a.SubResource
+	}
+	return fmt.Sprintf("%s %s %s", verb, a.Kind, a.Key)
+}
+
+// Harness runs a conductor for a parent against a fake client holding the parent and the existing objects. Each call
+// to Conduct reads the parent again and records the run, so a pipeline can be run several times, e.g. to check that a
+// second run makes no writes.
+type Harness[Parent client.Object] struct {
+	// Client is the fake client the pipeline runs against. Writes made through it directly are recorded as Actions.
+	Client client.WithWatch
+	// Conductor is the conductor under test.
+	Conductor *conductor.Conductor[Parent]
+
+	t       testing.TB
+	parent  Parent
+	scheme  *runtime.Scheme
+	handler conductor.StatusConditionHandler
+
+	mu         sync.Mutex
+	conditions []metav1.Condition
+	actions    []Action
+	report     conductor.RunReport
+}
+
+// Builder builds a Harness.
+type Builder[Parent client.Object] struct {
+	t         testing.TB
+	parent    Parent
+	scheme    *runtime.Scheme
+	objects   []client.Object
+	handler   conductor.StatusConditionHandler
+	configure []func(*conductor.Builder[Parent])
+}
+
+// ForParent starts building a Harness for the parent, which is created in the fake client.
+func ForParent[Parent client.Object](t testing.TB, parent Parent) *Builder[Parent] {
+	return &Builder[Parent]{t: t, parent: parent, scheme: scheme.Scheme}
+}
+
+// WithScheme sets the scheme of the fake client, which must register the parent and the children. Defaults to the
+// client-go scheme.
+func (b *Builder[Parent]) WithScheme(s *runtime.Scheme) *Builder[Parent] {
+	b.scheme = s
+	return b
+}
+
+// WithObjects adds existing objects to the fake client, such as children from a previous version or referenced
+// Secrets.
+func (b *Builder[Parent]) WithObjects(objects ...client.Object) *Builder[Parent] {
+	b.objects = append(b.objects, objects...)
+	return b
+}
+
+// WithStatusConditionsHandler passes the conditions of each run to the handler, e.g. conductor.PatchStatusConditions
+// to persist them on the parent between runs. The conditions are recorded either way.
+func (b *Builder[Parent]) WithStatusConditionsHandler(handler conductor.StatusConditionHandler) *Builder[Parent] {
+	b.handler = handler
+	return b
+}
+
+// WithConductor configures the conductor under test, e.g. with WithReadyCondition. The client and the status
+// conditions handler of the conductor are set by the Harness.
+func (b *Builder[Parent]) WithConductor(configure func(*conductor.Builder[Parent])) *Builder[Parent] {
+	b.configure = append(b.configure, configure)
+	return b
+}
+
+// Build creates the fake client with the parent and the existing objects, and builds the conductor.
+func (b *Builder[Parent]) Build() *Harness[Parent] {
+	h := &Harness[Parent]{
+		t:       b.t,
+		parent:  b.parent,
+		scheme:  b.scheme,
+		handler: b.handler,
+	}
+	h.Client = fake.NewClientBuilder().
+		WithScheme(b.scheme).
+		WithObjects(append([]client.Object{b.parent}, b.objects...)...).
+		WithStatusSubresource(b.parent).
+		WithInterceptorFuncs(h.interceptor()).
+		Build()
+
+	builder := conductor.ForParent(b.parent)
+	for _, configure := range b.configure {
+		configure(builder)
+	}
+	h.Conductor = builder.
+		WithClient(h.Client).
+		WithStatusConditionsHandler(h.handleConditions).
+		Build()
+	h.Conductor.Use(h.recordConditions)
+	return h
+}
+
+// Register registers the reconcilers with the conductor under test.
+func (h *Harness[Parent]) Register(reconcilers ...api.Reconciler[Parent]) *Harness[Parent] {
+	for _, r := range reconcilers {
+		h.Conductor.Register(r)
+	}
+	return h
+}
+
+// Conduct reads the parent from the fake client and runs the pipeline for it, replacing the records of the previous
+// run.
+func (h *Harness[Parent]) Conduct(ctx context.Context) (reconcile.Result, error) {
+	h.t.Helper()
+	h.mu.Lock()
+	h.conditions, h.actions, h.report = nil, nil, conductor.RunReport{}
+	h.mu.Unlock()
+
+	parent := h.Parent()
+	result, err := h.Conductor.Conduct(ctx, parent)
+
+	report, _ := h.Conductor.LastRunReport(client.ObjectKeyFromObject(parent))
+	h.mu.Lock()
+	h.report = report
+	h.mu.Unlock()
+	return result, err
+}
+
+// Parent returns the latest version of the parent from the fake client.
+func (h *Harness[Parent]) Parent() Parent {
+	h.t.Helper()
+	parent := h.parent.DeepCopyObject().(Parent)
+	if err := h.Client.Get(context.Background(), client.ObjectKeyFromObject(h.parent), parent); err != nil {
+		h.t.Fatalf("getting the parent: %v", err)
+	}
+	return parent
+}
+
+// Report returns the conductor.RunReport of the last run.
+func (h *Harness[Parent]) Report() conductor.RunReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.report
+}
+
+// Ran returns the names of the reconcilers run during the last run, in the order they completed. Skipped reconcilers
+// aren't included.
+func (h *Harness[Parent]) Ran() []string {
+	var ran []string
+	for _, outcome := range h.Report().Outcomes {
+		if !outcome.Skipped {
+			ran = append(ran, outcome.Descriptor.Name)
+		}
+	}
+	return ran
+}
+
+// Conditions returns the conditions added during the last run: those passed to the status conditions handler, or the
+// conditions of the State after the last reconciler run when the run stopped before the handler.
+func (h *Harness[Parent]) Conditions() []metav1.Condition {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]metav1.Condition(nil), h.conditions...)
+}
+
+// Actions returns the writes made to the fake client during the last run, in order.
+func (h *Harness[Parent]) Actions() []Action {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Action(nil), h.actions...)
+}
+
+// AssertRan asserts that exactly the named reconcilers ran during the last run, in order.
+func (h *Harness[Parent]) AssertRan(names ...string) bool {
+	h.t.Helper()
+	return assert.Equal(h.t, names, h.Ran(), "reconcilers run")
+}
+
+// AssertConditionTrue asserts that a condition of the Type was added with the status True during the last run.
+func (h *Harness[Parent]) AssertConditionTrue(conditionType string) bool {
+	h.t.Helper()
+	return h.assertCondition(conditionType, metav1.ConditionTrue)
+}
+
+// AssertConditionFalse asserts that a condition of the Type was added with the status False during the last run.
+func (h *Harness[Parent]) AssertConditionFalse(conditionType string) bool {
+	h.t.Helper()
+	return h.assertCondition(conditionType, metav1.ConditionFalse)
+}
+
+func (h *Harness[Parent]) assertCondition(conditionType string, status metav1.ConditionStatus) bool {
+	h.t.Helper()
+	condition := meta.FindStatusCondition(h.Conditions(), conditionType)
+	if condition == nil {
+		return assert.Fail(h.t, fmt.Sprintf("condition %s not added", conditionType), "conditions: %v", h.Conditions())
+	}
+	return assert.Equal(h.t, status, condition.Status, "status of condition %s (%s: %s)",
+		conditionType, condition.Reason, condition.Message)
+}
+
+// AssertNoWrites asserts that the last run made no write, as expected once the pipeline converged.
+func (h *Harness[Parent]) AssertNoWrites() bool {
+	h.t.Helper()
+	return assert.Empty(h.t, h.Actions(), "writes")
+}
+
+// AssertChildMatches asserts that the object with the type and key of expected exists in the fake client, with the
+// fields set in expected. Fields left empty in expected, such as the resourceVersion, aren't compared, so expected only
+// needs the fields the pipeline is responsible for.
+func (h *Harness[Parent]) AssertChildMatches(expected client.Object) bool {
+	h.t.Helper()
+	current := expected.DeepCopyObject().(client.Object)
+	key := client.ObjectKeyFromObject(expected)
+	if err := h.Client.Get(context.Background(), key, current); err != nil {
+		return assert.Fail(h.t, fmt.Sprintf("getting %s %s: %v", h.kind(expected), key, err))
+	}
+
+	want, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected)
+	if err != nil {
+		return assert.Fail(h.t, err.Error())
+	}
+	got, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return assert.Fail(h.t, err.Error())
+	}
+	if mismatches := subsetMismatches("", want, got); len(mismatches) > 0 {
+		return assert.Fail(h.t, fmt.Sprintf("%s %s doesn't match", h.kind(expected), key), "%v", mismatches)
+	}
+	return true
+}
+
+// AssertNoChild asserts that no object with the type and key of obj exists in the fake client.
+func (h *Harness[Parent]) AssertNoChild(obj client.Object) bool {
+	h.t.Helper()
+	key := client.ObjectKeyFromObject(obj)
+	err := h.Client.Get(context.Background(), key, obj.DeepCopyObject().(client.Object))
+	return assert.True(h.t, apierrors.IsNotFound(err), "%s %s exists", h.kind(obj), key)
+}
+
+// subsetMismatches returns the paths of the values set in want that differ in got. Maps are compared key by key, and
+// lists element by element, so that empty fields of the elements are ignored too.
+func subsetMismatches(path string, want, got any) []string {
+	switch want := want.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		gotMap, ok := got.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %v, want %v", pathOrRoot(path), got, want)}
+		}
+		var mismatches []string
+		for key, value := range want {
+			mismatches = append(mismatches, subsetMismatches(path+"."+key, value, gotMap[key])...)
+		}
+		return mismatches
+	case []any:
+		gotList, ok := got.([]any)
+		if !ok || len(gotList) != len(want) {
+			return []string{fmt.Sprintf("%s: got %v, want %v", pathOrRoot(path), got, want)}
+		}
+		var mismatches []string
+		for i := range want {
+			mismatches = append(mismatches, subsetMismatches(fmt.Sprintf("%s[%d]", path, i), want[i], gotList[i])...)
+		}
+		return mismatches
+	default:
+		if !reflect.DeepEqual(want, got) {
+			return []string{fmt.Sprintf("%s: got %v, want %v", pathOrRoot(path), got, want)}
+		}
+		return nil
+	}
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// recordConditions records the conditions of the State after each reconciler, for the runs stopping before the status
+// conditions handler, e.g. on an error.
+func (h *Harness[Parent]) recordConditions(next api.Reconciler[Parent]) api.Reconciler[Parent] {
+	return api.Decorate(next, func(ctx context.Context, c client.Client, parent Parent) (reconcile.Result, error) {
+		result, err := next.Reconcile(ctx, c, parent)
+		if state, stateErr := conductor.FetchState(ctx); stateErr == nil {
+			state.Lock()
+			conditions := append([]metav1.Condition(nil), state.Conditions...)
+			state.Unlock()
+			h.mu.Lock()
+			h.conditions = conditions
+			h.mu.Unlock()
+		}
+		return result, err
+	})
+}
+
+// handleConditions records the conditions of the run, and passes them to the handler of the Harness, if any.
+func (h *Harness[Parent]) handleConditions(ctx context.Context, c client.Client, parent client.Object, conditions []metav1.Condition) error {
+	h.mu.Lock()
+	h.conditions = append([]metav1.Condition(nil), conditions...)
+	h.mu.Unlock()
+	if h.handler == nil {
+		return nil
+	}
+	return h.handler(ctx, c, parent, conditions)
+}
+
+// interceptor records the writes made to the fake client.
+func (h *Harness[Parent]) interceptor() interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			h.record("create", "", obj)
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			h.record("update", "", obj)
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			h.record("patch", "", obj)
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			h.record("delete", "", obj)
+			return c.Delete(ctx, obj, opts...)
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, subResourceObj client.Object, opts ...client.SubResourceCreateOption) error {
+			h.record("create", subResource, obj)
+			return c.SubResource(subResource).Create(ctx, obj, subResourceObj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			h.record("update", subResource, obj)
+			return c.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			h.record("patch", subResource, obj)
+			return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	}
+}
+
+func (h *Harness[Parent]) record(verb, subResource string, obj client.Object) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.actions = append(h.actions, Action{
+		Verb:        verb,
+		SubResource: subResource,
+		Kind:        h.kind(obj),
+		Key:         client.ObjectKeyFromObject(obj),
+	})
+}
+
+// kind returns the kind of the object, looked up in the scheme for typed objects without type metadata.
+func (h *Harness[Parent]) kind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	if gvk, err := apiutil.GVKForObject(obj, h.scheme); err == nil {
+		return gvk.Kind
+	}
+	return fmt.Sprintf("%T", obj)
+}
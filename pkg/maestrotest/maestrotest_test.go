@@ -0,0 +1,98 @@
+package maestrotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func configReconciler() api.Reconciler[*appsv1.Deployment] {
+	return simple.FromReconcileFunc(func(_ context.Context, parent *appsv1.Deployment) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-config", Namespace: parent.Namespace},
+			Data:       map[string]string{"replicas": "3", "app": parent.Name},
+		}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Config"}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+}
+
+func TestHarness(t *testing.T) {
+	ctx := context.Background()
+	parent := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid"}}
+	existing := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}}
+
+	h := ForParent(t, parent).
+		WithObjects(existing).
+		WithConductor(func(b *conductor.Builder[*appsv1.Deployment]) {
+			b.WithReadyCondition(conductor.DefaultReadyConditionType)
+		}).
+		Build()
+	h.Register(configReconciler())
+
+	result, err := h.Conduct(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	h.AssertRan("Config")
+	// The creation requeues the parent
+	h.AssertConditionFalse("ConfigReconciled")
+	h.AssertConditionFalse(conductor.DefaultReadyConditionType)
+	h.AssertChildMatches(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"app": "app"},
+	})
+	h.AssertNoChild(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}})
+	assert.Equal(t, []Action{{Verb: "create", Kind: "ConfigMap", Key: client.ObjectKey{Namespace: "default", Name: "app-config"}}}, h.Actions())
+	assert.Equal(t, "create ConfigMap default/app-config", h.Actions()[0].String())
+
+	// The pipeline converged
+	_, err = h.Conduct(ctx)
+	require.NoError(t, err)
+	h.AssertNoWrites()
+	h.AssertConditionTrue("ConfigReconciled")
+	h.AssertConditionTrue(conductor.DefaultReadyConditionType)
+}
+
+func TestHarnessFailures(t *testing.T) {
+	ctx := context.Background()
+	parent := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid"}}
+
+	mock := &testing.T{}
+	h := ForParent(mock, parent).Build()
+	h.Register(configReconciler())
+	_, err := h.Conduct(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, h.AssertRan("Other"))
+	assert.False(t, h.AssertConditionTrue("ConfigReconciled"))
+	assert.False(t, h.AssertConditionTrue("Missing"))
+	assert.False(t, h.AssertNoWrites())
+	assert.False(t, h.AssertChildMatches(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"replicas": "2"},
+	}))
+	assert.False(t, h.AssertNoChild(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"}}))
+	assert.True(t, mock.Failed())
+}
+
+func TestSubsetMismatches(t *testing.T) {
+	got := map[string]any{
+		"data":  map[string]any{"a": "1", "b": "2"},
+		"ports": []any{map[string]any{"name": "http", "port": int64(80)}},
+	}
+	assert.Empty(t, subsetMismatches("", map[string]any{"data": map[string]any{"a": "1"}, "status": nil}, got))
+	assert.Empty(t, subsetMismatches("", map[string]any{"ports": []any{map[string]any{"port": int64(80)}}}, got))
+	assert.Equal(t, []string{".data.a: got 1, want 2"}, subsetMismatches("", map[string]any{"data": map[string]any{"a": "2"}}, got))
+	assert.Len(t, subsetMismatches("", map[string]any{"ports": []any{}}, got), 1)
+}
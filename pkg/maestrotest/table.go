@@ -0,0 +1,148 @@
+package maestrotest
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Table runs table-driven tests of a pipeline: each Case runs the reconcilers for a parent against a fake client holding
+// the existing objects, and verifies the writes, the conditions and the error of the run.
+type Table[Parent client.Object] struct {
+	// Reconcilers returns the reconcilers under test, called for every Case so no state is shared between them. A
+	// single reconciler is tested through a conductor with only that reconciler registered.
+	Reconcilers func() []api.Reconciler[Parent] // required
+	// Cases are the test cases, run in order as subtests.
+	Cases []Case[Parent] // required
+	// Scheme is the scheme of the fake client. Defaults to the client-go scheme.
+	Scheme *runtime.Scheme // optional
+	// Conductor configures the conductor under test, see Builder.WithConductor.
+	Conductor func(*conductor.Builder[Parent]) // optional
+}
+
+// Case is a test case of a Table. The objects are compared by kind, namespace and name, and the expected creates and
+// updates must also match the objects in the fake client after the run on the fields they set, see
+// Harness.AssertChildMatches.
+type Case[Parent client.Object] struct {
+	// Name is the name of the subtest.
+	Name string // required
+	// Parent is the parent of the run, created in the fake client.
+	Parent Parent // required
+	// Objects are the objects existing before the run, such as children of a previous version.
+	Objects []client.Object // optional
+	// Passes is the number of runs, e.g. 2 to verify the state after the requeue following a creation. The writes of
+	// every run and the conditions and error of the last run are verified. Defaults to 1.
+	Passes int // optional
+
+	// ExpectCreates, ExpectUpdates and ExpectDeletes are the objects the run must create, update (or patch) and delete,
+	// and no other. Writes to the parent, such as adding a finalizer, count as updates; writes to subresources aren't
+	// verified.
+	ExpectCreates []client.Object // optional
+	ExpectUpdates []client.Object // optional
+	ExpectDeletes []client.Object // optional
+	// ExpectConditions are the statuses of conditions the last run must add, by Type. Other conditions aren't verified.
+	ExpectConditions map[string]metav1.ConditionStatus // optional
+	// ExpectErr is a part of the error message the last run must fail with. The run must succeed when empty.
+	ExpectErr string // optional
+}
+
+// Run runs the Cases as subtests.
+func (tt Table[Parent]) Run(t *testing.T) {
+	t.Helper()
+	for _, tc := range tt.Cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tt.runCase(t, tc)
+		})
+	}
+}
+
+func (tt Table[Parent]) runCase(t *testing.T, tc Case[Parent]) {
+	builder := ForParent(t, tc.Parent).WithObjects(tc.Objects...)
+	if tt.Scheme != nil {
+		builder.WithScheme(tt.Scheme)
+	}
+	if tt.Conductor != nil {
+		builder.WithConductor(tt.Conductor)
+	}
+	h := builder.Build()
+	h.Register(tt.Reconcilers()...)
+
+	passes := max(tc.Passes, 1)
+	var actions []Action
+	var err error
+	for range passes {
+		_, err = h.Conduct(context.Background())
+		actions = append(actions, h.Actions()...)
+	}
+
+	if tc.ExpectErr == "" {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorContains(t, err, tc.ExpectErr)
+	}
+
+	creates, updates, deletes := writtenKeys(actions)
+	assert.Equal(t, h.objectKeys(tc.ExpectCreates), creates, "created objects")
+	assert.Equal(t, h.objectKeys(tc.ExpectUpdates), updates, "updated objects")
+	assert.Equal(t, h.objectKeys(tc.ExpectDeletes), deletes, "deleted objects")
+	for _, obj := range append(append([]client.Object(nil), tc.ExpectCreates...), tc.ExpectUpdates...) {
+		h.AssertChildMatches(obj)
+	}
+
+	types := make([]string, 0, len(tc.ExpectConditions))
+	for conditionType := range tc.ExpectConditions {
+		types = append(types, conditionType)
+	}
+	sort.Strings(types)
+	for _, conditionType := range types {
+		h.assertCondition(conditionType, tc.ExpectConditions[conditionType])
+	}
+}
+
+// writtenKeys returns the sorted kinds and keys of the objects created, updated or patched, and deleted by the
+// actions, without duplicates. Writes to subresources are left out.
+func writtenKeys(actions []Action) (creates, updates, deletes []string) {
+	seen := map[string]bool{}
+	for _, action := range actions {
+		if action.SubResource != "" {
+			continue
+		}
+		key := action.Kind + " " + action.Key.String()
+		if seen[action.Verb+" "+key] {
+			continue
+		}
+		seen[action.Verb+" "+key] = true
+		switch action.Verb {
+		case "create":
+			creates = append(creates, key)
+		case "update", "patch":
+			if !seen["updated "+key] {
+				seen["updated "+key] = true
+				updates = append(updates, key)
+			}
+		case "delete":
+			deletes = append(deletes, key)
+		}
+	}
+	sort.Strings(creates)
+	sort.Strings(updates)
+	sort.Strings(deletes)
+	return creates, updates, deletes
+}
+
+// objectKeys returns the sorted kinds and keys of the objects.
+func (h *Harness[Parent]) objectKeys(objects []client.Object) []string {
+	var keys []string
+	for _, obj := range objects {
+		keys = append(keys, h.kind(obj)+" "+client.ObjectKeyFromObject(obj).String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,108 @@
+package maestrotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTable(t *testing.T) {
+	newParent := func(labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid", Labels: labels}}
+	}
+	owned := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "app", UID: "uid", Controller: ptr.To(true),
+			}}},
+			Data: data,
+		}
+	}
+	configKey := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"}}
+
+	Table[*appsv1.Deployment]{
+		Reconcilers: func() []api.Reconciler[*appsv1.Deployment] {
+			return []api.Reconciler[*appsv1.Deployment]{
+				simple.FromReconcileFunc(func(_ context.Context, parent *appsv1.Deployment) (*corev1.ConfigMap, error) {
+					if parent.Labels["broken"] == "true" {
+						return nil, errors.New("broken configuration")
+					}
+					return &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-config", Namespace: parent.Namespace},
+						Data:       map[string]string{"app": parent.Name},
+					}, nil
+				}).
+					WithDetails(api.Descriptor{Name: "Config"}).
+					WithDryRunType(reconciler.DryRunNone).
+					WithPredicateFn(func(parent *appsv1.Deployment) bool { return parent.Labels["paused"] != "true" }).
+					WithShouldDeleteFn(func(parent *appsv1.Deployment) bool { return parent.Labels["disabled"] == "true" }).
+					WithChildKeyFn(func(parent *appsv1.Deployment) *corev1.ConfigMap {
+						return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-config", Namespace: parent.Namespace}}
+					}).
+					Build(),
+			}
+		},
+		Cases: []Case[*appsv1.Deployment]{
+			{
+				Name:             "creates the child",
+				Parent:           newParent(nil),
+				ExpectCreates:    []client.Object{owned(map[string]string{"app": "app"})},
+				ExpectConditions: map[string]metav1.ConditionStatus{"ConfigReconciled": metav1.ConditionFalse},
+			},
+			{
+				Name:             "converges",
+				Parent:           newParent(nil),
+				Passes:           2,
+				ExpectCreates:    []client.Object{configKey},
+				ExpectConditions: map[string]metav1.ConditionStatus{"ConfigReconciled": metav1.ConditionTrue},
+			},
+			{
+				Name:          "updates the drifted child",
+				Parent:        newParent(nil),
+				Objects:       []client.Object{owned(map[string]string{"app": "old"})},
+				ExpectUpdates: []client.Object{owned(map[string]string{"app": "app"})},
+			},
+			{
+				Name:          "deletes the child",
+				Parent:        newParent(map[string]string{"disabled": "true"}),
+				Objects:       []client.Object{owned(nil)},
+				ExpectDeletes: []client.Object{configKey},
+			},
+			{
+				Name:    "skipped by the predicate",
+				Parent:  newParent(map[string]string{"paused": "true"}),
+				Objects: []client.Object{owned(map[string]string{"app": "old"})},
+			},
+			{
+				Name:             "fails",
+				Parent:           newParent(map[string]string{"broken": "true"}),
+				ExpectErr:        "broken configuration",
+				ExpectConditions: map[string]metav1.ConditionStatus{"ConfigError": metav1.ConditionTrue},
+			},
+		},
+	}.Run(t)
+}
+
+func TestWrittenKeys(t *testing.T) {
+	key := client.ObjectKey{Namespace: "default", Name: "app"}
+	creates, updates, deletes := writtenKeys([]Action{
+		{Verb: "create", Kind: "ConfigMap", Key: key},
+		{Verb: "update", Kind: "ConfigMap", Key: key},
+		{Verb: "patch", Kind: "ConfigMap", Key: key},
+		{Verb: "patch", SubResource: "status", Kind: "Deployment", Key: key},
+		{Verb: "delete", Kind: "Secret", Key: key},
+	})
+	assert.Equal(t, []string{"ConfigMap default/app"}, creates)
+	assert.Equal(t, []string{"ConfigMap default/app"}, updates)
+	assert.Equal(t, []string{"Secret default/app"}, deletes)
+}
@@ -0,0 +1,44 @@
+package reconciler
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// admissionDenialMarkers are found in the messages of the API errors returned when admission rejects an object.
+var admissionDenialMarkers = []string{
+	// ResourceQuota, e.g. "exceeded quota: compute, requested: limits.cpu=2, used: limits.cpu=7, limited: limits.cpu=8"
+	"exceeded quota",
+	"failed quota",
+	// LimitRange, e.g. "maximum cpu usage per Container is 500m, but limit is 1"
+	"usage per ",
+	// ValidatingAdmissionPolicy, e.g. "ValidatingAdmissionPolicy 'replicas' with binding 'replicas' denied request"
+	"ValidatingAdmissionPolicy",
+	// Admission webhooks, e.g. `admission webhook "policy.example.com" denied the request`
+	"admission webhook",
+}
+
+// IsAdmissionDenied reports whether the error is a Forbidden or Invalid API error returned when admission rejected
+// the object: a ResourceQuota exceeded, a LimitRange violated, or a denial of a ValidatingAdmissionPolicy or an
+// admission webhook. Retrying fails the same way until the policy or the object changes, which the controller can't do
+// on its own.
+func IsAdmissionDenied(err error) bool {
+	if !apierrors.IsForbidden(err) && !apierrors.IsInvalid(err) {
+		return false
+	}
+	if IsNamespaceTerminating(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	for _, marker := range admissionDenialMarkers {
+		if strings.Contains(status.Status().Message, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ApplySetLabel holds the identifier of the apply set an object belongs to (see ApplySetID).
+const ApplySetLabel = AnnotationPrefix + "applyset"
+
+// ApplySetID returns the identifier of the apply set of a reconciler for a parent, a hash of the parent UID and the
+// reconciler name short enough to be a label value.
+func ApplySetID(parent client.Object, name string) string {
+	sum := sha256.Sum256([]byte(string(parent.GetUID()) + "/" + name))
+	return "applyset-" + hex.EncodeToString(sum[:20])
+}
+
+// ApplySet is the set of objects applied by a reconciler for a parent. Members are labeled so that, once the desired
+// objects are applied, Prune can garbage collect the objects that are no longer part of the set.
+//
+// An ApplySet tracks a single reconcile: create it, Add every desired object before applying it, then call Prune.
+type ApplySet struct {
+	// Parent is the object owning the set. Objects controlled by another owner are never pruned.
+	Parent client.Object
+	// Labels are added to every member, and select the objects to prune. They must uniquely identify the set.
+	Labels map[string]string
+	// GVKs are the kinds whose objects are pruned, in addition to the kinds of the members, so objects are pruned even
+	// when no member of their kind is left.
+	GVKs []schema.GroupVersionKind
+
+	members []client.Object
+}
+
+// NewApplySet returns the ApplySet of the reconciler name for the parent. Members are labeled with the ApplySetLabel,
+// as well as the OwnerUIDLabel and ReconcilerLabel to select them by parent or reconciler.
+func NewApplySet(parent client.Object, name string, gvks ...schema.GroupVersionKind) *ApplySet {
+	return &ApplySet{
+		Parent: parent,
+		Labels: map[string]string{
+			ApplySetLabel:   ApplySetID(parent, name),
+			OwnerUIDLabel:   string(parent.GetUID()),
+			ReconcilerLabel: name,
+		},
+		GVKs: gvks,
+	}
+}
+
+// Add labels the object as a member of the set. The object must be added before it is applied.
+func (s *ApplySet) Add(obj client.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, len(s.Labels))
+	}
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+	s.members = append(s.members, obj)
+}
+
+// Prune deletes the objects matching the Labels that are not members of the set, and returns the deleted objects.
+// Only the metadata of the objects is read. The kinds of typed members are resolved with the client's scheme.
+func (s *ApplySet) Prune(ctx context.Context, k8sCli client.Client) ([]client.Object, error) {
+	keep := make(map[schema.GroupVersionKind]map[client.ObjectKey]struct{}, len(s.GVKs))
+	for _, gvk := range s.GVKs {
+		keep[gvk] = map[client.ObjectKey]struct{}{}
+	}
+	for _, member := range s.members {
+		gvk, err := apiutil.GVKForObject(member, k8sCli.Scheme())
+		if err != nil {
+			return nil, err
+		}
+		if keep[gvk] == nil {
+			keep[gvk] = map[client.ObjectKey]struct{}{}
+		}
+		keep[gvk][client.ObjectKeyFromObject(member)] = struct{}{}
+	}
+
+	var pruned []client.Object
+	for gvk, keys := range keep {
+		existing := &metav1.PartialObjectMetadataList{}
+		existing.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := k8sCli.List(ctx, existing, client.MatchingLabels(s.Labels)); err != nil {
+			return pruned, err
+		}
+
+		for i := range existing.Items {
+			obj := &existing.Items[i]
+			if _, ok := keys[client.ObjectKeyFromObject(obj)]; ok {
+				continue
+			}
+			if controller := metav1.GetControllerOf(obj); controller != nil && controller.UID != s.Parent.GetUID() {
+				continue
+			}
+
+			obj.SetGroupVersionKind(gvk)
+			if err := k8sCli.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+				return pruned, err
+			}
+			pruned = append(pruned, obj)
+		}
+	}
+	return pruned, nil
+}
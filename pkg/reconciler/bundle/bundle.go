@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler (BundleReconciler) applies the objects of a multi-document YAML manifest as children of the parent, and
+// prunes the children that are no longer part of it, e.g. to ship the RBAC, CRDs and webhooks belonging to the parent.
+//
+// Objects without a namespace are placed in the namespace of the parent. Children are owned through a controller
+// reference where possible; cluster-scoped children of a namespaced parent, and children in other namespaces, are
+// marked with reconciler.MarkOwned instead.
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	// Set the ChildGVKs to the kinds of the manifest for the conductor to watch them.
+	Details api.Descriptor // required
+	// ManifestFn returns the multi-document YAML (or JSON) manifest of the children.
+	ManifestFn func(ctx context.Context, parent Parent) ([]byte, error) // required
+	// MutateFn is a function that is called with every decoded object before it is applied, e.g. to set values derived
+	// from the parent.
+	MutateFn func(ctx context.Context, parent Parent, obj *unstructured.Unstructured) error // optional
+	// PredicateFn is a function that returns true if the bundle should be reconciled.
+	// If nil, the bundle will always be reconciled.
+	PredicateFn func(parent Parent) bool // optional
+	// DryRunType configures the dry-run behavior of the reconciler.
+	DryRunType reconciler.DryRunType // optional
+	// CompareOpts are the options to use when comparing the children to their desired state, such as
+	// reconciler.IgnoreUnstructuredFields.
+	CompareOpts []cmp.Option // optional
+	// LabelsFn returns the labels added to every child, used to find the children to prune.
+	// They must uniquely identify the children of this reconciler for the parent.
+	// Defaults to the labels of reconciler.NewApplySet for the descriptor name.
+	LabelsFn func(parent Parent) map[string]string // optional
+	// NoPrune disables deleting the children that are no longer part of the bundle.
+	NoPrune bool // optional
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object]{}
+)
+
+// Reconcile decodes the manifest, creates or updates every object, then prunes the children that are no longer part
+// of the bundle.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// Validate returns an ErrInvalidConfiguration error if the Details name or the ManifestFn is missing.
+func (r *Reconciler[Parent]) Validate() error {
+	var errs []error
+	if r.Details.Name == "" {
+		errs = append(errs, fmt.Errorf("%w: the Details name of the bundle reconciler is empty", reconciler.ErrInvalidConfiguration))
+	}
+	if r.ManifestFn == nil {
+		errs = append(errs, fmt.Errorf("%w: %s has no ManifestFn", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler[Parent]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.ManifestFn == nil {
+		return reconcile.Result{}, r.Validate()
+	}
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	manifest, err := r.ManifestFn(ctx, parent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	desired, err := reconciler.DecodeManifests(manifest)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("decoding bundle manifest: %w", err)
+	}
+	return r.Apply(ctx, k8sCli, parent, desired)
+}
+
+// Apply creates or updates the desired objects as children of the parent, then prunes the children that are not
+// desired anymore, as done by Reconcile with the objects of the manifest. It honours every option of the reconciler
+// except the ManifestFn and PredicateFn, which makes it reusable by reconcilers rendering manifests.
+func (r *Reconciler[Parent]) Apply(ctx context.Context, k8sCli client.Client, parent Parent, desired []*unstructured.Unstructured) (reconcile.Result, error) {
+	set := r.applySet(parent)
+	owned := &simple.Reconciler[Parent, *unstructured.Unstructured]{
+		Details:     r.Details,
+		DryRunType:  r.DryRunType,
+		CompareOpts: r.CompareOpts,
+	}
+	unowned := *owned
+	unowned.NoReference = true
+
+	var result reconcile.Result
+	var errs []error
+	for _, child := range desired {
+		if r.MutateFn != nil {
+			if err := r.MutateFn(ctx, parent, child); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		applier := owned
+		ownable, err := place(k8sCli, parent, child)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ownable {
+			if err := reconciler.MarkOwned(parent, child, k8sCli.Scheme()); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			applier = &unowned
+		}
+		set.Add(child)
+
+		childResult, err := applier.Apply(ctx, k8sCli, parent, child)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = reconciler.MergeResults(result, childResult)
+	}
+	if len(errs) > 0 {
+		// Never prune while some children could not be applied.
+		return reconcile.Result{}, errors.Join(errs...)
+	}
+
+	if r.NoPrune {
+		return result, nil
+	}
+
+	pruned, err := set.Prune(ctx, k8sCli)
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent))
+	for _, child := range pruned {
+		log.Info("pruned child", "child", child.GetName(), "namespace", child.GetNamespace(),
+			"kind", child.GetObjectKind().GroupVersionKind().Kind)
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, child)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(pruned) > 0 {
+		result.Requeue = true
+	}
+	return result, nil
+}
+
+// place sets the namespace of the parent on a namespaced child without one, and returns whether the child can be
+// owned by the parent through a controller reference.
+func place(k8sCli client.Client, parent client.Object, child *unstructured.Unstructured) (bool, error) {
+	namespaced, err := k8sCli.IsObjectNamespaced(child)
+	if err != nil {
+		return false, err
+	}
+	if !namespaced {
+		child.SetNamespace("")
+	} else if child.GetNamespace() == "" {
+		child.SetNamespace(parent.GetNamespace())
+	}
+	return reconciler.CanReference(k8sCli, parent, child)
+}
+
+// applySet returns the set of children of the parent, selected by the labels of the LabelsFn if any. The ChildGVKs are
+// pruned even when no object of their kind is left in the bundle.
+func (r *Reconciler[Parent]) applySet(parent Parent) *reconciler.ApplySet {
+	set := reconciler.NewApplySet(parent, r.Details.Name, r.Details.ChildGVKs...)
+	if r.LabelsFn != nil {
+		set.Labels = r.LabelsFn(parent)
+	}
+	return set
+}
@@ -0,0 +1,130 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// FromManifestFunc returns a new instance of Builder for the manifest returned by the function.
+func FromManifestFunc[Parent client.Object](fn func(ctx context.Context, parent Parent) ([]byte, error)) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			ManifestFn:  fn,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+			DryRunType:  reconciler.DryRunWarn,
+		},
+	}
+}
+
+// FromString returns a new instance of Builder for a static manifest.
+func FromString[Parent client.Object](manifest string) *Builder[Parent] {
+	return FromManifestFunc(func(context.Context, Parent) ([]byte, error) {
+		return []byte(manifest), nil
+	})
+}
+
+// FromSource returns a new instance of Builder for the manifest fetched from the source, e.g. an OCI artifact. Wrap
+// the source in a source.Cache to avoid fetching it on every reconcile.
+func FromSource[Parent client.Object](src source.ManifestSource) *Builder[Parent] {
+	return FromManifestFunc(func(ctx context.Context, _ Parent) ([]byte, error) {
+		return src.Fetch(ctx)
+	})
+}
+
+// FromFS returns a new instance of Builder for the manifests of the files matching the patterns (see fs.Glob), e.g.
+// of an embed.FS. The files are read in lexical order on every reconcile.
+func FromFS[Parent client.Object](fsys fs.FS, patterns ...string) *Builder[Parent] {
+	return FromManifestFunc(func(context.Context, Parent) ([]byte, error) {
+		return ReadFS(fsys, patterns...)
+	})
+}
+
+// ReadFS returns the manifests of the files matching the patterns, in lexical order, as a single multi-document
+// manifest. A pattern matching no file is an error.
+func ReadFS(fsys fs.FS, patterns ...string) ([]byte, error) {
+	var names []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no manifest matches %q: %w", pattern, fs.ErrNotExist)
+		}
+		names = append(names, matches...)
+	}
+	sort.Strings(names)
+
+	var manifest bytes.Buffer
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		manifest.WriteString("\n---\n")
+		manifest.Write(data)
+	}
+	return manifest.Bytes(), nil
+}
+
+// WithMutateFn sets the MutateFn field.
+func (b *Builder[Parent]) WithMutateFn(fn func(ctx context.Context, parent Parent, obj *unstructured.Unstructured) error) *Builder[Parent] {
+	b.reconciler.MutateFn = fn
+	return b
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// WithDryRunType configures the dry-run behavior of the reconciler.
+func (b *Builder[Parent]) WithDryRunType(dryRunType reconciler.DryRunType) *Builder[Parent] {
+	b.reconciler.DryRunType = dryRunType
+	return b
+}
+
+// AddCompareOpt adds a comparator option to the reconciler
+func (b *Builder[Parent]) AddCompareOpt(compareOpts []cmp.Option) *Builder[Parent] {
+	b.reconciler.CompareOpts = append(b.reconciler.CompareOpts, compareOpts...)
+	return b
+}
+
+// WithDetails sets the Details field.
+func (b *Builder[Parent]) WithDetails(details api.Descriptor) *Builder[Parent] {
+	b.reconciler.Details = details
+	return b
+}
+
+// WithLabelsFn sets the LabelsFn field.
+func (b *Builder[Parent]) WithLabelsFn(fn func(parent Parent) map[string]string) *Builder[Parent] {
+	b.reconciler.LabelsFn = fn
+	return b
+}
+
+// WithNoPrune sets the NoPrune field.
+func (b *Builder[Parent]) WithNoPrune(noPrune bool) *Builder[Parent] {
+	b.reconciler.NoPrune = noPrune
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
@@ -0,0 +1,123 @@
+package bundle
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const rbac = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: agent
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: agent
+rules:
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: ["get", "list"]
+`
+
+const config = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: agent-config
+data:
+  level: info
+`
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, rbacv1.AddToScheme(s))
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion, rbacv1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ServiceAccount"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	return fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(objs...).Build()
+}
+
+func TestBundleReconciler(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := newClient(t, parent)
+	fsys := fstest.MapFS{
+		"manifests/10-rbac.yaml":   {Data: []byte(rbac)},
+		"manifests/20-config.yaml": {Data: []byte(config)},
+	}
+	r := FromFS[*corev1.ConfigMap](fsys, "manifests/*.yaml").
+		WithDetails(api.Descriptor{Name: "Agent"}).
+		WithMutateFn(func(_ context.Context, parent *corev1.ConfigMap, obj *unstructured.Unstructured) error {
+			if obj.GetKind() == "ConfigMap" {
+				return unstructured.SetNestedField(obj.Object, parent.Name, "data", "parent")
+			}
+			return nil
+		}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+	require.NoError(t, r.Validate())
+
+	ctx := context.Background()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "agent-config", Namespace: "default"}, cm))
+	assert.Equal(t, map[string]string{"level": "info", "parent": "agent"}, cm.Data)
+	assert.True(t, metav1.IsControlledBy(cm, parent))
+	role := &rbacv1.ClusterRole{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "agent"}, role))
+	assert.True(t, reconciler.IsOwnedBy(role, parent))
+
+	// Steady state
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// Objects removed from the bundle are pruned, as long as their kind is still known
+	r.ManifestFn = FromString[*corev1.ConfigMap](rbac).Build().ManifestFn
+	r.Details.ChildGVKs = []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("ConfigMap")}
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "agent-config", Namespace: "default"}, cm)
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "agent"}, role))
+}
+
+func TestReadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"b.yaml": {Data: []byte(config)},
+		"a.yaml": {Data: []byte(rbac)},
+	}
+	manifest, err := ReadFS(fsys, "*.yaml")
+	require.NoError(t, err)
+	objects, err := reconciler.DecodeManifests(manifest)
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, []string{"ServiceAccount", "ClusterRole", "ConfigMap"},
+		[]string{objects[0].GetKind(), objects[1].GetKind(), objects[2].GetKind()})
+
+	_, err = ReadFS(fsys, "missing/*.yaml")
+	require.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package childstatus
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler (ChildStatusReconciler) reconciles the status subresource of an existing child object.
+// Unlike the simple Reconciler it never creates, updates or deletes the child itself.
+type Reconciler[Parent client.Object, Child client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	Details api.Descriptor // required
+	// StatusFn sets the desired status on the child. The child passed in is a copy of the current object,
+	// only changes to its status are applied.
+	StatusFn func(ctx context.Context, parent Parent, child Child) error // required
+	// ChildKeyFn returns the child object with only a key (name and namespace) set.
+	ChildKeyFn func(Parent) Child // required
+	// PredicateFn is a function that returns true if the StatusFn should be called.
+	// If nil, the StatusFn will always be called.
+	PredicateFn func(parent Parent) bool // optional
+	// CompareOpts are additional options to use when comparing the child status to the desired status.
+	CompareOpts []cmp.Option // optional
+}
+
+var _ api.Reconciler[client.Object] = &Reconciler[client.Object, client.Object]{}
+
+// Reconcile fetches the child, computes its desired status and updates the status subresource if it changed.
+func (r *Reconciler[Parent, Child]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent, Child]) Describe() api.Descriptor {
+	return r.Details
+}
+
+func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	current := r.ChildKeyFn(parent)
+	key := client.ObjectKeyFromObject(current)
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent), "child", key.Name, "namespace", key.Namespace)
+
+	if err := k8sCli.Get(ctx, key, current); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The child is managed elsewhere, there is no status to write until it exists.
+			log.Info("child not found, skipping status")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	desired := current.DeepCopyObject().(Child)
+	if err := r.StatusFn(ctx, parent, desired); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	compareOpts := append(r.CompareOpts, reconciler.OnlyStatusFields())
+	if cmp.Equal(current, desired, compareOpts...) {
+		log.Info("no status changes")
+		return reconcile.Result{}, nil
+	}
+
+	if err := k8sCli.Status().Update(ctx, desired); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("updated child status")
+	return reconcile.Result{}, nil
+}
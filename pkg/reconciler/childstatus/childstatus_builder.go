@@ -0,0 +1,51 @@
+package childstatus
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type StatusFn[Parent client.Object, Child client.Object] func(ctx context.Context, parent Parent, child Child) error
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object, Child client.Object] struct {
+	reconciler Reconciler[Parent, Child]
+}
+
+// FromStatusFunc returns a new instance of Builder for the StatusFn and ChildKeyFn.
+func FromStatusFunc[Parent client.Object, Child client.Object](fn StatusFn[Parent, Child], childKeyFn func(Parent) Child) *Builder[Parent, Child] {
+	return &Builder[Parent, Child]{
+		reconciler: Reconciler[Parent, Child]{
+			StatusFn:    fn,
+			ChildKeyFn:  childKeyFn,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+		},
+	}
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent, Child]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent, Child] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// AddCompareOpt adds a comparator option to the reconciler
+func (b *Builder[Parent, Child]) AddCompareOpt(compareOpts []cmp.Option) *Builder[Parent, Child] {
+	b.reconciler.CompareOpts = append(b.reconciler.CompareOpts, compareOpts...)
+	return b
+}
+
+// WithDetails sets the Details field.
+func (b *Builder[Parent, Child]) WithDetails(details api.Descriptor) *Builder[Parent, Child] {
+	b.reconciler.Details = details
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent, Child]) Build() *Reconciler[Parent, Child] {
+	return &b.reconciler
+}
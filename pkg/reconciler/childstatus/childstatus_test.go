@@ -0,0 +1,56 @@
+package childstatus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestChildStatusUpdate(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"},
+		Data:       map[string]string{"message": "aggregated"},
+	}
+	child := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+	}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(child).WithStatusSubresource(child).Build()
+
+	calls := 0
+	reconciler := FromStatusFunc(func(_ context.Context, parent *corev1.ConfigMap, child *corev1.Pod) error {
+		calls++
+		child.Spec.NodeName = "ignored"
+		child.Status.Message = parent.Data["message"]
+		return nil
+	}, func(parent *corev1.ConfigMap) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: parent.Namespace}}
+	}).Build()
+
+	ctx := context.Background()
+	_, err := reconciler.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+
+	updated := &corev1.Pod{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(child), updated))
+	assert.Equal(t, "aggregated", updated.Status.Message)
+	assert.Equal(t, "node-a", updated.Spec.NodeName, "spec must not be written by the status reconciler")
+
+	// A second reconcile is a no-op
+	_, err = reconciler.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	unchanged := &corev1.Pod{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(child), unchanged))
+	assert.Equal(t, updated.ResourceVersion, unchanged.ResourceVersion)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,155 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithFieldManager returns a client setting the field manager of every write to manager, so the managed fields and
+// the audit logs attribute the changes to it. A client.FieldOwner option passed to a call takes precedence.
+func WithFieldManager(k8sCli client.Client, manager string) client.Client {
+	if manager == "" {
+		return k8sCli
+	}
+	return &fieldManagerClient{Client: k8sCli, owner: client.FieldOwner(manager)}
+}
+
+type fieldManagerClient struct {
+	client.Client
+	owner client.FieldOwner
+}
+
+func (c *fieldManagerClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, obj, append([]client.CreateOption{c.owner}, opts...)...)
+}
+
+func (c *fieldManagerClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, obj, append([]client.UpdateOption{c.owner}, opts...)...)
+}
+
+func (c *fieldManagerClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.Client.Patch(ctx, obj, patch, append([]client.PatchOption{c.owner}, opts...)...)
+}
+
+func (c *fieldManagerClient) Status() client.SubResourceWriter {
+	return &fieldManagerWriter{SubResourceWriter: c.Client.Status(), owner: c.owner}
+}
+
+func (c *fieldManagerClient) SubResource(subResource string) client.SubResourceClient {
+	sub := c.Client.SubResource(subResource)
+	return &fieldManagerSubResourceClient{SubResourceReader: sub, fieldManagerWriter: fieldManagerWriter{SubResourceWriter: sub, owner: c.owner}}
+}
+
+type fieldManagerWriter struct {
+	client.SubResourceWriter
+	owner client.FieldOwner
+}
+
+func (w *fieldManagerWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return w.SubResourceWriter.Create(ctx, obj, subResource, append([]client.SubResourceCreateOption{w.owner}, opts...)...)
+}
+
+func (w *fieldManagerWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.SubResourceWriter.Update(ctx, obj, append([]client.SubResourceUpdateOption{w.owner}, opts...)...)
+}
+
+func (w *fieldManagerWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.SubResourceWriter.Patch(ctx, obj, patch, append([]client.SubResourcePatchOption{w.owner}, opts...)...)
+}
+
+type fieldManagerSubResourceClient struct {
+	client.SubResourceReader
+	fieldManagerWriter
+}
+
+// ServiceAccountUsername returns the username the API server authenticates the service account as.
+func ServiceAccountUsername(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// Impersonator makes requests act as a user, so they are authorized and audited as that user instead of the
+// controller. The impersonation is bound to the context of the requests (see Impersonate) and applied by the transport
+// installed with EnableImpersonation, so the client of the reconciler is used as is: the writes still go through the
+// wrappers of the conductor (e.g. Plan), and the reads served by the cache of the manager are not impersonated.
+type Impersonator struct {
+	// User is the user, groups and extra fields to impersonate.
+	User rest.ImpersonationConfig
+}
+
+// NewImpersonator returns an Impersonator for the user.
+func NewImpersonator(user rest.ImpersonationConfig) *Impersonator {
+	return &Impersonator{User: user}
+}
+
+// NewServiceAccountImpersonator returns an Impersonator for the service account. The controller must be allowed to
+// impersonate it, with the impersonate verb on the serviceaccounts resource.
+func NewServiceAccountImpersonator(namespace, name string) *Impersonator {
+	return NewImpersonator(rest.ImpersonationConfig{
+		UserName: ServiceAccountUsername(namespace, name),
+		Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"},
+	})
+}
+
+// Validate returns ErrInvalidConfiguration if the user is missing or EnableImpersonation was never called, as the
+// requests would then be sent as the controller.
+func (i *Impersonator) Validate() error {
+	if i.User.UserName == "" {
+		return fmt.Errorf("%w: the impersonated user is required", ErrInvalidConfiguration)
+	}
+	if !impersonationEnabled.Load() {
+		return fmt.Errorf("%w: impersonating %s requires EnableImpersonation on the configuration of the manager",
+			ErrInvalidConfiguration, i.User.UserName)
+	}
+	return nil
+}
+
+// Impersonate returns a copy of ctx impersonating the user in the requests sent with it.
+func (i *Impersonator) Impersonate(ctx context.Context) context.Context {
+	return context.WithValue(ctx, impersonationKey{}, i.User)
+}
+
+// ImpersonatedUser returns the user impersonated by the requests sent with ctx, if any.
+func ImpersonatedUser(ctx context.Context) (rest.ImpersonationConfig, bool) {
+	user, ok := ctx.Value(impersonationKey{}).(rest.ImpersonationConfig)
+	return user, ok
+}
+
+type impersonationKey struct{}
+
+var impersonationEnabled atomic.Bool
+
+// EnableImpersonation installs on cfg the transport impersonating the user bound to the context of each request by an
+// Impersonator. It must be called before the manager or the clients are built from cfg, e.g.
+//
+//	cfg := ctrl.GetConfigOrDie()
+//	reconciler.EnableImpersonation(cfg)
+//	mgr, err := ctrl.NewManager(cfg, ctrl.Options{})
+func EnableImpersonation(cfg *rest.Config) {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonatingTransport{delegate: rt}
+	})
+	impersonationEnabled.Store(true)
+}
+
+type impersonatingTransport struct {
+	delegate http.RoundTripper
+}
+
+func (t *impersonatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	user, ok := ImpersonatedUser(req.Context())
+	if !ok {
+		return t.delegate.RoundTrip(req)
+	}
+	return transport.NewImpersonatingRoundTripper(transport.ImpersonationConfig{
+		UserName: user.UserName,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Extra:    user.Extra,
+	}, t.delegate).RoundTrip(req)
+}
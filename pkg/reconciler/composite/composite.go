@@ -0,0 +1,177 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler (CompositeReconciler) runs an ordered list of reconcilers as a single one, with one Descriptor and one
+// rolled-up condition. It packages reusable building blocks, e.g. a "CertificateStack" made of Issuer, Certificate and
+// Secret reconcilers, shared across operators.
+type Reconciler[Parent client.Object] struct {
+	// Details describes the composite. Its ChildGVKs are completed with those of the reconcilers.
+	Details api.Descriptor // required
+	// Reconcilers are run in order, stopping at the first error or requeue. Their RequiredConditions and DependsOn
+	// are not evaluated, the order of the list takes precedence.
+	Reconcilers []api.Reconciler[Parent] // required
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.ChildDescriber            = &Reconciler[client.Object]{}
+	_ api.Finalizer[client.Object]  = &Reconciler[client.Object]{}
+	_ api.Cleaner[client.Object]    = &Reconciler[client.Object]{}
+)
+
+// Reconcile runs the reconcilers in order. The conditions they add are rolled up into a single `<Name>Reconciled`
+// condition, or a `<Name>Error` condition on error, added to the conductor State.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	// The reconcilers add their conditions to a State of their own, rolled up once they ran. Values are shared with
+	// the State of the conductor.
+	inner := &conductor.State{}
+	if state, err := conductor.FetchState(ctx); err == nil {
+		inner = state.Nested()
+	}
+	innerCtx, err := conductor.BindState(conductor.ClearState(ctx), inner)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{}
+	for _, reconciler := range r.Reconcilers {
+		result, err = reconciler.Reconcile(innerCtx, k8sCli, parent)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", reconciler.Describe().Name, err)
+			break
+		}
+		if result.Requeue || result.RequeueAfter > 0 {
+			break
+		}
+	}
+
+	if err != nil {
+		conductor.RecordResult(ctx, r.Details.Name, result, err)
+		return result, err
+	}
+	if state, stateErr := conductor.FetchState(ctx); stateErr == nil {
+		state.AddCondition(r.rollUp(inner, result))
+	}
+	return result, nil
+}
+
+// rollUp returns the `<Name>Reconciled` condition of the composite, listing the conditions of the reconcilers that
+// are not a successful `<Name>Reconciled` condition in its message.
+func (r *Reconciler[Parent]) rollUp(inner *conductor.State, result reconcile.Result) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    fmt.Sprintf("%sReconciled", r.Details.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciled",
+		Message: "Reconciled successfully",
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	}
+	if result.Requeue || result.RequeueAfter > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InProgress"
+		condition.Message = "Reconciling"
+	}
+
+	var details []string
+	for _, c := range inner.Conditions {
+		if strings.HasSuffix(c.Type, "Reconciled") && c.Status == metav1.ConditionTrue {
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s=%s (%s): %s", c.Type, c.Status, c.Reason, c.Message))
+	}
+	if len(details) > 0 {
+		slices.Sort(details)
+		condition.Message += "; " + strings.Join(details, "; ")
+	}
+	return condition
+}
+
+// Describe returns the Details, with the ChildGVKs of the reconcilers.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	desc := r.Details
+	desc.ChildGVKs = slices.Clone(desc.ChildGVKs)
+	for _, reconciler := range r.Reconcilers {
+		for _, gvk := range reconciler.Describe().ChildGVKs {
+			if !slices.Contains(desc.ChildGVKs, gvk) {
+				desc.ChildGVKs = append(desc.ChildGVKs, gvk)
+			}
+		}
+	}
+	return desc
+}
+
+// Children returns the children declared by the reconcilers implementing api.ChildDescriber.
+func (r *Reconciler[Parent]) Children() []client.Object {
+	var children []client.Object
+	for _, reconciler := range r.Reconcilers {
+		if describer, ok := reconciler.(api.ChildDescriber); ok {
+			children = append(children, describer.Children()...)
+		}
+	}
+	return children
+}
+
+// Validate validates the reconcilers implementing api.Validator.
+func (r *Reconciler[Parent]) Validate() error {
+	var errs []error
+	for _, reconciler := range r.Reconcilers {
+		if validator, ok := reconciler.(api.Validator); ok {
+			if err := validator.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", r.Details.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Finalize finalizes the reconcilers implementing api.Finalizer in reverse order, stopping at the first error or
+// requeue.
+func (r *Reconciler[Parent]) Finalize(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	for _, reconciler := range slices.Backward(r.Reconcilers) {
+		finalizer, ok := reconciler.(api.Finalizer[Parent])
+		if !ok {
+			continue
+		}
+		result, err := finalizer.Finalize(ctx, k8sCli, parent)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", reconciler.Describe().Name, err)
+		}
+		if result.Requeue || result.RequeueAfter > 0 {
+			return result, nil
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// Cleanup cleans up the reconcilers implementing api.Cleaner in reverse order, stopping at the first error or requeue.
+func (r *Reconciler[Parent]) Cleanup(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	for _, reconciler := range slices.Backward(r.Reconcilers) {
+		cleaner, ok := reconciler.(api.Cleaner[Parent])
+		if !ok {
+			continue
+		}
+		result, err := cleaner.Cleanup(ctx, k8sCli, parent)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", reconciler.Describe().Name, err)
+		}
+		if result.Requeue || result.RequeueAfter > 0 {
+			return result, nil
+		}
+	}
+	return reconcile.Result{}, nil
+}
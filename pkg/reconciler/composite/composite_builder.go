@@ -0,0 +1,33 @@
+package composite
+
+import (
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// Compose returns a new instance of Builder running the reconcilers in order as a single reconciler described by
+// details.
+func Compose[Parent client.Object](details api.Descriptor, reconcilers ...api.Reconciler[Parent]) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			Details:     details,
+			Reconcilers: reconcilers,
+		},
+	}
+}
+
+// WithReconcilers appends reconcilers to the Reconcilers field.
+func (b *Builder[Parent]) WithReconcilers(reconcilers ...api.Reconciler[Parent]) *Builder[Parent] {
+	b.reconciler.Reconcilers = append(b.reconciler.Reconcilers, reconcilers...)
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
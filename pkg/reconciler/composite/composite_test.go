@@ -0,0 +1,82 @@
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type funcReconciler struct {
+	name string
+	fn   func() (reconcile.Result, error)
+}
+
+func (f *funcReconciler) Describe() api.Descriptor {
+	return api.Descriptor{Name: f.name}
+}
+
+func (f *funcReconciler) Reconcile(context.Context, client.Client, *corev1.ConfigMap) (reconcile.Result, error) {
+	return f.fn()
+}
+
+func childReconciler(name string) *simple.Reconciler[*corev1.ConfigMap, *corev1.Secret] {
+	return simple.FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Secret, error) {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-" + name, Namespace: parent.Namespace}}, nil
+	}).WithDetails(api.Descriptor{Name: name}).Build()
+}
+
+func TestComposite(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+
+	stack := Compose[*corev1.ConfigMap](api.Descriptor{Name: "CertificateStack"},
+		childReconciler("Issuer"), childReconciler("Certificate")).Build()
+	assert.Len(t, stack.Children(), 2)
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+
+	// Each reconcile creates one child and requeues
+	for range 2 {
+		result, err := stack.Reconcile(ctx, k8sCli, parent)
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+	}
+	require.Len(t, state.Conditions, 1)
+	assert.Equal(t, "CertificateStackReconciled", state.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, state.Conditions[0].Status)
+
+	result, err := stack.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	require.Len(t, state.Conditions, 1)
+	assert.Equal(t, metav1.ConditionTrue, state.Conditions[0].Status)
+
+	secrets := &corev1.SecretList{}
+	require.NoError(t, k8sCli.List(ctx, secrets))
+	assert.Len(t, secrets.Items, 2)
+
+	// Errors are reported under the name of the composite, with the failing reconciler
+	stack.Reconcilers = append(stack.Reconcilers, &funcReconciler{name: "Broken", fn: func() (reconcile.Result, error) {
+		return reconcile.Result{}, assert.AnError
+	}})
+	_, err = stack.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, assert.AnError)
+	errCondition := state.FindCondition("CertificateStackError")
+	require.NotNil(t, errCondition)
+	assert.Contains(t, errCondition.Message, "Broken")
+}
@@ -0,0 +1,73 @@
+package reconciler
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConditionsAccessor is implemented by objects exposing their status conditions directly. Objects implementing it skip
+// the unstructured conversion done by ConditionsFromObject and SetObjectConditions.
+type ConditionsAccessor interface {
+	GetConditions() []metav1.Condition
+	SetConditions(conditions []metav1.Condition)
+}
+
+// ConditionsFromObject returns the conditions found at .status.conditions of the object.
+// Objects without a status or without conditions return an empty slice.
+func ConditionsFromObject(obj client.Object) ([]metav1.Condition, error) {
+	if accessor, ok := obj.(ConditionsAccessor); ok {
+		return accessor.GetConditions(), nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, found, err := unstructured.NestedSlice(content, "status", "conditions")
+	if err != nil || !found {
+		return nil, err
+	}
+
+	conditions := make([]metav1.Condition, 0, len(raw))
+	for _, item := range raw {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var condition metav1.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(entry, &condition); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// SetObjectConditions replaces the conditions found at .status.conditions of the object.
+func SetObjectConditions(obj client.Object, conditions []metav1.Condition) error {
+	if accessor, ok := obj.(ConditionsAccessor); ok {
+		accessor.SetConditions(conditions)
+		return nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	raw := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		entry, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		raw = append(raw, entry)
+	}
+	if err := unstructured.SetNestedSlice(content, raw, "status", "conditions"); err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, obj)
+}
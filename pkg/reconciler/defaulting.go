@@ -0,0 +1,95 @@
+package reconciler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultDefaultingCacheSize is the number of entries kept by a DefaultingCache.
+	DefaultDefaultingCacheSize = 1024
+	// DefaultDefaultingCacheTTL is the duration for which a DefaultingCache entry is trusted.
+	DefaultDefaultingCacheTTL = 10 * time.Minute
+)
+
+// DefaultingCache remembers the dry-runs showing that a desired child only differs from the current one by the
+// defaults set by the API server. While neither the desired nor the current child changes, the comparison and the
+// dry-runs are skipped, until the entry expires after the TTL.
+// Entries are keyed by the kind and key of the child and the hashes of both objects (see HashObject), so the status
+// and the fields set by the API server don't invalidate them. The cache is kept in memory, so a single cache should be
+// shared across reconciles. The zero value is ready to use.
+type DefaultingCache struct {
+	// Size bounds the number of entries, an arbitrary entry is evicted when it is reached. Defaults to
+	// DefaultDefaultingCacheSize.
+	Size int
+	// TTL is the duration after which an entry expires, so the child is compared and dry-run again. Defaults to
+	// DefaultDefaultingCacheTTL.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// Clean returns true if a dry-run showed that the desired child only differs from the current one by defaults, less
+// than the TTL before now. Objects that can't be hashed are never clean.
+func (c *DefaultingCache) Clean(current, desired client.Object, now time.Time) bool {
+	key, err := defaultingKey(current, desired)
+	if err != nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.entries[key]
+	if ok && !now.Before(expires) {
+		delete(c.entries, key)
+		return false
+	}
+	return ok
+}
+
+// Record records that a dry-run showed at now that the desired child only differs from the current one by defaults.
+func (c *DefaultingCache) Record(current, desired client.Object, now time.Time) {
+	key, err := defaultingKey(current, desired)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]time.Time{}
+	}
+	size := c.Size
+	if size <= 0 {
+		size = DefaultDefaultingCacheSize
+	}
+	for evicted := range c.entries {
+		if len(c.entries) < size {
+			break
+		}
+		delete(c.entries, evicted)
+	}
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultDefaultingCacheTTL
+	}
+	c.entries[key] = now.Add(ttl)
+}
+
+// defaultingKey returns the key of the entry of the current and desired child.
+func defaultingKey(current, desired client.Object) (string, error) {
+	currentHash, err := HashObject(current)
+	if err != nil {
+		return "", err
+	}
+	desiredHash, err := HashObject(desired)
+	if err != nil {
+		return "", err
+	}
+	// Typed objects usually have no kind set, so the Go type identifies the kind.
+	return fmt.Sprintf("%T/%s/%s/%s/%s", desired, desired.GetObjectKind().GroupVersionKind(),
+		client.ObjectKeyFromObject(desired), desiredHash, currentHash), nil
+}
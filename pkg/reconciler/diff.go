@@ -0,0 +1,191 @@
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pmezard/go-difflib/difflib"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// DiffRenderer renders the difference between the current and the desired state of an object, for logs and reports.
+type DiffRenderer interface {
+	RenderDiff(current, desired client.Object) (string, error)
+}
+
+// CmpDiffRenderer renders diffs with cmp.Diff, as Go structs.
+type CmpDiffRenderer struct {
+	// Opts are the options passed to cmp.Diff.
+	Opts []cmp.Option
+}
+
+var _ DiffRenderer = CmpDiffRenderer{}
+
+// RenderDiff returns the cmp.Diff of the objects.
+func (r CmpDiffRenderer) RenderDiff(current, desired client.Object) (string, error) {
+	return cmp.Diff(current, desired, r.Opts...), nil
+}
+
+// YAMLDiffRenderer renders diffs like kubectl diff: a unified diff of the YAML of both objects, without their
+// managedFields.
+type YAMLDiffRenderer struct {
+	// Context is the number of unchanged lines shown around each change. Defaults to 3.
+	Context int
+}
+
+var _ DiffRenderer = YAMLDiffRenderer{}
+
+// RenderDiff returns the unified diff of the YAML of the objects, or an empty string if they render the same.
+// A nil current object renders as empty, as for a creation.
+func (r YAMLDiffRenderer) RenderDiff(current, desired client.Object) (string, error) {
+	from, err := renderYAML(current)
+	if err != nil {
+		return "", err
+	}
+	to, err := renderYAML(desired)
+	if err != nil {
+		return "", err
+	}
+
+	context := r.Context
+	if context == 0 {
+		context = 3
+	}
+	name := "object"
+	if desired != nil {
+		name = client.ObjectKeyFromObject(desired).String()
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: "current/" + name,
+		ToFile:   "desired/" + name,
+		Context:  context,
+	})
+}
+
+// renderYAML renders the object as YAML, without its managedFields.
+func renderYAML(obj client.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert object for diff: %w", err)
+	}
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("unable to render object for diff: %w", err)
+	}
+	return string(out), nil
+}
+
+// RedactingDiffRenderer redacts sensitive values before rendering diffs, so they don't leak into logs and reports.
+// The data and stringData of Secrets are always redacted. Redacted values are replaced with a short hash, which
+// still shows whether they changed.
+type RedactingDiffRenderer struct {
+	// Renderer renders the redacted objects. Defaults to a CmpDiffRenderer.
+	Renderer DiffRenderer
+	// SensitiveFields are the dot-separated paths of other fields to redact, e.g. "spec.credentials.password".
+	// String values and maps of strings are redacted.
+	SensitiveFields []string
+}
+
+var _ DiffRenderer = RedactingDiffRenderer{}
+
+// RenderDiff redacts the objects, then renders their diff with the Renderer.
+func (r RedactingDiffRenderer) RenderDiff(current, desired client.Object) (string, error) {
+	renderer := r.Renderer
+	if renderer == nil {
+		renderer = CmpDiffRenderer{}
+	}
+	current, err := Redact(current, r.SensitiveFields...)
+	if err != nil {
+		return "", err
+	}
+	desired, err = Redact(desired, r.SensitiveFields...)
+	if err != nil {
+		return "", err
+	}
+	return renderer.RenderDiff(current, desired)
+}
+
+// Redact returns a copy of the object with the data of Secrets and the sensitive fields (dot-separated paths)
+// replaced with a short hash of their values. The object itself is left untouched.
+func Redact(obj client.Object, sensitiveFields ...string) (client.Object, error) {
+	if obj == nil || reflect.ValueOf(obj).IsNil() {
+		return obj, nil
+	}
+	redacted := obj.DeepCopyObject().(client.Object)
+	if secret, ok := redacted.(*corev1.Secret); ok {
+		for key, value := range secret.Data {
+			secret.Data[key] = []byte(redactedValue(value))
+		}
+		for key, value := range secret.StringData {
+			secret.StringData[key] = redactedValue([]byte(value))
+		}
+	}
+
+	paths := make([][]string, 0, len(sensitiveFields)+2)
+	for _, field := range sensitiveFields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+	if u, ok := redacted.(*unstructured.Unstructured); ok && u.GroupVersionKind().GroupKind() == (schema.GroupKind{Kind: "Secret"}) {
+		paths = append(paths, []string{"data"}, []string{"stringData"})
+	}
+	if len(paths) == 0 {
+		return redacted, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert object for redaction: %w", err)
+	}
+	for _, path := range paths {
+		redactField(content, path)
+	}
+	if u, ok := redacted.(*unstructured.Unstructured); ok {
+		u.Object = content
+		return u, nil
+	}
+	value := reflect.ValueOf(redacted).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, redacted); err != nil {
+		return nil, fmt.Errorf("unable to convert object for redaction: %w", err)
+	}
+	return redacted, nil
+}
+
+// redactField redacts the string, or the strings of the map, at the path of the content.
+func redactField(content map[string]any, path []string) {
+	value, found, err := unstructured.NestedFieldNoCopy(content, path...)
+	if !found || err != nil {
+		return
+	}
+	switch value := value.(type) {
+	case string:
+		_ = unstructured.SetNestedField(content, redactedValue([]byte(value)), path...)
+	case map[string]any:
+		for key, item := range value {
+			if item, ok := item.(string); ok {
+				value[key] = redactedValue([]byte(item))
+			}
+		}
+	}
+}
+
+// redactedValue returns the placeholder of a redacted value.
+func redactedValue(value []byte) string {
+	sum := sha256.Sum256(value)
+	return "<redacted sha256:" + hex.EncodeToString(sum[:4]) + ">"
+}
@@ -2,14 +2,22 @@ package reconciler
 
 import (
 	"errors"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 )
 
 var ErrChildKeyMismatch = errors.New("child key mismatch")
+var ErrNotOwned = errors.New("object exists but is not owned by the parent")
+
+// ErrForeignOwner is returned when an object is controlled by another owner. It wraps ErrNotOwned.
+var ErrForeignOwner = fmt.Errorf("%w: controlled by another owner", ErrNotOwned)
 
 func InvertFunc[T client.Object](f func(parent T) bool) func(parent T) bool {
 	return func(parent T) bool {
@@ -35,6 +43,14 @@ func IgnoreStatusFields() cmp.Option {
 	}, cmp.Ignore())
 }
 
+// OnlyStatusFields ignores every field except the Status, for comparing status subresources.
+func OnlyStatusFields() cmp.Option {
+	return cmp.FilterPath(func(p cmp.Path) bool {
+		path := p.String()
+		return path != "" && path != "Status" && !strings.HasPrefix(path, "Status.")
+	}, cmp.Ignore())
+}
+
 func IgnoreManagedFields() cmp.Option {
 	return cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ManagedFields")
 }
@@ -42,3 +58,72 @@ func IgnoreManagedFields() cmp.Option {
 func IgnoreTypeMeta() cmp.Option {
 	return cmpopts.IgnoreFields(metav1.TypeMeta{}, "APIVersion", "Kind")
 }
+
+// CompareQuantities compares resource.Quantity values by their value, so "1000m" equals "1" and "1Gi" equals
+// "1024Mi".
+func CompareQuantities() cmp.Option {
+	return cmp.Comparer(func(a, b resource.Quantity) bool {
+		return a.Cmp(b) == 0
+	})
+}
+
+// CompareIntOrStrings compares intstr.IntOrString values semantically, so the int 8080 equals the string "8080".
+func CompareIntOrStrings() cmp.Option {
+	return cmp.Comparer(func(a, b intstr.IntOrString) bool {
+		return a.String() == b.String()
+	})
+}
+
+// SemanticValues combines CompareQuantities and CompareIntOrStrings.
+func SemanticValues() cmp.Options {
+	return cmp.Options{CompareQuantities(), CompareIntOrStrings()}
+}
+
+// SortedEnvVars compares lists of environment variables regardless of their order.
+// Only use it when no variable references another one with $(VAR), as such references depend on the order.
+func SortedEnvVars() cmp.Option {
+	return cmpopts.SortSlices(func(a, b corev1.EnvVar) bool {
+		return a.Name < b.Name
+	})
+}
+
+// SortedPorts compares lists of container and service ports regardless of their order.
+func SortedPorts() cmp.Options {
+	return cmp.Options{
+		cmpopts.SortSlices(func(a, b corev1.ContainerPort) bool {
+			if a.ContainerPort != b.ContainerPort {
+				return a.ContainerPort < b.ContainerPort
+			}
+			if a.Protocol != b.Protocol {
+				return a.Protocol < b.Protocol
+			}
+			return a.Name < b.Name
+		}),
+		cmpopts.SortSlices(func(a, b corev1.ServicePort) bool {
+			if a.Port != b.Port {
+				return a.Port < b.Port
+			}
+			if a.Protocol != b.Protocol {
+				return a.Protocol < b.Protocol
+			}
+			return a.Name < b.Name
+		}),
+	}
+}
+
+// SortedVolumeMounts compares lists of volume mounts regardless of their order.
+func SortedVolumeMounts() cmp.Option {
+	return cmpopts.SortSlices(func(a, b corev1.VolumeMount) bool {
+		if a.MountPath != b.MountPath {
+			return a.MountPath < b.MountPath
+		}
+		return a.Name < b.Name
+	})
+}
+
+// SortedVolumes compares lists of volumes regardless of their order.
+func SortedVolumes() cmp.Option {
+	return cmpopts.SortSlices(func(a, b corev1.Volume) bool {
+		return a.Name < b.Name
+	})
+}
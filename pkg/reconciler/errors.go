@@ -0,0 +1,32 @@
+package reconciler
+
+import (
+	"errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ChildError is an error about a child object, carrying its key so the error can be attributed to it (see
+// conductor.ReconcileError). Its message is the one of the wrapped error.
+type ChildError struct {
+	Child client.ObjectKey
+	Err   error
+}
+
+func (e *ChildError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ChildError) Unwrap() error {
+	return e.Err
+}
+
+// WithChildKey wraps the error in a ChildError for the child key. It returns nil for a nil error, and the error as is
+// if it already carries the key of a child.
+func WithChildKey(err error, key client.ObjectKey) error {
+	var childErr *ChildError
+	if err == nil || errors.As(err, &childErr) {
+		return err
+	}
+	return &ChildError{Child: key, Err: err}
+}
@@ -0,0 +1,125 @@
+package external
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Observation is the state of the external resource as seen by the ObserveFn.
+type Observation[T any] struct {
+	// Exists reports whether the external resource exists.
+	Exists bool
+	// UpToDate reports whether the external resource matches the state desired by the parent.
+	UpToDate bool
+	// Resource is the observed external resource, passed to the UpdateFn and DeleteFn.
+	Resource T
+}
+
+// Reconciler (ExternalReconciler) reconciles a resource living outside the cluster (a DNS record, a bucket, ...) for
+// a parent object, through Observe, Create, Update and Delete callbacks.
+type Reconciler[Parent client.Object, T any] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	Details api.Descriptor // required
+	// ObserveFn fetches the external resource for the parent and reports whether it exists and is up-to-date.
+	ObserveFn func(ctx context.Context, parent Parent) (Observation[T], error) // required
+	// CreateFn creates the external resource when it doesn't exist.
+	CreateFn func(ctx context.Context, parent Parent) error // required
+	// UpdateFn updates the external resource when it isn't up-to-date. If nil, drift is left untouched.
+	UpdateFn func(ctx context.Context, parent Parent, current T) error // optional
+	// DeleteFn deletes the external resource. It is called when ShouldDeleteFn returns true, and when the parent is
+	// finalized by a conductor managing a finalizer. If nil, the external resource is never deleted.
+	DeleteFn func(ctx context.Context, parent Parent, current T) error // optional
+	// PredicateFn is a function that returns true if the external resource should be created or updated.
+	PredicateFn func(parent Parent) bool // optional
+	// ShouldDeleteFn is a function that if returns true, the external resource will be deleted.
+	// It is called regardless of the PredicateFn function.
+	ShouldDeleteFn func(parent Parent) bool // optional
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object, any]{}
+	_ api.Finalizer[client.Object]  = &Reconciler[client.Object, any]{}
+)
+
+// Reconcile observes the external resource, then creates, updates or deletes it as needed.
+// The parent is requeued after every change, so the next observation confirms it.
+func (r *Reconciler[Parent, T]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent, T]) Describe() api.Descriptor {
+	return r.Details
+}
+
+func (r *Reconciler[Parent, T]) doReconcile(ctx context.Context, parent Parent) (reconcile.Result, error) {
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent), "reconciler", r.Details.Name)
+
+	observation, err := r.ObserveFn(ctx, parent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if r.ShouldDeleteFn != nil && r.ShouldDeleteFn(parent) {
+		return r.delete(ctx, parent, observation)
+	}
+
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	if !observation.Exists {
+		if err := r.CreateFn(ctx, parent); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Info("created external resource")
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationCreate, nil)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	if observation.UpToDate || r.UpdateFn == nil {
+		log.Info("no changes")
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, nil)
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.UpdateFn(ctx, parent, observation.Resource); err != nil {
+		return reconcile.Result{}, err
+	}
+	log.Info("updated external resource")
+	conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationUpdate, nil)
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// Finalize deletes the external resource when the parent is deleted. It requests a requeue until the resource is
+// observed as gone.
+func (r *Reconciler[Parent, T]) Finalize(ctx context.Context, _ client.Client, parent Parent) (reconcile.Result, error) {
+	observation, err := r.ObserveFn(ctx, parent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return r.delete(ctx, parent, observation)
+}
+
+func (r *Reconciler[Parent, T]) delete(ctx context.Context, parent Parent, observation Observation[T]) (reconcile.Result, error) {
+	if !observation.Exists || r.DeleteFn == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.DeleteFn(ctx, parent, observation.Resource); err != nil {
+		return reconcile.Result{}, err
+	}
+	klog.FromContext(ctx).V(1).Info("deleted external resource",
+		"parent", client.ObjectKeyFromObject(parent), "reconciler", r.Details.Name)
+	conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, nil)
+	return reconcile.Result{Requeue: true}, nil
+}
@@ -0,0 +1,64 @@
+package external
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object, T any] struct {
+	reconciler Reconciler[Parent, T]
+}
+
+// FromObserveFunc returns a new instance of Builder observing the external resource with observeFn and creating it
+// with createFn.
+func FromObserveFunc[Parent client.Object, T any](
+	observeFn func(ctx context.Context, parent Parent) (Observation[T], error),
+	createFn func(ctx context.Context, parent Parent) error,
+) *Builder[Parent, T] {
+	return &Builder[Parent, T]{
+		reconciler: Reconciler[Parent, T]{
+			ObserveFn:   observeFn,
+			CreateFn:    createFn,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+		},
+	}
+}
+
+// WithDetails sets the reconciler details.
+func (b *Builder[Parent, T]) WithDetails(details api.Descriptor) *Builder[Parent, T] {
+	b.reconciler.Details = details
+	return b
+}
+
+// WithUpdateFn sets the UpdateFn field.
+func (b *Builder[Parent, T]) WithUpdateFn(updateFn func(ctx context.Context, parent Parent, current T) error) *Builder[Parent, T] {
+	b.reconciler.UpdateFn = updateFn
+	return b
+}
+
+// WithDeleteFn sets the DeleteFn field.
+func (b *Builder[Parent, T]) WithDeleteFn(deleteFn func(ctx context.Context, parent Parent, current T) error) *Builder[Parent, T] {
+	b.reconciler.DeleteFn = deleteFn
+	return b
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent, T]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent, T] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// WithShouldDeleteFn sets the ShouldDeleteFn field.
+func (b *Builder[Parent, T]) WithShouldDeleteFn(shouldDeleteFn func(parent Parent) bool) *Builder[Parent, T] {
+	b.reconciler.ShouldDeleteFn = shouldDeleteFn
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent, T]) Build() *Reconciler[Parent, T] {
+	return &b.reconciler
+}
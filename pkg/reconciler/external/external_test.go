@@ -0,0 +1,102 @@
+package external
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// records is a fake DNS provider mapping record names to their target.
+type records map[string]string
+
+func newRecordReconciler(provider records) *Reconciler[*corev1.ConfigMap, string] {
+	return FromObserveFunc(
+		func(_ context.Context, parent *corev1.ConfigMap) (Observation[string], error) {
+			target, ok := provider[parent.Name]
+			return Observation[string]{Exists: ok, UpToDate: target == parent.Data["target"], Resource: target}, nil
+		},
+		func(_ context.Context, parent *corev1.ConfigMap) error {
+			provider[parent.Name] = parent.Data["target"]
+			return nil
+		},
+	).
+		WithUpdateFn(func(_ context.Context, parent *corev1.ConfigMap, _ string) error {
+			provider[parent.Name] = parent.Data["target"]
+			return nil
+		}).
+		WithDeleteFn(func(_ context.Context, parent *corev1.ConfigMap, _ string) error {
+			delete(provider, parent.Name)
+			return nil
+		}).
+		WithShouldDeleteFn(func(parent *corev1.ConfigMap) bool {
+			return parent.Data["target"] == ""
+		}).
+		WithDetails(api.Descriptor{Name: "DNSRecord"}).
+		Build()
+}
+
+func TestReconcile(t *testing.T) {
+	provider := records{}
+	r := newRecordReconciler(provider)
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Data:       map[string]string{"target": "10.0.0.1"},
+	}
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+
+	// Created when missing
+	result, err := r.Reconcile(ctx, nil, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, records{"app": "10.0.0.1"}, provider)
+
+	// Nothing to do once up-to-date
+	result, err = r.Reconcile(ctx, nil, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+	condition := state.FindCondition("DNSRecordReconciled")
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+
+	// Drift is corrected
+	parent.Data["target"] = "10.0.0.2"
+	result, err = r.Reconcile(ctx, nil, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, records{"app": "10.0.0.2"}, provider)
+
+	// Deleted when ShouldDeleteFn returns true
+	parent.Data["target"] = ""
+	result, err = r.Reconcile(ctx, nil, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Empty(t, provider)
+}
+
+func TestFinalize(t *testing.T) {
+	provider := records{"app": "10.0.0.1"}
+	r := newRecordReconciler(provider)
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Data:       map[string]string{"target": "10.0.0.1"},
+	}
+
+	// The first pass deletes the record and waits for it to be gone
+	result, err := r.Finalize(context.Background(), nil, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Empty(t, provider)
+
+	result, err = r.Finalize(context.Background(), nil, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+}
@@ -0,0 +1,161 @@
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultFlapThreshold is the number of identical updates within the window considered flapping.
+	DefaultFlapThreshold = 3
+	// DefaultFlapWindow is the window in which identical updates are counted.
+	DefaultFlapWindow = 5 * time.Minute
+)
+
+// FlapDetector detects update loops, where the same update is applied to a child again and again, typically because
+// another controller reverts it. It keeps the recent updates per child in memory, so a single detector should be
+// shared across reconciles (and across rebuilt reconcilers). The zero value is ready to use.
+type FlapDetector struct {
+	// Threshold is the number of identical updates within the Window considered flapping. Defaults to
+	// DefaultFlapThreshold.
+	Threshold int
+	// Window is the duration in which identical updates are counted. Defaults to DefaultFlapWindow.
+	Window time.Duration
+
+	mu      sync.Mutex
+	updates map[string][]flapUpdate
+}
+
+// flapUpdate is an update applied to a child.
+type flapUpdate struct {
+	hash string
+	at   time.Time
+}
+
+// Update is the fingerprint of an update of a child.
+type Update struct {
+	// Key identifies the child.
+	Key string
+	// Hash is the hash of the changes.
+	Hash string
+	// Fields are the paths of the changed fields.
+	Fields []string
+}
+
+// UpdateFor returns the fingerprint of the update from the current to the desired object: the fields set on the
+// desired object that differ from the current one. The LastUpdatedAnnotation is ignored.
+func UpdateFor(current, desired client.Object) (Update, error) {
+	// Typed objects usually have no kind set, so the Go type identifies the kind.
+	key := fmt.Sprintf("%T/%s", desired, client.ObjectKeyFromObject(desired))
+	patch, err := PatchFor(UpdateStrategyMergePatch, current, desired)
+	if err != nil || patch == nil {
+		return Update{Key: key}, err
+	}
+	data, err := patch.Data(desired)
+	if err != nil {
+		return Update{Key: key}, err
+	}
+	var changes map[string]any
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return Update{Key: key}, err
+	}
+	if metadata, ok := changes["metadata"].(map[string]any); ok {
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			delete(annotations, LastUpdatedAnnotation)
+		}
+	}
+
+	var fields []string
+	collectFields("", changes, &fields)
+	sort.Strings(fields)
+	// Maps are marshalled with sorted keys, making the hash stable.
+	normalized, err := json.Marshal(changes)
+	if err != nil {
+		return Update{Key: key}, err
+	}
+	sum := sha256.Sum256(normalized)
+	return Update{Key: key, Hash: hex.EncodeToString(sum[:]), Fields: fields}, nil
+}
+
+// collectFields appends the dot-separated paths of the leaves of the changes.
+func collectFields(prefix string, changes map[string]any, fields *[]string) {
+	for name, value := range changes {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if nested, ok := value.(map[string]any); ok {
+			collectFields(path, nested, fields)
+			continue
+		}
+		*fields = append(*fields, path)
+	}
+}
+
+// Flapping returns true if the update was already applied Threshold times within the Window.
+func (d *FlapDetector) Flapping(update Update, now time.Time) bool {
+	if update.Hash == "" {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	count := 0
+	for _, applied := range d.prune(update.Key, now) {
+		if applied.hash == update.Hash {
+			count++
+		}
+	}
+	return count >= d.threshold()
+}
+
+// Record records that the update was applied.
+func (d *FlapDetector) Record(update Update, now time.Time) {
+	if update.Hash == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.updates == nil {
+		d.updates = map[string][]flapUpdate{}
+	}
+	updates := append(d.prune(update.Key, now), flapUpdate{hash: update.Hash, at: now})
+	// Only the updates that can reach the threshold are kept.
+	if limit := d.threshold() * 4; len(updates) > limit {
+		updates = updates[len(updates)-limit:]
+	}
+	d.updates[update.Key] = updates
+}
+
+// prune drops the updates of the child older than the Window, and returns the remaining ones. d.mu must be held.
+func (d *FlapDetector) prune(key string, now time.Time) []flapUpdate {
+	window := d.Window
+	if window <= 0 {
+		window = DefaultFlapWindow
+	}
+	updates := d.updates[key]
+	i := 0
+	for i < len(updates) && now.Sub(updates[i].at) > window {
+		i++
+	}
+	updates = updates[i:]
+	if len(updates) == 0 {
+		delete(d.updates, key)
+	}
+	return updates
+}
+
+func (d *FlapDetector) threshold() int {
+	if d.Threshold <= 0 {
+		return DefaultFlapThreshold
+	}
+	return d.Threshold
+}
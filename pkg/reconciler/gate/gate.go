@@ -0,0 +1,133 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultRequeueAfter is the delay used to requeue the parent while waiting on the dependency.
+const DefaultRequeueAfter = 10 * time.Second
+
+// Reconciler (GateReconciler) wraps another reconciler and only runs it once a dependency object in the cluster
+// reports the given condition.
+type Reconciler[Parent client.Object, Dependency client.Object] struct {
+	// Inner is the reconciler run once the dependency is ready.
+	Inner api.Reconciler[Parent] // required
+	// DependencyKeyFn returns the dependency object with only a key (name and namespace) set.
+	// The key is typically taken from a reference in the parent spec.
+	DependencyKeyFn func(Parent) Dependency // required
+	// ConditionType is the condition the dependency must report.
+	ConditionType string // required
+	// ConditionStatus is the status the condition must have. Defaults to True.
+	ConditionStatus metav1.ConditionStatus // optional
+	// RequeueAfter is the delay used to requeue the parent while waiting. Defaults to DefaultRequeueAfter.
+	RequeueAfter time.Duration // optional
+}
+
+var _ api.Reconciler[client.Object] = &Reconciler[client.Object, client.Object]{}
+
+// Reconcile fetches the dependency and checks its condition. If the condition is not met, the inner reconciler is
+// skipped, a Waiting condition is added to the conductor State and the parent is requeued.
+func (r *Reconciler[Parent, Dependency]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	dependency := r.DependencyKeyFn(parent)
+	key := client.ObjectKeyFromObject(dependency)
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent), "dependency", key)
+
+	reason, message, err := r.check(ctx, k8sCli, dependency)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if reason == "" {
+		return r.Inner.Reconcile(ctx, k8sCli, parent)
+	}
+
+	log.Info("waiting on dependency", "reason", reason)
+	if state, err := conductor.FetchState(ctx); err == nil {
+		state.AddCondition(metav1.Condition{
+			Type:    fmt.Sprintf("%sWaiting", r.Describe().Name),
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+			LastTransitionTime: metav1.Time{
+				Time: time.Now(),
+			},
+		})
+	}
+
+	return reconcile.Result{
+		RequeueAfter: r.requeueAfter(),
+	}, nil
+}
+
+// Describe returns the descriptor of the inner reconciler.
+func (r *Reconciler[Parent, Dependency]) Describe() api.Descriptor {
+	return r.Inner.Describe()
+}
+
+// Children returns the children of the inner reconciler, if it declares any.
+func (r *Reconciler[Parent, Dependency]) Children() []client.Object {
+	if describer, ok := r.Inner.(api.ChildDescriber); ok {
+		return describer.Children()
+	}
+	return nil
+}
+
+// Validate validates the inner reconciler, if it implements api.Validator.
+func (r *Reconciler[Parent, Dependency]) Validate() error {
+	if validator, ok := r.Inner.(api.Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// check returns an empty reason if the dependency reports the expected condition.
+func (r *Reconciler[Parent, Dependency]) check(ctx context.Context, k8sCli client.Client, dependency Dependency) (string, string, error) {
+	key := client.ObjectKeyFromObject(dependency)
+	if key.Name == "" {
+		return "DependencyNotReferenced", "Dependency is not referenced by the parent", nil
+	}
+
+	if err := k8sCli.Get(ctx, key, dependency); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "DependencyNotFound", fmt.Sprintf("Dependency %s was not found", key), nil
+		}
+		return "", "", err
+	}
+
+	conditions, err := reconciler.ConditionsFromObject(dependency)
+	if err != nil {
+		return "", "", err
+	}
+
+	condition := meta.FindStatusCondition(conditions, r.ConditionType)
+	if condition == nil || condition.Status != r.conditionStatus() {
+		return "DependencyNotReady", fmt.Sprintf("Waiting for %s to report %s=%s", key, r.ConditionType, r.conditionStatus()), nil
+	}
+	return "", "", nil
+}
+
+func (r *Reconciler[Parent, Dependency]) conditionStatus() metav1.ConditionStatus {
+	if r.ConditionStatus == "" {
+		return metav1.ConditionTrue
+	}
+	return r.ConditionStatus
+}
+
+func (r *Reconciler[Parent, Dependency]) requeueAfter() time.Duration {
+	if r.RequeueAfter <= 0 {
+		return DefaultRequeueAfter
+	}
+	return r.RequeueAfter
+}
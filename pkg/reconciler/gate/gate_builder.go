@@ -0,0 +1,49 @@
+package gate
+
+import (
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object, Dependency client.Object] struct {
+	reconciler Reconciler[Parent, Dependency]
+}
+
+// WaitFor returns a new instance of Builder that gates the inner reconciler on the dependency returned by keyFn
+// reporting conditionType=True.
+func WaitFor[Parent client.Object, Dependency client.Object](
+	inner api.Reconciler[Parent],
+	keyFn func(Parent) Dependency,
+	conditionType string,
+) *Builder[Parent, Dependency] {
+	return &Builder[Parent, Dependency]{
+		reconciler: Reconciler[Parent, Dependency]{
+			Inner:           inner,
+			DependencyKeyFn: keyFn,
+			ConditionType:   conditionType,
+			ConditionStatus: metav1.ConditionTrue,
+			RequeueAfter:    DefaultRequeueAfter,
+		},
+	}
+}
+
+// WithConditionStatus sets the ConditionStatus field.
+func (b *Builder[Parent, Dependency]) WithConditionStatus(status metav1.ConditionStatus) *Builder[Parent, Dependency] {
+	b.reconciler.ConditionStatus = status
+	return b
+}
+
+// WithRequeueAfter sets the RequeueAfter field.
+func (b *Builder[Parent, Dependency]) WithRequeueAfter(after time.Duration) *Builder[Parent, Dependency] {
+	b.reconciler.RequeueAfter = after
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent, Dependency]) Build() *Reconciler[Parent, Dependency] {
+	return &b.reconciler
+}
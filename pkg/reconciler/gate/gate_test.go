@@ -0,0 +1,71 @@
+package gate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type countingReconciler struct {
+	calls int
+}
+
+func (c *countingReconciler) Describe() api.Descriptor {
+	return api.Descriptor{Name: "Counting"}
+}
+
+func (c *countingReconciler) Reconcile(context.Context, client.Client, *corev1.ConfigMap) (reconcile.Result, error) {
+	c.calls++
+	return reconcile.Result{}, nil
+}
+
+func TestWaitForDependency(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Data:       map[string]string{"databaseRef": "database"},
+	}
+	database := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "default"},
+	}
+
+	inner := &countingReconciler{}
+	gated := WaitFor(inner, func(parent *corev1.ConfigMap) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: parent.Data["databaseRef"], Namespace: parent.Namespace}}
+	}, string(corev1.PodReady)).Build()
+
+	ctx := context.Background()
+
+	// Dependency does not exist yet
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+	result, err := gated.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRequeueAfter, result.RequeueAfter)
+	assert.Zero(t, inner.calls)
+
+	// Dependency exists but is not ready
+	k8sCli = fake.NewClientBuilder().WithScheme(s).WithObjects(database).Build()
+	result, err = gated.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRequeueAfter, result.RequeueAfter)
+	assert.Zero(t, inner.calls)
+
+	// Dependency is ready
+	database.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	k8sCli = fake.NewClientBuilder().WithScheme(s).WithObjects(database).Build()
+	result, err = gated.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, 1, inner.calls)
+}
@@ -0,0 +1,140 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// DefaultCollectInterval is the interval between two collections of a Collector without an Interval.
+const DefaultCollectInterval = 10 * time.Minute
+
+// Collector deletes the children owned through the owner labels of reconciler.MarkOwned whose parent no longer exists,
+// e.g. because it was deleted without a finalizer or while the operator was down. It runs periodically once added to
+// the manager, on the leader only.
+//
+// Only the children recording the kind of their parent in the reconciler.OwnerKindAnnotation are collected, so the
+// children of other kinds of parents are never deleted by mistake.
+type Collector struct {
+	// Client is the client used to list the children, read the parents and delete the children.
+	Client client.Client // required
+	// APIReader reads the parents that appear missing or recreated through the Client, uncached, typically
+	// mgr.GetAPIReader(): a parent created moments ago may not be in the cache of the manager client yet, and its
+	// children must not be deleted.
+	APIReader client.Reader // required
+	// ParentGVK is the kind of the parents.
+	ParentGVK schema.GroupVersionKind // required
+	// ChildGVKs are the kinds of the children to collect, in every namespace.
+	ChildGVKs []schema.GroupVersionKind // required
+	// Interval is the interval between two collections. Defaults to DefaultCollectInterval.
+	Interval time.Duration // optional
+}
+
+var (
+	_ manager.Runnable               = &Collector{}
+	_ manager.LeaderElectionRunnable = &Collector{}
+)
+
+// Start collects the orphaned children every Interval until the context is done.
+func (c *Collector) Start(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultCollectInterval
+	}
+	log := klog.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.Collect(ctx); err != nil {
+			log.Error(err, "unable to collect orphaned children", "parentKind", c.ParentGVK.Kind)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns true, so children are only collected by the leader.
+func (c *Collector) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect deletes the orphaned children once, and returns the number of children deleted.
+func (c *Collector) Collect(ctx context.Context) (int, error) {
+	if c.APIReader == nil {
+		return 0, fmt.Errorf("%w: the collector has no API reader", reconciler.ErrInvalidConfiguration)
+	}
+	log := klog.FromContext(ctx).V(1)
+	kind := c.ParentGVK.GroupKind().String()
+	parents := map[client.ObjectKey]*metav1.PartialObjectMetadata{}
+	// verified are the parents read through the APIReader.
+	verified := map[client.ObjectKey]bool{}
+
+	deleted := 0
+	for _, gvk := range c.ChildGVKs {
+		children, err := list(ctx, c.Client, gvk, client.HasLabels{reconciler.OwnerUIDLabel})
+		if err != nil {
+			return deleted, err
+		}
+		for _, child := range children {
+			key, ok := reconciler.OwnerKey(child)
+			if !ok || child.GetAnnotations()[reconciler.OwnerKindAnnotation] != kind || !child.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+
+			parent, seen := parents[key]
+			if !seen {
+				if parent, err = c.parent(ctx, c.Client, key); err != nil {
+					return deleted, err
+				}
+				parents[key] = parent
+			}
+			// The cache may not have the parent yet, or still have the previous one: check it uncached before deleting.
+			if !ownedBy(child, parent) && !verified[key] {
+				if parent, err = c.parent(ctx, c.APIReader, key); err != nil {
+					return deleted, err
+				}
+				parents[key], verified[key] = parent, true
+			}
+			if ownedBy(child, parent) {
+				continue
+			}
+
+			if err := c.Client.Delete(ctx, child); client.IgnoreNotFound(err) != nil {
+				return deleted, err
+			}
+			log.Info("deleted orphaned child", "child", child.Name, "namespace", child.Namespace, "kind", gvk.Kind, "parent", key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// ownedBy returns true if the parent exists and owns the child. A parent recreated with the same name doesn't own the
+// children of the previous one.
+func ownedBy(child client.Object, parent *metav1.PartialObjectMetadata) bool {
+	return parent != nil && string(parent.GetUID()) == child.GetLabels()[reconciler.OwnerUIDLabel]
+}
+
+// parent returns the metadata of the parent read through the reader, or nil if it doesn't exist.
+func (c *Collector) parent(ctx context.Context, reader client.Reader, key client.ObjectKey) (*metav1.PartialObjectMetadata, error) {
+	parent := &metav1.PartialObjectMetadata{}
+	parent.SetGroupVersionKind(c.ParentGVK)
+	if err := reader.Get(ctx, key, parent); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parent, nil
+}
@@ -0,0 +1,115 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DeletionRequeueAfter is the delay used to requeue the parent while its children are being deleted.
+const DeletionRequeueAfter = 5 * time.Second
+
+// Reconciler (GCReconciler) deletes the children owned by the parent through the owner labels of
+// reconciler.MarkOwned once the parent is deleted. The Kubernetes garbage collector only follows owner references, which
+// can't be set on children in other namespaces than the parent, or on cluster-scoped children of a namespaced parent.
+//
+// The children are deleted by Finalize, when the conductor manages a finalizer on the parent, or by Reconcile when the
+// parent is marked for deletion. Use a Collector for the parents deleted without a finalizer.
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	// The ChildGVKs are the kinds of the children to delete, in every namespace.
+	Details api.Descriptor // required
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.Finalizer[client.Object]  = &Reconciler[client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object]{}
+)
+
+// Reconcile deletes the children of the parent if it is marked for deletion, and does nothing otherwise.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if parent.GetDeletionTimestamp().IsZero() {
+		return reconcile.Result{}, nil
+	}
+	result, err := r.collect(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Finalize deletes the children of the parent, and requeues the parent after the DeletionRequeueAfter delay until they
+// are gone.
+func (r *Reconciler[Parent]) Finalize(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	return r.collect(ctx, k8sCli, parent)
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// Validate returns an ErrInvalidConfiguration error if the Details name or the ChildGVKs are missing.
+func (r *Reconciler[Parent]) Validate() error {
+	var errs []error
+	if r.Details.Name == "" {
+		errs = append(errs, fmt.Errorf("%w: the Details name of the gc reconciler is empty", reconciler.ErrInvalidConfiguration))
+	}
+	if len(r.Details.ChildGVKs) == 0 {
+		errs = append(errs, fmt.Errorf("%w: %s has no ChildGVKs to collect", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler[Parent]) collect(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent))
+
+	remaining := 0
+	for _, gvk := range r.Details.ChildGVKs {
+		children, err := list(ctx, k8sCli, gvk, client.MatchingLabels{reconciler.OwnerUIDLabel: string(parent.GetUID())})
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		for _, child := range children {
+			remaining++
+			if !child.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			if err := k8sCli.Delete(ctx, child); client.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, err
+			}
+			log.Info("deleted child", "child", child.Name, "namespace", child.Namespace, "kind", gvk.Kind)
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, child)
+		}
+	}
+	if remaining > 0 {
+		return reconcile.Result{RequeueAfter: DeletionRequeueAfter}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// list returns the metadata of the objects of the kind matching the options, in every namespace.
+func list(ctx context.Context, k8sCli client.Client, gvk schema.GroupVersionKind, opts ...client.ListOption) ([]*metav1.PartialObjectMetadata, error) {
+	objects := &metav1.PartialObjectMetadataList{}
+	objects.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := k8sCli.List(ctx, objects, opts...); err != nil {
+		return nil, err
+	}
+	items := make([]*metav1.PartialObjectMetadata, len(objects.Items))
+	for i := range objects.Items {
+		objects.Items[i].SetGroupVersionKind(gvk)
+		items[i] = &objects.Items[i]
+	}
+	return items, nil
+}
@@ -0,0 +1,35 @@
+package gc
+
+import (
+	"github.com/ethan-gallant/maestro/api"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// ForChildren returns a new instance of Builder deleting the children of the kinds, which are set as the ChildGVKs of
+// the descriptor.
+func ForChildren[Parent client.Object](gvks ...schema.GroupVersionKind) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			Details: api.Descriptor{ChildGVKs: gvks},
+		},
+	}
+}
+
+// WithDetails sets the Details field. The kinds passed to ForChildren are added to its ChildGVKs.
+func (b *Builder[Parent]) WithDetails(details api.Descriptor) *Builder[Parent] {
+	gvks := b.reconciler.Details.ChildGVKs
+	b.reconciler.Details = details
+	b.reconciler.Details.ChildGVKs = append(details.ChildGVKs, gvks...)
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
@@ -0,0 +1,141 @@
+package gc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	configMapGVK = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	secretGVK    = corev1.SchemeGroupVersion.WithKind("Secret")
+)
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+	mapper.Add(secretGVK, meta.RESTScopeNamespace)
+	return fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(objs...).Build()
+}
+
+// shared returns a simple reconciler of a ConfigMap in the shared namespace, which can't have an owner reference.
+func shared() *simple.Reconciler[*corev1.ConfigMap, *corev1.ConfigMap] {
+	return simple.FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: "shared"}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Shared"}).
+		WithOwnership(reconciler.OwnershipAuto).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+}
+
+func TestGCReconciler(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := newClient(t, parent)
+	ctx := context.Background()
+
+	_, err := shared().Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "shared"}, child))
+	assert.Empty(t, child.OwnerReferences)
+	assert.True(t, reconciler.IsOwnedBy(child, parent))
+	assert.Equal(t, "ConfigMap", child.Annotations[reconciler.OwnerKindAnnotation])
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(parent)}}, reconciler.EnqueueOwner(ctx, child))
+
+	// Steady state
+	result, err := shared().Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	r := ForChildren[*corev1.ConfigMap](configMapGVK).WithDetails(api.Descriptor{Name: "GC"}).Build()
+	require.NoError(t, r.Validate())
+
+	// Nothing is collected while the parent lives
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(child), child))
+
+	result, err = r.Finalize(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: DeletionRequeueAfter}, result)
+	assert.True(t, apierrors.IsNotFound(k8sCli.Get(ctx, client.ObjectKeyFromObject(child), child)))
+
+	result, err = r.Finalize(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+
+	require.ErrorIs(t, ForChildren[*corev1.ConfigMap]().Build().Validate(), reconciler.ErrInvalidConfiguration)
+}
+
+func TestCollector(t *testing.T) {
+	alive := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "alive", Namespace: "default", UID: "alive-uid"}}
+	deleted := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: "default", UID: "deleted-uid"}}
+	// Recreated with the same name as the parent of the stale child
+	recreated := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "recreated", Namespace: "default", UID: "new-uid"}}
+	k8sCli := newClient(t, alive, recreated)
+	ctx := context.Background()
+
+	for _, parent := range []*corev1.ConfigMap{alive, deleted, recreated} {
+		_, err := shared().Reconcile(ctx, k8sCli, parent)
+		require.NoError(t, err)
+	}
+	stale := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "recreated", Namespace: "shared"}, stale))
+	stale.Labels[reconciler.OwnerUIDLabel] = "old-uid"
+	require.NoError(t, k8sCli.Update(ctx, stale))
+
+	// Children of another kind of parent are never collected
+	foreign := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: "shared"}}
+	reconciler.SetOwnerMetadata(deleted, foreign)
+	require.NoError(t, k8sCli.Create(ctx, foreign))
+
+	collector := &Collector{Client: k8sCli, ParentGVK: configMapGVK, ChildGVKs: []schema.GroupVersionKind{configMapGVK, secretGVK}}
+	_, err := collector.Collect(ctx)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+
+	collector.APIReader = k8sCli
+	count, err := collector.Collect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	list := &corev1.ConfigMapList{}
+	require.NoError(t, k8sCli.List(ctx, list, client.InNamespace("shared")))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "alive", list.Items[0].Name)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(foreign), foreign))
+}
+
+func TestCollectorUncachedParent(t *testing.T) {
+	fresh := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "fresh", Namespace: "default", UID: "fresh-uid"}}
+	// The parent was just created: the API server has it, the cache doesn't yet
+	cached := newClient(t)
+	uncached := newClient(t, fresh)
+	ctx := context.Background()
+
+	_, err := shared().Reconcile(ctx, cached, fresh)
+	require.NoError(t, err)
+
+	collector := &Collector{Client: cached, APIReader: uncached, ParentGVK: configMapGVK, ChildGVKs: []schema.GroupVersionKind{configMapGVK}}
+	count, err := collector.Collect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	require.NoError(t, cached.Get(ctx, client.ObjectKey{Name: "fresh", Namespace: "shared"}, &corev1.ConfigMap{}))
+}
@@ -0,0 +1,25 @@
+package reconciler
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GeneratedNamePrefix is the prefix of the parent annotations holding the names generated by the API server for
+// children created with a generateName, followed by the name of the reconciler.
+const GeneratedNamePrefix = "generated-name." + AnnotationPrefix
+
+// GeneratedName returns the name generated for the child of the named reconciler, as recorded on the parent.
+func GeneratedName(parent client.Object, name string) (string, bool) {
+	generated, ok := parent.GetAnnotations()[GeneratedNamePrefix+name]
+	return generated, ok && generated != ""
+}
+
+// SetGeneratedName records on the parent the name generated for the child of the named reconciler.
+func SetGeneratedName(parent client.Object, name, generated string) {
+	annotations := parent.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[GeneratedNamePrefix+name] = generated
+	parent.SetAnnotations(annotations)
+}
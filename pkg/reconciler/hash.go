@@ -0,0 +1,71 @@
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationPrefix is the prefix of annotations and labels managed by Maestro.
+const AnnotationPrefix = "maestro.io/"
+
+// DesiredHashAnnotation is the annotation holding the hash of the desired state of a child, with ChangeDetectionHash.
+const DesiredHashAnnotation = AnnotationPrefix + "desired-hash"
+
+// ParentGenerationAnnotation holds the generation of the parent a child was last applied for, to skip unchanged
+// parents.
+const ParentGenerationAnnotation = AnnotationPrefix + "parent-generation"
+
+// InputsHashAnnotation holds the hash of the inputs a child was last applied for, to skip unchanged parents.
+const InputsHashAnnotation = AnnotationPrefix + "inputs-hash"
+
+// HashData returns a stable sha256 hash of the key/value pairs, independent of map ordering.
+func HashData(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write([]byte(data[key]))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// HashObject returns a stable sha256 hash of the desired state of the object. The fields set by the API server
+// (resourceVersion, uid, creationTimestamp, generation, managedFields), the status, the DesiredHashAnnotation and the
+// LastUpdatedAnnotation are left out.
+func HashObject(obj client.Object) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert object for hashing: %w", err)
+	}
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields"} {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	unstructured.RemoveNestedField(content, "metadata", "annotations", DesiredHashAnnotation)
+	unstructured.RemoveNestedField(content, "metadata", "annotations", LastUpdatedAnnotation)
+	if annotations, found, _ := unstructured.NestedMap(content, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(content, "metadata", "annotations")
+	}
+	unstructured.RemoveNestedField(content, "status")
+
+	// Maps are marshalled with sorted keys, making the output stable.
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal object for hashing: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
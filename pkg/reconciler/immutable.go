@@ -0,0 +1,36 @@
+package reconciler
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// IsImmutableFieldError reports whether the error is an Invalid API error caused by a change to immutable fields,
+// such as the template of a Job, the storage class of a PVC or the clusterIP of a Service. Such updates can only be
+// applied by recreating the object.
+// Only field causes of type Forbidden, or Invalid ones mentioning immutability, are considered; other validation
+// errors would fail the recreation as well.
+func IsImmutableFieldError(err error) bool {
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		switch cause.Type {
+		case metav1.CauseType(field.ErrorTypeForbidden):
+			return true
+		case metav1.CauseTypeFieldValueInvalid:
+			if strings.Contains(cause.Message, "immutable") {
+				return true
+			}
+		}
+	}
+	return false
+}
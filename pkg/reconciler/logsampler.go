@@ -0,0 +1,78 @@
+package reconciler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultLogSamplePeriod is the period in which a sampled message is logged at most once per object.
+const DefaultLogSamplePeriod = 10 * time.Minute
+
+// LogSampler suppresses the repeated log lines of steady objects, such as the "no changes" message logged on every
+// reconcile of an up to date child: a message is logged at most once per Period for an object, and again as soon as the
+// message changes or the object was Reset, e.g. after an update. It keeps the last message per object in memory, so a
+// single sampler should be shared across reconciles. The zero value is ready to use.
+type LogSampler struct {
+	// Period is the period in which a message is logged at most once per object. Defaults to DefaultLogSamplePeriod.
+	// A negative Period only logs the transitions.
+	Period time.Duration
+
+	mu   sync.Mutex
+	last map[string]logSample
+}
+
+// logSample is the last message logged for an object.
+type logSample struct {
+	msg string
+	at  time.Time
+}
+
+// Info logs the message with the logger, unless the same message was logged for the object within the Period.
+func (s *LogSampler) Info(log logr.Logger, obj client.Object, msg string, keysAndValues ...any) {
+	if log.Enabled() && s.Allow(obj, msg, time.Now()) {
+		log.Info(msg, keysAndValues...)
+	}
+}
+
+// Allow returns whether the message should be logged for the object at now, and records it if so.
+func (s *LogSampler) Allow(obj client.Object, msg string, now time.Time) bool {
+	key := sampleKey(obj)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.last[key]; ok && last.msg == msg && (s.period() < 0 || now.Sub(last.at) < s.period()) {
+		return false
+	}
+	if s.last == nil {
+		s.last = map[string]logSample{}
+	}
+	s.last[key] = logSample{msg: msg, at: now}
+	return true
+}
+
+// Reset forgets the last message of the object, so the next one is logged as a transition. It should be called when
+// the object changed, e.g. after it was created, updated or deleted.
+func (s *LogSampler) Reset(obj client.Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.last, sampleKey(obj))
+}
+
+func (s *LogSampler) period() time.Duration {
+	if s.Period == 0 {
+		return DefaultLogSamplePeriod
+	}
+	return s.Period
+}
+
+// sampleKey identifies the object. Typed objects usually have no kind set, so the Go type identifies the kind.
+func sampleKey(obj client.Object) string {
+	if _, ok := obj.(runtime.Unstructured); ok {
+		return fmt.Sprintf("%s/%s", obj.GetObjectKind().GroupVersionKind().GroupKind(), client.ObjectKeyFromObject(obj))
+	}
+	return fmt.Sprintf("%T/%s", obj, client.ObjectKeyFromObject(obj))
+}
@@ -0,0 +1,53 @@
+package reconciler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DecodeManifests decodes a multi-document YAML (or JSON) manifest into unstructured objects, in order. Empty
+// documents are skipped and the items of List kinds (e.g. v1/List) are expanded. It returns ErrMissingGVK if a document
+// has no apiVersion or kind.
+func DecodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objects []*unstructured.Unstructured
+	for i := 0; ; i++ {
+		content := map[string]interface{}{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("decoding document %d: %w", i, err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("decoding document %d: %w", i, err)
+			}
+			for j := range list.Items {
+				objects = append(objects, &list.Items[j])
+			}
+			continue
+		}
+		if gvk := obj.GroupVersionKind(); gvk.Version == "" || gvk.Kind == "" {
+			return nil, fmt.Errorf("decoding document %d: %w: %s", i, ErrMissingGVK, manifestName(obj))
+		}
+		objects = append(objects, obj)
+	}
+}
+
+// manifestName returns a readable name of the object for errors.
+func manifestName(obj *unstructured.Unstructured) string {
+	return strings.TrimPrefix(obj.GetNamespace()+"/"+obj.GetName(), "/")
+}
@@ -0,0 +1,150 @@
+package multi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler (MultiReconciler) reconciles a dynamic number of children of the same type for a parent object,
+// and prunes the children that are no longer desired.
+type Reconciler[Parent client.Object, Child client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	Details api.Descriptor // required
+	// ReconcileFn is the function that reconciles the Child objects.
+	// The ReconcileFn accepts a Parent object, and returns the desired state of every Child object, or an error.
+	ReconcileFn func(ctx context.Context, parent Parent) ([]Child, error) // required
+	// PredicateFn is a function that returns true if the ReconcileFn should be called.
+	// If nil, the ReconcileFn will always be called.
+	PredicateFn func(parent Parent) bool // optional
+	// NoReference optionally disables setting the owner reference on the child objects.
+	NoReference bool // optional
+	// DryRunType configures the dry-run behavior of the reconciler.
+	DryRunType reconciler.DryRunType // optional
+	// CompareOpts are the options to use when comparing the child objects to their desired state.
+	CompareOpts []cmp.Option // optional
+	// PreUpdateFn is a function that is called before each existing child object is applied.
+	PreUpdateFn func(ctx context.Context, parent Parent, previous, child Child) error // optional
+	// LabelsFn returns the labels added to every child, used to find the children to prune.
+	// They must uniquely identify the children of this reconciler for the parent.
+	// Defaults to the labels of reconciler.NewApplySet for the descriptor name.
+	LabelsFn func(parent Parent) map[string]string // optional
+	// NoPrune disables deleting the children that are no longer desired.
+	NoPrune bool // optional
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object, client.Object]{}
+	_ api.ChildDescriber            = &Reconciler[client.Object, client.Object]{}
+)
+
+// Reconcile creates or updates every desired child, then prunes the children that are no longer desired.
+func (r *Reconciler[Parent, Child]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent, Child]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// Children returns the type of the child, unless NoReference is set as the child is then not owned by the parent.
+func (r *Reconciler[Parent, Child]) Children() []client.Object {
+	if r.NoReference {
+		return nil
+	}
+	return []client.Object{reconciler.NewObject[Child]()}
+}
+
+// ChildTypes returns the type of the child, owned or not, unless it is an interface.
+func (r *Reconciler[Parent, Child]) ChildTypes() []client.Object {
+	if reflect.TypeFor[Child]().Kind() == reflect.Interface {
+		return nil
+	}
+	return []client.Object{reconciler.NewObject[Child]()}
+}
+
+func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	desired, err := r.ReconcileFn(ctx, parent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	set := r.applySet(parent)
+	applier := &simple.Reconciler[Parent, Child]{
+		Details:     r.Details,
+		NoReference: r.NoReference,
+		DryRunType:  r.DryRunType,
+		CompareOpts: r.CompareOpts,
+		PreUpdateFn: r.PreUpdateFn,
+	}
+
+	var result reconcile.Result
+	var errs []error
+	for _, child := range desired {
+		set.Add(child)
+
+		childResult, err := applier.Apply(ctx, k8sCli, parent, child)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = reconciler.MergeResults(result, childResult)
+	}
+	if len(errs) > 0 {
+		// Never prune while some children could not be applied.
+		return reconcile.Result{}, errors.Join(errs...)
+	}
+
+	if r.NoPrune {
+		return result, nil
+	}
+
+	// The child type is pruned even when no child is desired anymore.
+	gvk, err := apiutil.GVKForObject(reconciler.NewObject[Child](), k8sCli.Scheme())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	set.GVKs = append(set.GVKs, gvk)
+
+	pruned, err := set.Prune(ctx, k8sCli)
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent))
+	for _, child := range pruned {
+		log.Info("pruned child", "child", child.GetName(), "namespace", child.GetNamespace(), "kind", gvk.Kind)
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, child)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(pruned) > 0 {
+		result.Requeue = true
+	}
+	return result, nil
+}
+
+// applySet returns the set of children of the parent, selected by the labels of the LabelsFn if any.
+func (r *Reconciler[Parent, Child]) applySet(parent Parent) *reconciler.ApplySet {
+	set := reconciler.NewApplySet(parent, r.Details.Name)
+	if r.LabelsFn != nil {
+		set.Labels = r.LabelsFn(parent)
+	}
+	return set
+}
@@ -0,0 +1,79 @@
+package multi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMultiReconcileAndPrune(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenants", Namespace: "default", UID: "parent-uid"},
+		Data:       map[string]string{"tenants": "a,b"},
+	}
+	// An unrelated ConfigMap, in the apply set of another reconciler of the parent, which must never be pruned
+	unrelated := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"},
+	}
+	reconciler.NewApplySet(parent, "Other").Add(unrelated)
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, unrelated).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) ([]*corev1.ConfigMap, error) {
+		var children []*corev1.ConfigMap
+		for _, tenant := range strings.Split(parent.Data["tenants"], ",") {
+			children = append(children, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-" + tenant, Namespace: parent.Namespace},
+				Data:       map[string]string{"tenant": tenant},
+			})
+		}
+		return children, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Tenants"}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+
+	ctx := context.Background()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		child := &corev1.ConfigMap{}
+		require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, child))
+		assert.Equal(t, "Tenants", child.Labels[reconciler.ReconcilerLabel])
+		assert.Equal(t, reconciler.ApplySetID(parent, "Tenants"), child.Labels[reconciler.ApplySetLabel])
+		assert.True(t, metav1.IsControlledBy(child, parent))
+	}
+
+	// Steady state
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// Removing a tenant prunes its child
+	parent.Data["tenants"] = "a"
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	list := &corev1.ConfigMapList{}
+	require.NoError(t, k8sCli.List(ctx, list))
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+	assert.ElementsMatch(t, []string{"tenants", "unrelated", "tenant-a"}, names)
+}
@@ -0,0 +1,43 @@
+package reconciler
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MutateParent applies mutateFn to a freshly read copy of the parent, e.g. to add annotations or labels or to default
+// spec fields, and patches the parent with the changes. The patch is guarded by the resource version of the parent
+// read: on conflicts, the parent is read and mutated again. Parents with a status subresource don't get their status
+// changed, which is left to the status reconcilers and the status condition handler of the conductor.
+// It returns whether the parent changed, in which case the parent is updated with the patched object.
+func MutateParent[Parent client.Object](ctx context.Context, k8sCli client.Client, parent Parent, mutateFn func(parent Parent) error) (bool, error) {
+	changed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := parent.DeepCopyObject().(Parent)
+		if err := k8sCli.Get(ctx, client.ObjectKeyFromObject(parent), current); err != nil {
+			return err
+		}
+
+		desired := current.DeepCopyObject().(Parent)
+		if err := mutateFn(desired); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(current, desired) {
+			changed = false
+			return nil
+		}
+
+		patch := client.MergeFromWithOptions(current, client.MergeFromWithOptimisticLock{})
+		if err := k8sCli.Patch(ctx, desired, patch); err != nil {
+			return err
+		}
+		changed = true
+		reflect.ValueOf(parent).Elem().Set(reflect.ValueOf(desired).Elem())
+		return nil
+	})
+	return changed, err
+}
@@ -0,0 +1,189 @@
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TerminatingRequeueAfter is the delay used to requeue the parent while a namespace is terminating.
+const TerminatingRequeueAfter = 5 * time.Second
+
+// Reconciler (NamespaceReconciler) reconciles a namespace for a parent object, e.g. one namespace per tenant.
+//
+// As a namespaced parent can't own a namespace through an owner reference, ownership is tracked with the labels and
+// annotations of reconciler.SetOwnerMetadata. Pre-existing namespaces can be adopted; their labels and annotations
+// are never removed, only the ones returned by the NamespaceFn are set (merge-only policy).
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	Details api.Descriptor // required
+	// NameFn returns the name of the namespace for the parent.
+	NameFn func(Parent) string // required
+	// NamespaceFn returns the desired labels and annotations of the namespace. The name is always taken from NameFn.
+	// If nil, the namespace is only created and marked as owned.
+	NamespaceFn func(ctx context.Context, parent Parent) (*corev1.Namespace, error) // optional
+	// PredicateFn is a function that returns true if the namespace should be reconciled.
+	// If nil, the namespace will always be reconciled.
+	PredicateFn func(parent Parent) bool // optional
+	// ShouldDeleteFn is a function that if returns true, the namespace will be deleted if it's owned by the parent.
+	// If no function is provided, the namespace will never be deleted.
+	ShouldDeleteFn func(Parent) bool // optional
+	// Adopt allows taking over namespaces that exist but are not owned by any parent.
+	// Otherwise, reconciling fails with ErrNotOwned, as it always does for namespaces owned by another parent.
+	Adopt bool // optional
+	// DeleteAdopted allows deleting namespaces that were adopted rather than created by the reconciler.
+	DeleteAdopted bool // optional
+	// TeardownAfter lists the kinds of objects inside the namespace that must be gone before it is deleted.
+	// This lets the reconcilers of the children perform their own cleanup before the namespace deletion removes them.
+	TeardownAfter []client.ObjectList // optional
+}
+
+var _ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+
+// Reconcile creates, adopts, updates or deletes the namespace of the parent.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+func (r *Reconciler[Parent]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	name := r.NameFn(parent)
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent), "namespace", name)
+
+	current := &corev1.Namespace{}
+	exists := true
+	if err := k8sCli.Get(ctx, client.ObjectKey{Name: name}, current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		exists = false
+	}
+
+	if r.ShouldDeleteFn != nil && r.ShouldDeleteFn(parent) {
+		if !exists {
+			return reconcile.Result{}, nil
+		}
+		return r.teardown(ctx, k8sCli, parent, current)
+	}
+
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	desired := &corev1.Namespace{}
+	if r.NamespaceFn != nil {
+		fromFn, err := r.NamespaceFn(ctx, parent)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		desired = fromFn
+	}
+	desired.SetName(name)
+	reconciler.SetOwnerMetadata(parent, desired)
+
+	if !exists {
+		if err := k8sCli.Create(ctx, desired); err != nil {
+			return reconcile.Result{}, err
+		}
+		log.Info("created namespace")
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	if current.Status.Phase == corev1.NamespaceTerminating {
+		log.Info("namespace is terminating")
+		return reconcile.Result{RequeueAfter: TerminatingRequeueAfter}, nil
+	}
+
+	if !reconciler.IsOwnedBy(current, parent) {
+		// Namespaces owned by another parent are never taken over, so two parents mapped to the same name don't
+		// flap over it.
+		if owner := current.GetLabels()[reconciler.OwnerUIDLabel]; owner != "" {
+			return reconcile.Result{}, fmt.Errorf("%w: namespace %s is owned by %s", reconciler.ErrNotOwned, name,
+				current.GetAnnotations()[reconciler.OwnerAnnotation])
+		}
+		if !r.Adopt {
+			return reconcile.Result{}, fmt.Errorf("%w: namespace %s", reconciler.ErrNotOwned, name)
+		}
+		desired.Annotations[reconciler.AdoptedAnnotation] = "true"
+		log.Info("adopting namespace")
+	}
+
+	// Only ever add or change labels and annotations, never remove the ones written by others.
+	reconciler.MergeMetadata(current, desired)
+	if cmp.Equal(current.GetLabels(), desired.GetLabels()) && cmp.Equal(current.GetAnnotations(), desired.GetAnnotations()) {
+		log.Info("no changes")
+		return reconcile.Result{}, nil
+	}
+
+	updated := current.DeepCopy()
+	updated.SetLabels(desired.GetLabels())
+	updated.SetAnnotations(desired.GetAnnotations())
+	if err := k8sCli.Update(ctx, updated); err != nil {
+		return reconcile.Result{}, err
+	}
+	log.Info("updated namespace")
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// teardown deletes the namespace once the objects listed in TeardownAfter are gone from it.
+// While waiting, the reconciler doesn't requeue so that the reconcilers of the children can run their own deletion.
+func (r *Reconciler[Parent]) teardown(ctx context.Context, k8sCli client.Client, parent Parent, current *corev1.Namespace) (reconcile.Result, error) {
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent), "namespace", current.Name)
+
+	if !reconciler.IsOwnedBy(current, parent) || (reconciler.IsAdopted(current) && !r.DeleteAdopted) {
+		log.Info("namespace is not owned, skipping deletion")
+		return reconcile.Result{}, nil
+	}
+
+	if current.Status.Phase == corev1.NamespaceTerminating {
+		return reconcile.Result{RequeueAfter: TerminatingRequeueAfter}, nil
+	}
+
+	for _, list := range r.TeardownAfter {
+		if err := k8sCli.List(ctx, list, client.InNamespace(current.Name), client.Limit(1)); err != nil {
+			return reconcile.Result{}, err
+		}
+		if meta.LenList(list) > 0 {
+			log.Info("waiting for children to be removed before deleting namespace")
+			if state, err := conductor.FetchState(ctx); err == nil {
+				state.AddCondition(metav1.Condition{
+					Type:    fmt.Sprintf("%sWaiting", r.Details.Name),
+					Status:  metav1.ConditionTrue,
+					Reason:  "ChildrenRemaining",
+					Message: fmt.Sprintf("Waiting for children of namespace %s to be removed", current.Name),
+					LastTransitionTime: metav1.Time{
+						Time: time.Now(),
+					},
+				})
+			}
+			return reconcile.Result{}, nil
+		}
+	}
+
+	if err := k8sCli.Delete(ctx, current); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	log.Info("deleted namespace")
+	return reconcile.Result{Requeue: true}, nil
+}
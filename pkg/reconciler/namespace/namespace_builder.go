@@ -0,0 +1,72 @@
+package namespace
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// ForName returns a new instance of Builder for the namespace named by nameFn.
+func ForName[Parent client.Object](nameFn func(Parent) string) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			NameFn:      nameFn,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+		},
+	}
+}
+
+// WithNamespaceFn sets the NamespaceFn field.
+func (b *Builder[Parent]) WithNamespaceFn(fn func(ctx context.Context, parent Parent) (*corev1.Namespace, error)) *Builder[Parent] {
+	b.reconciler.NamespaceFn = fn
+	return b
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// WithShouldDeleteFn sets the ShouldDeleteFn field.
+func (b *Builder[Parent]) WithShouldDeleteFn(shouldDeleteFn func(Parent) bool) *Builder[Parent] {
+	b.reconciler.ShouldDeleteFn = shouldDeleteFn
+	return b
+}
+
+// WithAdopt sets the Adopt field.
+func (b *Builder[Parent]) WithAdopt(adopt bool) *Builder[Parent] {
+	b.reconciler.Adopt = adopt
+	return b
+}
+
+// WithDeleteAdopted sets the DeleteAdopted field.
+func (b *Builder[Parent]) WithDeleteAdopted(deleteAdopted bool) *Builder[Parent] {
+	b.reconciler.DeleteAdopted = deleteAdopted
+	return b
+}
+
+// AddTeardownAfter adds kinds of objects that must be removed from the namespace before it is deleted.
+func (b *Builder[Parent]) AddTeardownAfter(lists ...client.ObjectList) *Builder[Parent] {
+	b.reconciler.TeardownAfter = append(b.reconciler.TeardownAfter, lists...)
+	return b
+}
+
+// WithDetails sets the Details field.
+func (b *Builder[Parent]) WithDetails(details api.Descriptor) *Builder[Parent] {
+	b.reconciler.Details = details
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
@@ -0,0 +1,151 @@
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTenant() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme", Namespace: "tenants", UID: "tenant-uid"},
+	}
+}
+
+func newReconciler(deleting *bool) *Builder[*corev1.ConfigMap] {
+	return ForName(func(tenant *corev1.ConfigMap) string {
+		return "tenant-" + tenant.Name
+	}).
+		WithNamespaceFn(func(_ context.Context, _ *corev1.ConfigMap) (*corev1.Namespace, error) {
+			return &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"tier": "tenant"}},
+			}, nil
+		}).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return *deleting })
+}
+
+func TestNamespaceCreateAndDelete(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+	ctx := context.Background()
+	tenant := newTenant()
+
+	deleting := false
+	r := newReconciler(&deleting).Build()
+
+	result, err := r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, ns))
+	assert.Equal(t, "tenant", ns.Labels["tier"])
+	assert.True(t, reconciler.IsOwnedBy(ns, tenant))
+
+	deleting = true
+	_, err = r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, ns)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestNamespaceAdoption(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-acme",
+			Labels:      map[string]string{"team": "platform"},
+			Annotations: map[string]string{"note": "pre-existing"},
+		},
+	}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+	ctx := context.Background()
+	tenant := newTenant()
+
+	deleting := false
+	_, err := newReconciler(&deleting).Build().Reconcile(ctx, k8sCli, tenant)
+	assert.ErrorIs(t, err, reconciler.ErrNotOwned)
+
+	r := newReconciler(&deleting).WithAdopt(true).Build()
+	_, err = r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, ns))
+	assert.Equal(t, "platform", ns.Labels["team"])
+	assert.Equal(t, "tenant", ns.Labels["tier"])
+	assert.Equal(t, "pre-existing", ns.Annotations["note"])
+	assert.True(t, reconciler.IsAdopted(ns))
+
+	// Adopted namespaces are not deleted by default
+	deleting = true
+	_, err = r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, ns))
+}
+
+func TestNamespaceOwnedByAnotherParent(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "acme", Namespace: "other-tenants", UID: "other-uid"}}
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme"}}
+	reconciler.SetOwnerMetadata(other, existing)
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+	ctx := context.Background()
+	tenant := newTenant()
+
+	deleting := false
+	r := newReconciler(&deleting).WithAdopt(true).WithDeleteAdopted(true).Build()
+	_, err := r.Reconcile(ctx, k8sCli, tenant)
+	assert.ErrorIs(t, err, reconciler.ErrNotOwned)
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, ns))
+	assert.True(t, reconciler.IsOwnedBy(ns, other))
+	assert.Equal(t, "other-tenants/acme", ns.Annotations[reconciler.OwnerAnnotation])
+	assert.False(t, reconciler.IsAdopted(ns))
+
+	// The namespace of the other parent is not deleted
+	deleting = true
+	_, err = r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, ns))
+}
+
+func TestNamespaceTeardownAfter(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+	ctx := context.Background()
+	tenant := newTenant()
+
+	deleting := false
+	r := newReconciler(&deleting).AddTeardownAfter(&corev1.ConfigMapList{}).Build()
+	_, err := r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "tenant-acme"}}
+	require.NoError(t, k8sCli.Create(ctx, child))
+
+	deleting = true
+	_, err = r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, &corev1.Namespace{}))
+
+	require.NoError(t, k8sCli.Delete(ctx, child))
+	_, err = r.Reconcile(ctx, k8sCli, tenant)
+	require.NoError(t, err)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-acme"}, &corev1.Namespace{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
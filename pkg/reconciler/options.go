@@ -10,3 +10,67 @@ const (
 	// DryRunNone will not perform a dry-run, and will always update the object if it is different
 	DryRunNone DryRunType = "none"
 )
+
+// UpdateStrategy configures how changes are sent to the API server when a child object is out of date.
+type UpdateStrategy string
+
+const (
+	// UpdateStrategyUpdate replaces the whole object with an Update (default)
+	UpdateStrategyUpdate UpdateStrategy = "update"
+	// UpdateStrategyMergePatch sends a JSON merge patch of the fields set on the desired object. Lists are replaced.
+	UpdateStrategyMergePatch UpdateStrategy = "merge-patch"
+	// UpdateStrategyStrategicMergePatch sends a strategic merge patch of the fields set on the desired object, merging
+	// lists by their patch merge key (e.g. containers by name). Only supported for built-in types.
+	UpdateStrategyStrategicMergePatch UpdateStrategy = "strategic-merge-patch"
+)
+
+// ForeignOwnerPolicy configures what happens when an existing child is controlled by another owner.
+type ForeignOwnerPolicy string
+
+const (
+	// ForeignOwnerError fails the reconcile with ErrForeignOwner (default)
+	ForeignOwnerError ForeignOwnerPolicy = "error"
+	// ForeignOwnerSkip leaves the child untouched and carries on
+	ForeignOwnerSkip ForeignOwnerPolicy = "skip"
+	// ForeignOwnerAdopt takes over the child, demoting the other owner to a regular (non-controller) owner
+	ForeignOwnerAdopt ForeignOwnerPolicy = "adopt"
+)
+
+// ChangeDetection configures how a reconciler detects that a child object is out of date.
+type ChangeDetection string
+
+const (
+	// ChangeDetectionCompare compares the current object to the desired one, with a dry-run on mismatch (default)
+	ChangeDetectionCompare ChangeDetection = "compare"
+	// ChangeDetectionHash stores a hash of the desired object in the DesiredHashAnnotation of the child, and only
+	// updates it when the hash changes. It avoids the dry-runs and the false diffs from API defaulting, but changes
+	// made to the child by others are not reverted until the desired object changes.
+	ChangeDetectionHash ChangeDetection = "hash"
+)
+
+// DeleteMode configures how children are deleted when the ShouldDeleteFn of a reconciler returns true.
+type DeleteMode string
+
+const (
+	// DeleteSingle gets and deletes the child identified by the ChildKeyFn (default)
+	DeleteSingle DeleteMode = "single"
+	// DeleteCollection deletes every child matching a label selector derived from the parent with a single DeleteAllOf
+	// call, without enumerating their keys. It suits reconcilers managing a dynamic number of children.
+	DeleteCollection DeleteMode = "collection"
+)
+
+// OwnershipMode configures how a child is marked as owned by its parent.
+type OwnershipMode string
+
+const (
+	// OwnershipReference sets a controller reference on the child (default). Cluster-scoped children of a namespaced
+	// parent (e.g. ClusterRoles) are marked as with OwnershipLabels instead, while children in another namespace than
+	// the parent fail.
+	OwnershipReference OwnershipMode = "reference"
+	// OwnershipLabels marks the child with the owner labels and annotations of MarkOwned instead. The child is not
+	// garbage collected with the parent, see the gc package.
+	OwnershipLabels OwnershipMode = "labels"
+	// OwnershipAuto sets a controller reference when possible (see CanReference), and falls back to OwnershipLabels
+	// otherwise.
+	OwnershipAuto OwnershipMode = "auto"
+)
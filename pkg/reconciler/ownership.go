@@ -0,0 +1,126 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// OwnerUIDLabel holds the UID of the parent owning an object that can't use an owner reference
+	// (e.g. cluster-scoped children of a namespaced parent). As a label, owned objects can be selected for cleanup.
+	OwnerUIDLabel = AnnotationPrefix + "owner-uid"
+	// OwnerAnnotation holds the namespace/name of the parent owning the object, for humans.
+	OwnerAnnotation = AnnotationPrefix + "owner"
+	// OwnerKindAnnotation holds the group and kind of the parent owning the object (e.g. "Deployment.apps"), set by
+	// MarkOwned, so owned objects can be mapped back to their parent.
+	OwnerKindAnnotation = AnnotationPrefix + "owner-kind"
+	// ReconcilerLabel holds the name of the reconciler managing the object, to select the objects of one reconciler.
+	ReconcilerLabel = AnnotationPrefix + "reconciler"
+	// AdoptedAnnotation marks objects that existed before being taken over by a parent.
+	AdoptedAnnotation = AnnotationPrefix + "adopted"
+)
+
+// SetOwnerMetadata marks the object as owned by the owner using labels and annotations instead of an owner reference.
+func SetOwnerMetadata(owner, obj client.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[OwnerUIDLabel] = string(owner.GetUID())
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[OwnerAnnotation] = client.ObjectKeyFromObject(owner).String()
+	obj.SetAnnotations(annotations)
+}
+
+// MarkOwned marks the object as owned by the owner like SetOwnerMetadata, and records the kind of the owner, resolved
+// with the scheme, in the OwnerKindAnnotation.
+func MarkOwned(owner, obj client.Object, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(owner, scheme)
+	if err != nil {
+		return err
+	}
+	SetOwnerMetadata(owner, obj)
+	annotations := obj.GetAnnotations()
+	annotations[OwnerKindAnnotation] = gvk.GroupKind().String()
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// OwnerKey returns the key of the owner of an object marked with SetOwnerMetadata, and false if it isn't marked.
+func OwnerKey(obj client.Object) (client.ObjectKey, bool) {
+	owner, ok := obj.GetAnnotations()[OwnerAnnotation]
+	if !ok || obj.GetLabels()[OwnerUIDLabel] == "" {
+		return client.ObjectKey{}, false
+	}
+	namespace, name, found := strings.Cut(owner, string(types.Separator))
+	if !found {
+		return client.ObjectKey{Name: owner}, true
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name}, true
+}
+
+// EnqueueOwner maps an object marked with SetOwnerMetadata to a request for its owner, for use with
+// handler.EnqueueRequestsFromMapFunc to watch children that can't have an owner reference, e.g. in other namespaces.
+func EnqueueOwner(_ context.Context, obj client.Object) []reconcile.Request {
+	key, ok := OwnerKey(obj)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
+
+// CanReference returns true if the parent can own the child through an owner reference: owner references can't cross
+// namespaces, and a cluster-scoped child can only be owned by a cluster-scoped parent.
+func CanReference(k8sCli client.Client, parent, child client.Object) (bool, error) {
+	if parent.GetNamespace() == "" {
+		return true, nil
+	}
+	namespaced, err := k8sCli.IsObjectNamespaced(child)
+	if err != nil {
+		return false, err
+	}
+	return namespaced && (child.GetNamespace() == "" || child.GetNamespace() == parent.GetNamespace()), nil
+}
+
+// IsOwnedBy returns true if the object was marked as owned by the owner with SetOwnerMetadata.
+func IsOwnedBy(obj, owner client.Object) bool {
+	uid := obj.GetLabels()[OwnerUIDLabel]
+	return uid != "" && uid == string(owner.GetUID())
+}
+
+// IsAdopted returns true if the object existed before being taken over by its owner.
+func IsAdopted(obj client.Object) bool {
+	return obj.GetAnnotations()[AdoptedAnnotation] == "true"
+}
+
+// MergeMetadata copies the labels and annotations of current that are not set on desired, so that
+// metadata written by other actors is kept (merge-only policy).
+func MergeMetadata(current, desired client.Object) {
+	desired.SetLabels(mergeMap(current.GetLabels(), desired.GetLabels()))
+	desired.SetAnnotations(mergeMap(current.GetAnnotations(), desired.GetAnnotations()))
+}
+
+func mergeMap(current, desired map[string]string) map[string]string {
+	if len(current) == 0 {
+		return desired
+	}
+	merged := make(map[string]string, len(current)+len(desired))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
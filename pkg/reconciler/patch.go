@@ -0,0 +1,137 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrStrategicMergeUnsupported is returned when a strategic merge patch is requested for an unstructured object,
+// which lacks the patch metadata of the Go types.
+var ErrStrategicMergeUnsupported = errors.New("strategic merge patch is not supported for unstructured objects")
+
+// PatchFor returns the patch setting the fields of desired on current, according to the strategy.
+// Fields absent from desired are left untouched, so values written by mutating webhooks or other controllers (e.g. the
+// replicas set by an HPA) are preserved. The type metadata and status are never patched. It returns nil when current
+// already holds every field of desired.
+func PatchFor(strategy UpdateStrategy, current, desired client.Object) (client.Patch, error) {
+	currentJSON, err := patchableJSON(current, false)
+	if err != nil {
+		return nil, err
+	}
+	desiredJSON, err := patchableJSON(desired, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply desired onto current, then diff the result against current. This yields the minimal patch, without the
+	// deletions a direct diff of current and desired would contain.
+	var patchType types.PatchType
+	var data []byte
+	switch strategy {
+	case UpdateStrategyMergePatch:
+		merged, err := jsonpatch.MergePatch(currentJSON, desiredJSON)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = jsonpatch.CreateMergePatch(currentJSON, merged); err != nil {
+			return nil, err
+		}
+		patchType = types.MergePatchType
+	case UpdateStrategyStrategicMergePatch:
+		if _, ok := current.(runtime.Unstructured); ok {
+			return nil, ErrStrategicMergeUnsupported
+		}
+		schema, err := strategicpatch.NewPatchMetaFromStruct(current)
+		if err != nil {
+			return nil, err
+		}
+		merged, err := strategicpatch.StrategicMergePatchUsingLookupPatchMeta(currentJSON, desiredJSON, schema)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = strategicpatch.CreateTwoWayMergePatchUsingLookupPatchMeta(currentJSON, merged, schema); err != nil {
+			return nil, err
+		}
+		patchType = types.StrategicMergePatchType
+	default:
+		return nil, fmt.Errorf("unsupported update strategy %q", strategy)
+	}
+
+	if string(data) == "{}" {
+		return nil, nil
+	}
+	return client.RawPatch(patchType, data), nil
+}
+
+// ApplyPatch applies a merge or strategic merge patch to the object in-memory, without the defaulting and validation
+// of the API server.
+func ApplyPatch(obj client.Object, patch client.Patch) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch patch.Type() {
+	case types.MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, data)
+	case types.StrategicMergePatchType:
+		patched, err = strategicpatch.StrategicMergePatch(original, data, obj)
+	default:
+		return fmt.Errorf("unsupported patch type %q", patch.Type())
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reset the object, so fields removed by the patch don't linger.
+	value := reflect.ValueOf(obj).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(patched, obj)
+}
+
+// patchableJSON returns the JSON of the object without its type metadata and status. With pruneNulls, null values
+// (e.g. the zero creationTimestamp of typed objects) are dropped, as they would otherwise delete the field.
+func patchableJSON(obj client.Object, pruneNulls bool) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "apiVersion")
+	delete(content, "kind")
+	delete(content, "status")
+	if pruneNulls {
+		pruneNullValues(content)
+	}
+	return json.Marshal(content)
+}
+
+// pruneNullValues recursively removes the null values of the map.
+func pruneNullValues(content map[string]interface{}) {
+	for key, value := range content {
+		switch value := value.(type) {
+		case nil:
+			delete(content, key)
+		case map[string]interface{}:
+			pruneNullValues(value)
+		case []interface{}:
+			for _, item := range value {
+				if item, ok := item.(map[string]interface{}); ok {
+					pruneNullValues(item)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package reconciler
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The following functions are presets for the PreserveFields of the reconcilers. They copy fields populated by the
+// API server or other controllers from the current object onto the desired one, unless the desired object sets them,
+// so updates don't try to wipe them.
+
+// PreserveServiceClusterIP preserves the clusterIP and clusterIPs allocated to a Service.
+func PreserveServiceClusterIP(current, desired *corev1.Service) {
+	if desired.Spec.ClusterIP == "" {
+		desired.Spec.ClusterIP = current.Spec.ClusterIP
+	}
+	if len(desired.Spec.ClusterIPs) == 0 {
+		desired.Spec.ClusterIPs = current.Spec.ClusterIPs
+	}
+}
+
+// PreserveServiceNodePorts preserves the nodePorts allocated to the ports of a Service, matching the ports by name,
+// then by port and protocol. The healthCheckNodePort is preserved as well.
+func PreserveServiceNodePorts(current, desired *corev1.Service) {
+	for i := range desired.Spec.Ports {
+		port := &desired.Spec.Ports[i]
+		if port.NodePort != 0 {
+			continue
+		}
+		for _, currentPort := range current.Spec.Ports {
+			if port.Name == currentPort.Name && (port.Name != "" ||
+				port.Port == currentPort.Port && protocolOrDefault(port.Protocol) == protocolOrDefault(currentPort.Protocol)) {
+				port.NodePort = currentPort.NodePort
+				break
+			}
+		}
+	}
+	if desired.Spec.HealthCheckNodePort == 0 {
+		desired.Spec.HealthCheckNodePort = current.Spec.HealthCheckNodePort
+	}
+}
+
+// protocolOrDefault returns the protocol, or TCP (the API default) if unset.
+func protocolOrDefault(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return protocol
+}
+
+// PreservePersistentVolumeClaimVolumeName preserves the volume a PersistentVolumeClaim is bound to.
+func PreservePersistentVolumeClaimVolumeName(current, desired *corev1.PersistentVolumeClaim) {
+	if desired.Spec.VolumeName == "" {
+		desired.Spec.VolumeName = current.Spec.VolumeName
+	}
+}
+
+// PreserveServiceAccountSecrets preserves the secrets and image pull secrets added to a ServiceAccount by the token
+// controllers.
+func PreserveServiceAccountSecrets(current, desired *corev1.ServiceAccount) {
+	if len(desired.Secrets) == 0 {
+		desired.Secrets = current.Secrets
+	}
+	if len(desired.ImagePullSecrets) == 0 {
+		desired.ImagePullSecrets = current.ImagePullSecrets
+	}
+}
@@ -0,0 +1,131 @@
+// Package presets provides CompareOpts ignoring the fields defaulted by the API server for common workload types.
+//
+// The options ignore a field only when it is unset on the desired object, so values set explicitly are still
+// compared. They expect the current object as the first argument of the comparison and the desired object as the
+// second one, as done by the reconcilers of this module.
+package presets
+
+import (
+	"reflect"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// IgnoreUnset ignores the named fields of the struct type when they are unset (zero) on the desired object, leaving
+// them to the defaults of the API server. The type is given as a value, e.g. appsv1.DeploymentSpec{}.
+func IgnoreUnset(typ any, names ...string) cmp.Option {
+	structType := reflect.TypeOf(typ)
+	fields := make(map[string]bool, len(names))
+	for _, name := range names {
+		fields[name] = true
+	}
+	return cmp.FilterPath(func(p cmp.Path) bool {
+		field, ok := p.Last().(cmp.StructField)
+		if !ok || !fields[field.Name()] || p.Index(-2).Type() != structType {
+			return false
+		}
+		_, desired := field.Values()
+		return !desired.IsValid() || desired.IsZero()
+	}, cmp.Ignore())
+}
+
+// PodTemplate ignores the defaulted fields of pod templates: pod, container, probe, port and volume defaults.
+// Resource quantities and probe ports are compared semantically.
+func PodTemplate() cmp.Options {
+	return cmp.Options{
+		reconciler.SemanticValues(),
+		IgnoreUnset(corev1.PodSpec{},
+			"RestartPolicy", "TerminationGracePeriodSeconds", "DNSPolicy", "SecurityContext", "SchedulerName",
+			"DeprecatedServiceAccount", "EnableServiceLinks", "Priority", "PreemptionPolicy"),
+		IgnoreUnset(corev1.Container{}, "TerminationMessagePath", "TerminationMessagePolicy", "ImagePullPolicy"),
+		IgnoreUnset(corev1.ContainerPort{}, "Protocol"),
+		IgnoreUnset(corev1.Probe{}, "TimeoutSeconds", "PeriodSeconds", "SuccessThreshold", "FailureThreshold"),
+		IgnoreUnset(corev1.HTTPGetAction{}, "Scheme"),
+		IgnoreUnset(corev1.ObjectFieldSelector{}, "APIVersion"),
+		IgnoreUnset(corev1.ConfigMapVolumeSource{}, "DefaultMode"),
+		IgnoreUnset(corev1.SecretVolumeSource{}, "DefaultMode"),
+		IgnoreUnset(corev1.ProjectedVolumeSource{}, "DefaultMode"),
+		IgnoreUnset(corev1.DownwardAPIVolumeSource{}, "DefaultMode"),
+		IgnoreUnset(corev1.EmptyDirVolumeSource{}, "Medium"),
+	}
+}
+
+// Deployment ignores the defaulted fields of Deployments and their pod template.
+func Deployment() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(appsv1.DeploymentSpec{}, "Replicas", "Strategy", "RevisionHistoryLimit", "ProgressDeadlineSeconds"),
+		IgnoreUnset(appsv1.DeploymentStrategy{}, "RollingUpdate"),
+		PodTemplate(),
+	}
+}
+
+// StatefulSet ignores the defaulted fields of StatefulSets, their pod template and volume claim templates.
+func StatefulSet() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(appsv1.StatefulSetSpec{},
+			"Replicas", "PodManagementPolicy", "UpdateStrategy", "RevisionHistoryLimit",
+			"PersistentVolumeClaimRetentionPolicy"),
+		IgnoreUnset(appsv1.StatefulSetUpdateStrategy{}, "RollingUpdate"),
+		IgnoreUnset(corev1.PersistentVolumeClaimSpec{}, "VolumeMode"),
+		PodTemplate(),
+	}
+}
+
+// DaemonSet ignores the defaulted fields of DaemonSets and their pod template.
+func DaemonSet() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(appsv1.DaemonSetSpec{}, "UpdateStrategy", "RevisionHistoryLimit"),
+		IgnoreUnset(appsv1.DaemonSetUpdateStrategy{}, "RollingUpdate"),
+		PodTemplate(),
+	}
+}
+
+// Service ignores the defaulted and allocated fields of Services: type, clusterIPs, IP families, traffic policies,
+// session affinity, and the protocol, targetPort and nodePort of the ports. Target ports are compared semantically.
+func Service() cmp.Options {
+	return cmp.Options{
+		reconciler.CompareIntOrStrings(),
+		IgnoreUnset(corev1.ServiceSpec{},
+			"Type", "ClusterIP", "ClusterIPs", "IPFamilies", "IPFamilyPolicy", "SessionAffinity",
+			"InternalTrafficPolicy", "ExternalTrafficPolicy", "HealthCheckNodePort"),
+		IgnoreUnset(corev1.ServicePort{}, "Protocol", "TargetPort", "NodePort"),
+	}
+}
+
+// Ingress ignores the defaulted fields of Ingresses, such as the class set by the default IngressClass.
+func Ingress() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(networkingv1.IngressSpec{}, "IngressClassName"),
+	}
+}
+
+// Job ignores the defaulted fields of Jobs and their pod template.
+func Job() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(batchv1.JobSpec{},
+			"Parallelism", "Completions", "BackoffLimit", "CompletionMode", "Suspend", "Selector",
+			"ManualSelector", "PodReplacementPolicy"),
+		PodTemplate(),
+	}
+}
+
+// CronJob ignores the defaulted fields of CronJobs and their job template.
+func CronJob() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(batchv1.CronJobSpec{},
+			"ConcurrencyPolicy", "Suspend", "SuccessfulJobsHistoryLimit", "FailedJobsHistoryLimit"),
+		Job(),
+	}
+}
+
+// PersistentVolumeClaim ignores the defaulted and bound fields of PersistentVolumeClaims.
+func PersistentVolumeClaim() cmp.Options {
+	return cmp.Options{
+		IgnoreUnset(corev1.PersistentVolumeClaimSpec{}, "StorageClassName", "VolumeMode", "VolumeName"),
+	}
+}
@@ -0,0 +1,130 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestDeployment(t *testing.T) {
+	desired := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "app",
+						Image: "app:v1",
+						Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+					}},
+				},
+			},
+		},
+	}
+
+	// The current object, as defaulted by the API server
+	current := desired.DeepCopy()
+	current.Spec.Replicas = ptr.To[int32](1)
+	current.Spec.RevisionHistoryLimit = ptr.To[int32](10)
+	current.Spec.ProgressDeadlineSeconds = ptr.To[int32](600)
+	current.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       ptr.To(intstr.FromString("25%")),
+			MaxUnavailable: ptr.To(intstr.FromString("25%")),
+		},
+	}
+	pod := &current.Spec.Template.Spec
+	pod.RestartPolicy = corev1.RestartPolicyAlways
+	pod.TerminationGracePeriodSeconds = ptr.To[int64](30)
+	pod.DNSPolicy = corev1.DNSClusterFirst
+	pod.SecurityContext = &corev1.PodSecurityContext{}
+	pod.SchedulerName = corev1.DefaultSchedulerName
+	pod.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+	pod.Containers[0].TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	pod.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+	pod.Containers[0].Ports[0].Protocol = corev1.ProtocolTCP
+
+	assert.False(t, cmp.Equal(current, desired))
+	assert.True(t, cmp.Equal(current, desired, Deployment()), cmp.Diff(current, desired, Deployment()))
+
+	// Fields set on the desired object are still compared
+	desired.Spec.RevisionHistoryLimit = ptr.To[int32](3)
+	assert.False(t, cmp.Equal(current, desired, Deployment()))
+}
+
+func TestService(t *testing.T) {
+	desired := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+		},
+	}
+
+	current := desired.DeepCopy()
+	current.Spec.Type = corev1.ServiceTypeClusterIP
+	current.Spec.ClusterIP = "10.0.0.10"
+	current.Spec.ClusterIPs = []string{"10.0.0.10"}
+	current.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+	current.Spec.IPFamilyPolicy = ptr.To(corev1.IPFamilyPolicySingleStack)
+	current.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	current.Spec.InternalTrafficPolicy = ptr.To(corev1.ServiceInternalTrafficPolicyCluster)
+	current.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+	current.Spec.Ports[0].TargetPort = intstr.FromInt32(80)
+
+	assert.True(t, cmp.Equal(current, desired, Service()), cmp.Diff(current, desired, Service()))
+
+	desired.Spec.Type = corev1.ServiceTypeNodePort
+	assert.False(t, cmp.Equal(current, desired, Service()))
+}
+
+func TestSemanticValues(t *testing.T) {
+	current := &corev1.Container{
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}},
+		LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt32(8080)},
+		}},
+	}
+	desired := &corev1.Container{
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1000m"),
+			corev1.ResourceMemory: resource.MustParse("1024Mi"),
+		}},
+		LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromString("8080")},
+		}},
+	}
+
+	assert.True(t, cmp.Equal(current, desired, reconciler.SemanticValues()), cmp.Diff(current, desired, reconciler.SemanticValues()))
+
+	desired.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("500m")
+	assert.False(t, cmp.Equal(current, desired, reconciler.SemanticValues()))
+}
+
+func TestSortedLists(t *testing.T) {
+	current := &corev1.Container{
+		Env:          []corev1.EnvVar{{Name: "B", Value: "2"}, {Name: "A", Value: "1"}},
+		Ports:        []corev1.ContainerPort{{ContainerPort: 9090}, {ContainerPort: 8080}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}, {Name: "config", MountPath: "/config"}},
+	}
+	desired := &corev1.Container{
+		Env:          []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+		Ports:        []corev1.ContainerPort{{ContainerPort: 8080}, {ContainerPort: 9090}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/config"}, {Name: "data", MountPath: "/data"}},
+	}
+	opts := cmp.Options{reconciler.SortedEnvVars(), reconciler.SortedPorts(), reconciler.SortedVolumeMounts()}
+
+	assert.False(t, cmp.Equal(current, desired))
+	assert.True(t, cmp.Equal(current, desired, opts), cmp.Diff(current, desired, opts))
+
+	desired.Env[1].Value = "3"
+	assert.False(t, cmp.Equal(current, desired, opts))
+}
@@ -0,0 +1,83 @@
+package reconciler
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsReady reports whether the object is ready, with a message explaining why not:
+//   - Deployments are Available and fully rolled out
+//   - StatefulSets and DaemonSets have every replica updated and ready
+//   - Jobs are Complete
+//   - other objects report a Ready condition with a True status, if they have conditions
+//
+// Objects being deleted are never ready.
+func IsReady(obj client.Object) (bool, string, error) {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return false, "the object is being deleted", nil
+	}
+
+	switch obj := obj.(type) {
+	case *appsv1.Deployment:
+		if obj.Status.ObservedGeneration < obj.Generation {
+			return false, "the rollout has not been observed yet", nil
+		}
+		replicas := ptr.Deref(obj.Spec.Replicas, int32(1))
+		if obj.Status.UpdatedReplicas < replicas {
+			return false, fmt.Sprintf("%d of %d replicas are updated", obj.Status.UpdatedReplicas, replicas), nil
+		}
+		for _, condition := range obj.Status.Conditions {
+			if condition.Type == appsv1.DeploymentAvailable {
+				return condition.Status == corev1.ConditionTrue, condition.Message, nil
+			}
+		}
+		return false, "the deployment reports no Available condition", nil
+	case *appsv1.StatefulSet:
+		replicas := ptr.Deref(obj.Spec.Replicas, int32(1))
+		if obj.Status.ObservedGeneration < obj.Generation {
+			return false, "the rollout has not been observed yet", nil
+		}
+		if obj.Status.UpdatedReplicas < replicas || obj.Status.ReadyReplicas < replicas {
+			return false, fmt.Sprintf("%d of %d replicas are updated and %d ready", obj.Status.UpdatedReplicas, replicas,
+				obj.Status.ReadyReplicas), nil
+		}
+		return true, "", nil
+	case *appsv1.DaemonSet:
+		desired := obj.Status.DesiredNumberScheduled
+		if obj.Status.ObservedGeneration < obj.Generation {
+			return false, "the rollout has not been observed yet", nil
+		}
+		if obj.Status.UpdatedNumberScheduled < desired || obj.Status.NumberReady < desired {
+			return false, fmt.Sprintf("%d of %d pods are updated and %d ready", obj.Status.UpdatedNumberScheduled, desired,
+				obj.Status.NumberReady), nil
+		}
+		return true, "", nil
+	case *batchv1.Job:
+		for _, condition := range obj.Status.Conditions {
+			if condition.Type == batchv1.JobComplete && condition.Status == corev1.ConditionTrue {
+				return true, "", nil
+			}
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				return false, fmt.Sprintf("the job failed: %s", condition.Message), nil
+			}
+		}
+		return false, "the job is not complete", nil
+	}
+
+	conditions, err := ConditionsFromObject(obj)
+	if err != nil || len(conditions) == 0 {
+		return err == nil, "", err
+	}
+	condition := meta.FindStatusCondition(conditions, "Ready")
+	if condition == nil {
+		return false, "the object reports no Ready condition", nil
+	}
+	return condition.Status == metav1.ConditionTrue, condition.Message, nil
+}
@@ -0,0 +1,170 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"sigs.k8s.io/yaml"
+)
+
+// ChartMediaType is the media type of the layer holding the archive of a chart pushed to an OCI registry with
+// `helm push`.
+const ChartMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// ErrUnsupportedChart is returned when loading a chart using a feature this package doesn't implement, such as
+// dependencies.
+var ErrUnsupportedChart = errors.New("unsupported chart")
+
+// Chart is a Helm chart loaded in memory.
+type Chart struct {
+	// Metadata is the content of the Chart.yaml file.
+	Metadata Metadata
+	// Values are the default values of the values.yaml file.
+	Values map[string]interface{}
+	// Templates are the files of the templates directory, by path relative to the root of the chart
+	// (e.g. "templates/service.yaml").
+	Templates map[string]string
+	// Files are the other files of the chart, available to the templates as .Files.
+	Files Files
+}
+
+// Metadata is the subset of the Chart.yaml file available to the templates as .Chart.
+type Metadata struct {
+	APIVersion   string        `json:"apiVersion"`
+	Name         string        `json:"name"`
+	Version      string        `json:"version"`
+	AppVersion   string        `json:"appVersion,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Type         string        `json:"type,omitempty"`
+	KubeVersion  string        `json:"kubeVersion,omitempty"`
+	Dependencies []interface{} `json:"dependencies,omitempty"`
+}
+
+// Files are the files of a chart outside of its templates, by path relative to the root of the chart.
+type Files map[string][]byte
+
+// Get returns the content of the file, or an empty string if it doesn't exist.
+func (f Files) Get(name string) string {
+	return string(f[name])
+}
+
+// GetBytes returns the content of the file, or nil if it doesn't exist.
+func (f Files) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// LoadFS loads the chart at the root of fsys, e.g. an embedded directory:
+//
+//	//go:embed all:charts/redis
+//	var redisChart embed.FS
+//
+//	sub, _ := fs.Sub(redisChart, "charts/redis")
+//	chart, err := helm.LoadFS(sub)
+//
+// The all: prefix embeds the partials of the chart, whose names start with an underscore.
+func LoadFS(fsys fs.FS) (*Chart, error) {
+	files := map[string][]byte{}
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		files[name], err = fs.ReadFile(fsys, name)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading chart: %w", err)
+	}
+	return load(files)
+}
+
+// LoadArchive loads a chart archive, as built by `helm package` or pulled from a registry with NewOCISource. The
+// archive fails with source.ErrTooLarge beyond source.DefaultMaxSize bytes once extracted.
+func LoadArchive(data []byte) (*Chart, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading chart archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	var remaining int64 = source.DefaultMaxSize
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading chart archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// The files of the archive are in a directory named after the chart
+		_, name, ok := strings.Cut(path.Clean(header.Name), "/")
+		if !ok {
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(archive, remaining+1))
+		if err != nil {
+			return nil, fmt.Errorf("reading chart archive: %w", err)
+		}
+		if remaining -= int64(len(content)); remaining < 0 {
+			return nil, fmt.Errorf("reading chart archive: %w: exceeds %d bytes", source.ErrTooLarge, source.DefaultMaxSize)
+		}
+		files[name] = content
+	}
+	return load(files)
+}
+
+// NewOCISource returns a source pulling the archive of a chart pushed to an OCI registry, for FromSource, e.g.
+// NewOCISource("ghcr.io/acme/charts/redis:1.2.0"). Set the credentials on the returned source if needed.
+func NewOCISource(reference string) *source.OCI {
+	return &source.OCI{Reference: reference, MediaTypes: []string{ChartMediaType}, Archive: true}
+}
+
+// load builds the chart from its files, by path relative to the root of the chart.
+func load(files map[string][]byte) (*Chart, error) {
+	metadata, ok := files["Chart.yaml"]
+	if !ok {
+		return nil, errors.New("chart has no Chart.yaml file")
+	}
+	chart := &Chart{Values: map[string]interface{}{}, Templates: map[string]string{}, Files: Files{}}
+	if err := yaml.Unmarshal(metadata, &chart.Metadata); err != nil {
+		return nil, fmt.Errorf("decoding Chart.yaml: %w", err)
+	}
+	if chart.Metadata.Name == "" {
+		return nil, errors.New("chart has no name in Chart.yaml")
+	}
+	if len(chart.Metadata.Dependencies) > 0 {
+		return nil, fmt.Errorf("%w: %s has dependencies", ErrUnsupportedChart, chart.Metadata.Name)
+	}
+
+	for name, content := range files {
+		switch {
+		case name == "Chart.yaml":
+		case name == "values.yaml":
+			if err := yaml.Unmarshal(content, &chart.Values); err != nil {
+				return nil, fmt.Errorf("decoding values.yaml: %w", err)
+			}
+			if chart.Values == nil {
+				chart.Values = map[string]interface{}{}
+			}
+		case strings.HasPrefix(name, "templates/"):
+			chart.Templates[name] = string(content)
+		case strings.HasPrefix(name, "charts/"):
+			return nil, fmt.Errorf("%w: %s has subcharts", ErrUnsupportedChart, chart.Metadata.Name)
+		default:
+			chart.Files[name] = content
+		}
+	}
+	return chart, nil
+}
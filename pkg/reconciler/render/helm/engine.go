@@ -0,0 +1,421 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler/render"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// maxIncludeDepth bounds the nesting of include and tpl calls, so a recursive template fails instead of exhausting the
+// stack.
+const maxIncludeDepth = 100
+
+// Release is the release a chart is rendered for, available to the templates as .Release.
+type Release struct {
+	// Name is the name of the release, used by most charts to name their objects.
+	Name string // required
+	// Namespace is the namespace of the release.
+	Namespace string // optional
+	// Service is the service rendering the release. Defaults to "Maestro".
+	Service string // optional
+	// Revision is the revision of the release. Defaults to 1.
+	Revision int // optional
+	// IsUpgrade renders the chart as an upgrade of the release rather than an install.
+	IsUpgrade bool // optional
+}
+
+// Capabilities are the capabilities of the cluster, available to the templates as .Capabilities.
+type Capabilities struct {
+	// KubeVersion is the version of Kubernetes.
+	KubeVersion KubeVersion
+	// APIVersions are the API versions available, as "group/version" or "group/version/Kind".
+	APIVersions APIVersions
+}
+
+// KubeVersion is a version of Kubernetes.
+type KubeVersion struct {
+	Version string
+	Major   string
+	Minor   string
+}
+
+// String returns the version.
+func (v KubeVersion) String() string {
+	return v.Version
+}
+
+// APIVersions is a set of API versions.
+type APIVersions []string
+
+// Has returns true if the API version is in the set.
+func (a APIVersions) Has(apiVersion string) bool {
+	return slices.Contains(a, apiVersion)
+}
+
+// DefaultCapabilities are the capabilities the charts are rendered with unless set.
+var DefaultCapabilities = Capabilities{KubeVersion: KubeVersion{Version: "v1.29.0", Major: "1", Minor: "29"}}
+
+// Renderer renders a chart for a release, implementing render.Renderer. The values it is given are merged over the
+// default values of the chart.
+type Renderer struct {
+	// Chart is the chart to render.
+	Chart *Chart // required
+	// Release is the release the chart is rendered for.
+	Release Release // required
+	// Capabilities are the capabilities of the cluster. Defaults to DefaultCapabilities.
+	Capabilities *Capabilities // optional
+}
+
+var _ render.Renderer = &Renderer{}
+
+// documentSeparator splits a rendered template into its documents.
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// Render renders the templates of the chart into a multi-document manifest, in lexical order of their paths. Partials
+// (whose names start with an underscore), NOTES.txt, and the documents annotated as hooks are left out.
+func (r *Renderer) Render(_ context.Context, values map[string]interface{}) ([]byte, error) {
+	chart := r.Chart
+	merged := mergeValues(runtime.DeepCopyJSON(chart.Values), values)
+	capabilities := DefaultCapabilities
+	if r.Capabilities != nil {
+		capabilities = *r.Capabilities
+	}
+	release := r.Release
+	if release.Service == "" {
+		release.Service = "Maestro"
+	}
+	if release.Revision == 0 {
+		release.Revision = 1
+	}
+
+	tmpl := template.New(chart.Metadata.Name).Option("missingkey=zero")
+	depth := 0
+	funcs := Funcs()
+	funcs["include"] = func(name string, data interface{}) (string, error) {
+		if depth++; depth > maxIncludeDepth {
+			return "", fmt.Errorf("including %s: nested too deeply", name)
+		}
+		defer func() { depth-- }()
+		var out bytes.Buffer
+		err := tmpl.ExecuteTemplate(&out, name, data)
+		return out.String(), err
+	}
+	funcs["tpl"] = func(text string, data interface{}) (string, error) {
+		if depth++; depth > maxIncludeDepth {
+			return "", errors.New("tpl: nested too deeply")
+		}
+		defer func() { depth-- }()
+		clone, err := tmpl.Clone()
+		if err != nil {
+			return "", err
+		}
+		if _, err := clone.New("tpl").Parse(text); err != nil {
+			return "", fmt.Errorf("tpl: %w", err)
+		}
+		var out bytes.Buffer
+		err = clone.ExecuteTemplate(&out, "tpl", data)
+		return out.String(), err
+	}
+	tmpl.Funcs(funcs)
+
+	names := make([]string, 0, len(chart.Templates))
+	for name := range chart.Templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, err := tmpl.New(path.Join(chart.Metadata.Name, name)).Parse(chart.Templates[name]); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+	}
+
+	var manifest bytes.Buffer
+	for _, name := range names {
+		if base := path.Base(name); strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+			continue
+		}
+		fullName := path.Join(chart.Metadata.Name, name)
+		data := map[string]interface{}{
+			"Values": merged,
+			"Release": map[string]interface{}{
+				"Name":      release.Name,
+				"Namespace": release.Namespace,
+				"Service":   release.Service,
+				"Revision":  release.Revision,
+				"IsInstall": !release.IsUpgrade,
+				"IsUpgrade": release.IsUpgrade,
+			},
+			"Chart":        chart.Metadata,
+			"Files":        chart.Files,
+			"Capabilities": capabilities,
+			"Template":     map[string]interface{}{"Name": fullName, "BasePath": path.Join(chart.Metadata.Name, "templates")},
+		}
+		var out bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&out, fullName, data); err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", name, err)
+		}
+		for _, document := range documentSeparator.Split(strings.ReplaceAll(out.String(), "<no value>", ""), -1) {
+			if strings.TrimSpace(document) == "" || isHook(document) {
+				continue
+			}
+			fmt.Fprintf(&manifest, "---\n# Source: %s\n%s\n", fullName, strings.Trim(document, "\n"))
+		}
+	}
+	return manifest.Bytes(), nil
+}
+
+// isHook returns true if the document has the helm.sh/hook annotation. Hooks are run by Helm around an install or an
+// upgrade, which has no equivalent in a reconcile loop.
+func isHook(document string) bool {
+	object := struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := yaml.Unmarshal([]byte(document), &object); err != nil {
+		return false
+	}
+	return object.Metadata.Annotations["helm.sh/hook"] != ""
+}
+
+// mergeValues merges the values over the defaults, recursively for maps. A nil value removes the default.
+func mergeValues(defaults, values map[string]interface{}) map[string]interface{} {
+	for key, value := range values {
+		if value == nil {
+			delete(defaults, key)
+			continue
+		}
+		if valueMap, ok := value.(map[string]interface{}); ok {
+			if defaultMap, ok := defaults[key].(map[string]interface{}); ok {
+				defaults[key] = mergeValues(defaultMap, valueMap)
+				continue
+			}
+		}
+		defaults[key] = value
+	}
+	return defaults
+}
+
+// Funcs returns the functions available to the templates, a subset of the Helm ones following their semantics:
+//   - include and tpl render a named template or a string; required and fail stop the rendering with an error
+//   - toYaml, fromYaml and toJson convert between values and YAML or JSON
+//   - default, empty, coalesce and ternary choose between values
+//   - quote, squote, indent, nindent, trim, trimPrefix, trimSuffix, trunc, upper, lower, replace, contains, hasPrefix,
+//     hasSuffix, join, toString, b64enc, b64dec and sha256sum work on strings
+//   - list, dict, hasKey and get build and read lists and maps; int, add and sub work on integers
+//
+// include and tpl are bound to the chart being rendered by the Renderer. A template calling another function fails
+// to parse.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"include": func(string, interface{}) (string, error) { return "", errors.New("include: not rendering a chart") },
+		"tpl":     func(string, interface{}) (string, error) { return "", errors.New("tpl: not rendering a chart") },
+		"required": func(message string, value interface{}) (interface{}, error) {
+			if value == nil || value == "" {
+				return nil, errors.New(message)
+			}
+			return value, nil
+		},
+		"fail": func(message string) (string, error) { return "", errors.New(message) },
+		"toYaml": func(value interface{}) string {
+			data, err := yaml.Marshal(value)
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSuffix(string(data), "\n")
+		},
+		"fromYaml": func(text string) map[string]interface{} {
+			value := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(text), &value); err != nil {
+				return map[string]interface{}{"Error": err.Error()}
+			}
+			return value
+		},
+		"toJson": func(value interface{}) string {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return ""
+			}
+			return string(data)
+		},
+		"default": func(defaultValue interface{}, given ...interface{}) interface{} {
+			if len(given) == 0 || empty(given[0]) {
+				return defaultValue
+			}
+			return given[0]
+		},
+		"empty": empty,
+		"coalesce": func(values ...interface{}) interface{} {
+			for _, value := range values {
+				if !empty(value) {
+					return value
+				}
+			}
+			return nil
+		},
+		"ternary": func(whenTrue, whenFalse interface{}, condition bool) interface{} {
+			if condition {
+				return whenTrue
+			}
+			return whenFalse
+		},
+		"quote": func(values ...interface{}) string {
+			return quoteAll(values, strconv.Quote)
+		},
+		"squote": func(values ...interface{}) string {
+			return quoteAll(values, func(s string) string { return "'" + s + "'" })
+		},
+		"indent": indent,
+		"nindent": func(spaces int, s string) string {
+			return "\n" + indent(spaces, s)
+		},
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"trunc": func(length int, s string) string {
+			switch {
+			case length >= 0 && len(s) > length:
+				return s[:length]
+			case length < 0 && len(s) > -length:
+				return s[len(s)+length:]
+			}
+			return s
+		},
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"replace":   func(old, replacement, s string) string { return strings.ReplaceAll(s, old, replacement) },
+		"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"join": func(separator string, values interface{}) string {
+			var parts []string
+			list := reflect.ValueOf(values)
+			if list.Kind() == reflect.Slice || list.Kind() == reflect.Array {
+				for i := 0; i < list.Len(); i++ {
+					parts = append(parts, toString(list.Index(i).Interface()))
+				}
+			}
+			return strings.Join(parts, separator)
+		},
+		"toString": toString,
+		"b64enc":   func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec": func(s string) string {
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return err.Error()
+			}
+			return string(data)
+		},
+		"sha256sum": func(s string) string {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		},
+		"list": func(values ...interface{}) []interface{} { return values },
+		"dict": func(pairs ...interface{}) map[string]interface{} {
+			dict := map[string]interface{}{}
+			for i := 0; i+1 < len(pairs); i += 2 {
+				dict[toString(pairs[i])] = pairs[i+1]
+			}
+			return dict
+		},
+		"hasKey": func(dict map[string]interface{}, key string) bool {
+			_, ok := dict[key]
+			return ok
+		},
+		"get": func(dict map[string]interface{}, key string) interface{} {
+			if value, ok := dict[key]; ok {
+				return value
+			}
+			return ""
+		},
+		"int": toInt,
+		"add": func(values ...interface{}) int64 {
+			var sum int64
+			for _, value := range values {
+				sum += toInt(value)
+			}
+			return sum
+		},
+		"sub": func(a, b interface{}) int64 { return toInt(a) - toInt(b) },
+	}
+}
+
+// empty returns true if the value is nil or the zero value of its type, or an empty collection.
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// quoteAll quotes the string form of the non-nil values, separated by spaces.
+func quoteAll(values []interface{}, quote func(string) string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			quoted = append(quoted, quote(toString(value)))
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// indent indents every line of a string by a number of spaces.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// toString returns the string form of a value, without the exponent YAML numbers are printed with.
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// toInt converts a number, or the string form of one, to an integer. Other values are 0.
+func toInt(value interface{}) int64 {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(v.Float())
+	case reflect.String:
+		i, _ := strconv.ParseInt(v.String(), 10, 64)
+		return i
+	}
+	return 0
+}
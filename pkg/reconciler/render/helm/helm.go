@@ -0,0 +1,143 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/render"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler (HelmReconciler) renders a Helm chart for a release of the parent, with values computed from the parent,
+// then applies the rendered objects as children of the parent and prunes the children that are no longer rendered,
+// see render.Reconciler. The chart is embedded in the operator (see LoadFS) or fetched from a Source, e.g. an OCI
+// registry (see NewOCISource).
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	// Set the ChildGVKs to the kinds rendered for the conductor to watch them.
+	Details api.Descriptor // required
+	// Chart is the chart to render.
+	Chart *Chart // required unless Source is set
+	// Source is the source of the archive of the chart, loaded again only when it changes. Wrap it in a
+	// source.Cache to avoid fetching it on every reconcile.
+	Source source.ManifestSource // optional
+	// ReleaseFn returns the release the chart is rendered for. Defaults to a release named after the parent, in its
+	// namespace.
+	ReleaseFn func(parent Parent) Release // optional
+	// Capabilities are the capabilities of the cluster available to the templates. Defaults to DefaultCapabilities.
+	Capabilities *Capabilities // optional
+	// ValuesFn returns the values merged over the default values of the chart.
+	// If nil, the chart is rendered with its default values.
+	ValuesFn func(ctx context.Context, parent Parent) (map[string]interface{}, error) // optional
+	// PredicateFn is a function that returns true if the rendered objects should be reconciled.
+	// If nil, they will always be reconciled.
+	PredicateFn func(parent Parent) bool // optional
+	// DryRunType configures the dry-run behavior of the reconciler.
+	DryRunType reconciler.DryRunType // optional
+	// CompareOpts are the options to use when comparing the children to their desired state, such as
+	// reconciler.IgnoreUnstructuredFields.
+	CompareOpts []cmp.Option // optional
+	// LabelsFn returns the labels added to every child, used to find the children to prune.
+	// They must uniquely identify the children of this reconciler for the parent.
+	// Defaults to the labels of render.Reconciler.
+	LabelsFn func(parent Parent) map[string]string // optional
+	// NoPrune disables deleting the children that are no longer rendered.
+	NoPrune bool // optional
+
+	mu            sync.Mutex
+	sourceArchive []byte
+	loaded        *Chart
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object]{}
+)
+
+// Reconcile renders the chart for the release of the parent, creates or updates every rendered object, then prunes the
+// children that are no longer rendered.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.Chart == nil && r.Source == nil {
+		err := r.Validate()
+		conductor.RecordResult(ctx, r.Details.Name, reconcile.Result{}, err)
+		return reconcile.Result{}, err
+	}
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		conductor.RecordResult(ctx, r.Details.Name, reconcile.Result{}, nil)
+		return reconcile.Result{}, nil
+	}
+	chart, err := r.chart(ctx)
+	if err != nil {
+		conductor.RecordResult(ctx, r.Details.Name, reconcile.Result{}, err)
+		return reconcile.Result{}, err
+	}
+
+	renderer := &render.Reconciler[Parent]{
+		Details:     r.Details,
+		Renderer:    &Renderer{Chart: chart, Release: r.release(parent), Capabilities: r.Capabilities},
+		ValuesFn:    r.ValuesFn,
+		DryRunType:  r.DryRunType,
+		CompareOpts: r.CompareOpts,
+		LabelsFn:    r.LabelsFn,
+		NoPrune:     r.NoPrune,
+	}
+	return renderer.Reconcile(ctx, k8sCli, parent)
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// Validate returns an ErrInvalidConfiguration error if the Details name, or both the Chart and the Source, are
+// missing.
+func (r *Reconciler[Parent]) Validate() error {
+	var errs []error
+	if r.Details.Name == "" {
+		errs = append(errs, fmt.Errorf("%w: the Details name of the helm reconciler is empty", reconciler.ErrInvalidConfiguration))
+	}
+	if r.Chart == nil && r.Source == nil {
+		errs = append(errs, fmt.Errorf("%w: %s has no Chart or Source", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// release returns the release of the parent.
+func (r *Reconciler[Parent]) release(parent Parent) Release {
+	if r.ReleaseFn != nil {
+		return r.ReleaseFn(parent)
+	}
+	return Release{Name: parent.GetName(), Namespace: parent.GetNamespace()}
+}
+
+// chart returns the Chart, or the chart fetched from the Source, loaded again only when its archive changed.
+func (r *Reconciler[Parent]) chart(ctx context.Context) (*Chart, error) {
+	if r.Source == nil {
+		return r.Chart, nil
+	}
+	archive, err := r.Source.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching chart: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded == nil || !bytes.Equal(r.sourceArchive, archive) {
+		loaded, err := LoadArchive(archive)
+		if err != nil {
+			return nil, err
+		}
+		r.loaded, r.sourceArchive = loaded, archive
+	}
+	return r.loaded, nil
+}
@@ -0,0 +1,94 @@
+package helm
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// FromChart returns a new instance of Builder for the chart, e.g. embedded in the operator and loaded with LoadFS.
+func FromChart[Parent client.Object](chart *Chart) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			Chart:       chart,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+			DryRunType:  reconciler.DryRunWarn,
+		},
+	}
+}
+
+// FromSource returns a new instance of Builder for the chart archive fetched from the source, e.g. NewOCISource.
+// Wrap the source in a source.Cache to avoid fetching it on every reconcile.
+func FromSource[Parent client.Object](src source.ManifestSource) *Builder[Parent] {
+	b := FromChart[Parent](nil)
+	b.reconciler.Source = src
+	return b
+}
+
+// WithReleaseFn sets the ReleaseFn field.
+func (b *Builder[Parent]) WithReleaseFn(fn func(parent Parent) Release) *Builder[Parent] {
+	b.reconciler.ReleaseFn = fn
+	return b
+}
+
+// WithCapabilities sets the Capabilities field.
+func (b *Builder[Parent]) WithCapabilities(capabilities Capabilities) *Builder[Parent] {
+	b.reconciler.Capabilities = &capabilities
+	return b
+}
+
+// WithValuesFn sets the ValuesFn field.
+func (b *Builder[Parent]) WithValuesFn(fn func(ctx context.Context, parent Parent) (map[string]interface{}, error)) *Builder[Parent] {
+	b.reconciler.ValuesFn = fn
+	return b
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// WithDryRunType configures the dry-run behavior of the reconciler.
+func (b *Builder[Parent]) WithDryRunType(dryRunType reconciler.DryRunType) *Builder[Parent] {
+	b.reconciler.DryRunType = dryRunType
+	return b
+}
+
+// AddCompareOpt adds a comparator option to the reconciler
+func (b *Builder[Parent]) AddCompareOpt(compareOpts []cmp.Option) *Builder[Parent] {
+	b.reconciler.CompareOpts = append(b.reconciler.CompareOpts, compareOpts...)
+	return b
+}
+
+// WithDetails sets the Details field.
+func (b *Builder[Parent]) WithDetails(details api.Descriptor) *Builder[Parent] {
+	b.reconciler.Details = details
+	return b
+}
+
+// WithLabelsFn sets the LabelsFn field.
+func (b *Builder[Parent]) WithLabelsFn(fn func(parent Parent) map[string]string) *Builder[Parent] {
+	b.reconciler.LabelsFn = fn
+	return b
+}
+
+// WithNoPrune sets the NoPrune field.
+func (b *Builder[Parent]) WithNoPrune(noPrune bool) *Builder[Parent] {
+	b.reconciler.NoPrune = noPrune
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
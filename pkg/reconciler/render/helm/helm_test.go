@@ -0,0 +1,235 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+//go:embed all:testdata/cache
+var testdata embed.FS
+
+func loadCache(t *testing.T) *Chart {
+	sub, err := fs.Sub(testdata, "testdata/cache")
+	require.NoError(t, err)
+	chart, err := LoadFS(sub)
+	require.NoError(t, err)
+	return chart
+}
+
+func newClient(t *testing.T, parent client.Object) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	return fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(parent).Build()
+}
+
+func TestRender(t *testing.T) {
+	chart := loadCache(t)
+	assert.Equal(t, "cache", chart.Metadata.Name)
+	assert.Equal(t, "7.2.4", chart.Metadata.AppVersion)
+	assert.Contains(t, chart.Templates, "templates/_helpers.tpl")
+	assert.Contains(t, chart.Files, "files/redis.conf")
+
+	renderer := &Renderer{Chart: chart, Release: Release{Name: "app", Namespace: "default"}}
+	manifest, err := renderer.Render(context.Background(), map[string]interface{}{
+		"service": map[string]interface{}{"port": 7000},
+		"config":  map[string]interface{}{"maxmemory": "128mb"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), "# Source: cache/templates/configmap.yaml")
+	objects, err := reconciler.DecodeManifests(manifest)
+	require.NoError(t, err)
+	require.Len(t, objects, 2, "the hook and the notes should be left out")
+
+	configMap := objects[0]
+	assert.Equal(t, "ConfigMap", configMap.GetKind())
+	assert.Equal(t, "app-cache", configMap.GetName())
+	assert.Equal(t, map[string]string{
+		"app.kubernetes.io/name":       "cache",
+		"app.kubernetes.io/instance":   "app",
+		"app.kubernetes.io/version":    "7.2.4",
+		"app.kubernetes.io/managed-by": "Maestro",
+	}, configMap.GetLabels())
+	data := configMap.Object["data"].(map[string]interface{})
+	assert.Equal(t, "redis:7.2.4", data["image"])
+	assert.Equal(t, "appendonly no\nmaxmemory 128mb\nmaxmemory-policy allkeys-lru\n", data["redis.conf"])
+
+	service := objects[1]
+	assert.Equal(t, "Service", service.GetKind())
+	assert.Equal(t, "app-cache", service.GetName())
+	ports := service.Object["spec"].(map[string]interface{})["ports"].([]interface{})
+	assert.EqualValues(t, 7000, ports[0].(map[string]interface{})["port"])
+
+	// A nil value removes the default
+	manifest, err = renderer.Render(context.Background(), map[string]interface{}{"service": map[string]interface{}{"enabled": nil}})
+	require.NoError(t, err)
+	objects, err = reconciler.DecodeManifests(manifest)
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, true, chart.Values["service"].(map[string]interface{})["enabled"], "the defaults should be left untouched")
+}
+
+func TestRenderErrors(t *testing.T) {
+	chart := func(template string) *Chart {
+		return &Chart{
+			Metadata:  Metadata{Name: "broken"},
+			Values:    map[string]interface{}{},
+			Templates: map[string]string{"templates/_helpers.tpl": `{{ define "loop" }}{{ include "loop" . }}{{ end }}`, "templates/object.yaml": template},
+		}
+	}
+	ctx := context.Background()
+
+	_, err := (&Renderer{Chart: chart(`{{ required "name is required" .Values.name }}`)}).Render(ctx, nil)
+	require.ErrorContains(t, err, "name is required")
+	_, err = (&Renderer{Chart: chart(`{{ include "loop" . }}`)}).Render(ctx, nil)
+	require.ErrorContains(t, err, "nested too deeply")
+	_, err = (&Renderer{Chart: chart(`{{ lookup "v1" "Secret" "default" "creds" }}`)}).Render(ctx, nil)
+	require.ErrorContains(t, err, `function "lookup" not defined`)
+
+	_, err = LoadFS(fstest.MapFS{"Chart.yaml": {Data: []byte("name: app\ndependencies:\n- name: redis\n")}})
+	require.ErrorIs(t, err, ErrUnsupportedChart)
+	_, err = LoadFS(fstest.MapFS{
+		"Chart.yaml":              {Data: []byte("name: app\n")},
+		"charts/redis/Chart.yaml": {Data: []byte("name: redis\n")},
+	})
+	require.ErrorIs(t, err, ErrUnsupportedChart)
+	_, err = LoadFS(fstest.MapFS{"values.yaml": {Data: []byte("{}")}})
+	require.ErrorContains(t, err, "no Chart.yaml")
+}
+
+func TestHelmReconciler(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := newClient(t, parent)
+
+	serviceEnabled := true
+	r := FromChart[*corev1.ConfigMap](loadCache(t)).
+		WithDetails(api.Descriptor{Name: "Cache", ChildGVKs: []schema.GroupVersionKind{
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+			corev1.SchemeGroupVersion.WithKind("Service"),
+		}}).
+		WithValuesFn(func(_ context.Context, parent *corev1.ConfigMap) (map[string]interface{}, error) {
+			return map[string]interface{}{"service": map[string]interface{}{"enabled": serviceEnabled}}, nil
+		}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+	require.NoError(t, r.Validate())
+
+	ctx := context.Background()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app-cache", Namespace: "default"}, configMap))
+	assert.True(t, metav1.IsControlledBy(configMap, parent))
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app-cache", Namespace: "default"}, &corev1.Service{}))
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "app-cache-test", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err), "hooks should not be applied")
+
+	// Steady state
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// Children no longer rendered are pruned
+	serviceEnabled = false
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "app-cache", Namespace: "default"}, &corev1.Service{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	r = FromChart[*corev1.ConfigMap](nil).Build()
+	require.ErrorIs(t, r.Validate(), reconciler.ErrInvalidConfiguration)
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+}
+
+// archive packages the test chart as `helm package` does, under a directory named after the chart.
+func archive(t *testing.T) []byte {
+	var data bytes.Buffer
+	gz := gzip.NewWriter(&data)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, fs.WalkDir(testdata, "testdata/cache", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := testdata.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Name: path.Join("cache", strings.TrimPrefix(name, "testdata/cache/")), Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tw.Write(content)
+		return err
+	}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return data.Bytes()
+}
+
+func TestOCIChart(t *testing.T) {
+	chart := archive(t)
+	artifact, err := json.Marshal(map[string]interface{}{"layers": []map[string]string{
+		{"mediaType": "application/vnd.cncf.helm.config.v1+json", "digest": source.Digest([]byte("{}"))},
+		{"mediaType": ChartMediaType, "digest": source.Digest(chart)},
+	}})
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/acme/charts/cache/manifests/0.1.0":
+			_, _ = w.Write(artifact)
+		case "/v2/acme/charts/cache/blobs/" + source.Digest(chart):
+			_, _ = w.Write(chart)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := newClient(t, parent)
+
+	src := NewOCISource(strings.TrimPrefix(server.URL, "http://") + "/acme/charts/cache:0.1.0")
+	src.PlainHTTP = true
+	r := FromSource[*corev1.ConfigMap](source.NewCache(src, 0)).
+		WithDetails(api.Descriptor{Name: "Cache"}).
+		WithReleaseFn(func(parent *corev1.ConfigMap) Release { return Release{Name: parent.Name + "-redis"} }).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+	ctx := context.Background()
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app-redis-cache", Namespace: "default"}, &corev1.ConfigMap{}))
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app-redis-cache", Namespace: "default"}, &corev1.Service{}))
+
+	_, err = LoadArchive([]byte("not an archive"))
+	require.Error(t, err)
+}
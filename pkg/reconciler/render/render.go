@@ -0,0 +1,125 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/bundle"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Renderer renders values into a multi-document YAML manifest. This package ships no rendering engine: wrap the one of
+// your manifests in a Renderer, e.g. Kustomize or a generator of your own. For Helm charts, see the helm package; for
+// Go templates, see the template reconciler.
+type Renderer interface {
+	Render(ctx context.Context, values map[string]interface{}) ([]byte, error)
+}
+
+// RenderFunc is a function implementing Renderer.
+type RenderFunc func(ctx context.Context, values map[string]interface{}) ([]byte, error)
+
+// Render calls the function.
+func (f RenderFunc) Render(ctx context.Context, values map[string]interface{}) ([]byte, error) {
+	return f(ctx, values)
+}
+
+// Reconciler (RenderReconciler) renders a manifest with values computed from the parent through a Renderer, then
+// applies the rendered objects as children of the parent and prunes the children that are no longer rendered, see
+// bundle.Reconciler. It wraps the output of an external rendering engine inside a Maestro pipeline, reporting
+// conditions through the conductor like other reconcilers.
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	// Set the ChildGVKs to the kinds rendered for the conductor to watch them.
+	Details api.Descriptor // required
+	// Renderer renders the manifest.
+	Renderer Renderer // required
+	// ValuesFn returns the values the manifest is rendered with.
+	// If nil, the manifest is rendered with empty values.
+	ValuesFn func(ctx context.Context, parent Parent) (map[string]interface{}, error) // optional
+	// PredicateFn is a function that returns true if the rendered objects should be reconciled.
+	// If nil, they will always be reconciled.
+	PredicateFn func(parent Parent) bool // optional
+	// DryRunType configures the dry-run behavior of the reconciler.
+	DryRunType reconciler.DryRunType // optional
+	// CompareOpts are the options to use when comparing the children to their desired state, such as
+	// reconciler.IgnoreUnstructuredFields.
+	CompareOpts []cmp.Option // optional
+	// LabelsFn returns the labels added to every child, used to find the children to prune.
+	// They must uniquely identify the children of this reconciler for the parent.
+	// Defaults to the maestro.io/owner-uid label set to the parent UID and the maestro.io/reconciler label set to the
+	// descriptor name.
+	LabelsFn func(parent Parent) map[string]string // optional
+	// NoPrune disables deleting the children that are no longer rendered.
+	NoPrune bool // optional
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object]{}
+)
+
+// Reconcile renders the manifest, creates or updates every rendered object, then prunes the children that are no longer
+// rendered.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// Validate returns an ErrInvalidConfiguration error if the Details name or the Renderer is missing.
+func (r *Reconciler[Parent]) Validate() error {
+	var errs []error
+	if r.Details.Name == "" {
+		errs = append(errs, fmt.Errorf("%w: the Details name of the render reconciler is empty", reconciler.ErrInvalidConfiguration))
+	}
+	if r.Renderer == nil {
+		errs = append(errs, fmt.Errorf("%w: %s has no Renderer", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler[Parent]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.Renderer == nil {
+		return reconcile.Result{}, r.Validate()
+	}
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	values := map[string]interface{}{}
+	if r.ValuesFn != nil {
+		var err error
+		if values, err = r.ValuesFn(ctx, parent); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	manifest, err := r.Renderer.Render(ctx, values)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("rendering manifest: %w", err)
+	}
+	desired, err := reconciler.DecodeManifests(manifest)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("decoding rendered manifest: %w", err)
+	}
+
+	applier := &bundle.Reconciler[Parent]{
+		Details:     r.Details,
+		DryRunType:  r.DryRunType,
+		CompareOpts: r.CompareOpts,
+		LabelsFn:    r.LabelsFn,
+		NoPrune:     r.NoPrune,
+	}
+	return applier.Apply(ctx, k8sCli, parent, desired)
+}
@@ -0,0 +1,113 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// renderer renders a ConfigMap per tenant, and a ClusterRole
+var renderer = RenderFunc(func(_ context.Context, values map[string]interface{}) ([]byte, error) {
+	manifest := fmt.Sprintf(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: %s-reader
+rules: []
+`, values["release"])
+	for _, tenant := range values["tenants"].([]string) {
+		manifest += fmt.Sprintf(`
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: tenant-%s
+data:
+  tenant: %s
+`, tenant, tenant)
+	}
+	return []byte(manifest), nil
+})
+
+func TestRenderReconciler(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, rbacv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "default", UID: "parent-uid"}}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion, rbacv1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(parent).Build()
+	tenants := []string{"a", "b"}
+	r := FromRenderer[*corev1.ConfigMap](renderer).
+		WithDetails(api.Descriptor{Name: "Platform"}).
+		WithValuesFn(func(_ context.Context, parent *corev1.ConfigMap) (map[string]interface{}, error) {
+			return map[string]interface{}{"release": parent.Name, "tenants": tenants}, nil
+		}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+	require.NoError(t, r.Validate())
+
+	ctx := context.Background()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		child := &corev1.ConfigMap{}
+		require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, child))
+		assert.True(t, metav1.IsControlledBy(child, parent))
+		assert.Equal(t, "Platform", child.Labels[reconciler.ReconcilerLabel])
+	}
+	// A namespaced parent can't own a cluster-scoped child through an owner reference
+	role := &rbacv1.ClusterRole{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "platform-reader"}, role))
+	assert.Empty(t, role.OwnerReferences)
+	assert.True(t, reconciler.IsOwnedBy(role, parent))
+
+	// Steady state
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// Children no longer rendered are pruned
+	tenants = []string{"a"}
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-b", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "tenant-a", Namespace: "default"}, &corev1.ConfigMap{}))
+}
+
+func TestRenderReconcilerErrors(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	r := FromRenderer[*corev1.ConfigMap](nil).Build()
+	require.ErrorIs(t, r.Validate(), reconciler.ErrInvalidConfiguration)
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+
+	r = FromRenderer[*corev1.ConfigMap](RenderFunc(func(context.Context, map[string]interface{}) ([]byte, error) {
+		return []byte("metadata:\n  name: no-kind\n"), nil
+	})).WithDetails(api.Descriptor{Name: "Platform"}).Build()
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrMissingGVK)
+}
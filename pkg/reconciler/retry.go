@@ -0,0 +1,115 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RetryPolicy retries transient errors in-process, before they bubble up and requeue the whole parent.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values of 1 or less disable retries.
+	MaxAttempts int
+	// Backoff returns the delay before the given retry (starting at 1). Defaults to DefaultBackoff.
+	Backoff func(retry int) time.Duration
+	// Retriable classifies the errors worth retrying. Defaults to IsRetriable.
+	Retriable func(err error) bool
+}
+
+// DefaultBackoff is the backoff used by a RetryPolicy without one.
+var DefaultBackoff = ExponentialBackoff(100*time.Millisecond, 2*time.Second)
+
+// ExponentialBackoff returns a backoff doubling from initial up to max.
+func ExponentialBackoff(initial, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		delay := initial
+		for i := 1; i < retry && delay < max; i++ {
+			delay *= 2
+		}
+		return min(delay, max)
+	}
+}
+
+// IsRetriable reports whether the error is a transient API error: conflicts, timeouts, throttling and server errors.
+func IsRetriable(err error) bool {
+	return apierrors.IsConflict(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsUnexpectedServerError(err)
+}
+
+// Do calls fn until it succeeds, returns an error that is not retriable, or MaxAttempts is reached.
+// It stops early with the last error when the context is done while waiting.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) (reconcile.Result, error)) (reconcile.Result, error) {
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+	retriable := p.Retriable
+	if retriable == nil {
+		retriable = IsRetriable
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := fn(ctx)
+		if err == nil || attempt >= p.MaxAttempts || !retriable(err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// Retrying wraps a reconciler, retrying its transient errors according to the Policy.
+type Retrying[Parent client.Object] struct {
+	Inner  api.Reconciler[Parent]
+	Policy RetryPolicy
+}
+
+var _ api.Reconciler[client.Object] = &Retrying[client.Object]{}
+
+// WithRetry wraps the reconciler with the retry policy.
+func WithRetry[Parent client.Object](inner api.Reconciler[Parent], policy RetryPolicy) *Retrying[Parent] {
+	return &Retrying[Parent]{Inner: inner, Policy: policy}
+}
+
+// Reconcile runs the inner reconciler, retrying it according to the Policy.
+func (r *Retrying[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	return r.Policy.Do(ctx, func(ctx context.Context) (reconcile.Result, error) {
+		return r.Inner.Reconcile(ctx, k8sCli, parent)
+	})
+}
+
+// Describe returns the descriptor of the inner reconciler.
+func (r *Retrying[Parent]) Describe() api.Descriptor {
+	return r.Inner.Describe()
+}
+
+// Children returns the children of the inner reconciler, if it declares any.
+func (r *Retrying[Parent]) Children() []client.Object {
+	if describer, ok := r.Inner.(api.ChildDescriber); ok {
+		return describer.Children()
+	}
+	return nil
+}
+
+// Validate validates the inner reconciler, if it implements api.Validator.
+func (r *Retrying[Parent]) Validate() error {
+	if validator, ok := r.Inner.(api.Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LastRunPrefix is the prefix of the parent annotations holding the time a scheduled reconciler last succeeded,
+// followed by the name of the reconciler.
+const LastRunPrefix = "last-run." + reconciler.AnnotationPrefix
+
+// Reconciler (ScheduledReconciler) wraps another reconciler and only runs it when it is due according to the
+// Schedule, evaluated against the time it last succeeded for the parent.
+type Reconciler[Parent client.Object] struct {
+	// Inner is the reconciler run when due. Its descriptor name keys the last run annotation, so it must be unique
+	// among the scheduled reconcilers of a parent.
+	Inner api.Reconciler[Parent] // required
+	// Schedule returns when the inner reconciler is next due after its last run, see Interval and ParseCron.
+	Schedule Schedule // required
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time // optional
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object]{}
+)
+
+// Reconcile runs the inner reconciler if it never succeeded for the parent or is due since its last run. Once it
+// succeeds without requeueing, the time is recorded on the parent. The parent is then requeued when the inner
+// reconciler is next due; within a conductor, the requeue is requested from the State so the following reconcilers
+// still run (see conductor.RequestResync).
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	name := r.Describe().Name
+	log := klog.FromContext(ctx).V(1).WithValues("parent", client.ObjectKeyFromObject(parent), "reconciler", name)
+	now := r.now()
+
+	lastRun, ok := LastRun(parent, name)
+	if ok {
+		if next := r.Schedule.Next(lastRun); next.IsZero() || now.Before(next) {
+			log.Info("skipping reconciler until it is due", "lastRun", lastRun, "next", next)
+			return r.requeue(ctx, reconcile.Result{}, now, lastRun), nil
+		}
+	}
+
+	result, err := r.Inner.Reconcile(ctx, k8sCli, parent)
+	if err != nil || result.Requeue || result.RequeueAfter > 0 {
+		return result, err
+	}
+
+	before := parent.DeepCopyObject().(Parent)
+	SetLastRun(parent, name, now)
+	if err := k8sCli.Patch(ctx, parent, client.MergeFrom(before)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("unable to record the last run of %s: %w", name, err)
+	}
+	log.Info("recorded last run", "lastRun", now)
+	return r.requeue(ctx, result, now, now), nil
+}
+
+// Describe returns the descriptor of the inner reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Inner.Describe()
+}
+
+// Children returns the children of the inner reconciler, if it declares any.
+func (r *Reconciler[Parent]) Children() []client.Object {
+	if describer, ok := r.Inner.(api.ChildDescriber); ok {
+		return describer.Children()
+	}
+	return nil
+}
+
+// Validate checks that the reconciler is named and scheduled, then validates the inner reconciler if it implements
+// api.Validator.
+func (r *Reconciler[Parent]) Validate() error {
+	if r.Schedule == nil {
+		return fmt.Errorf("%w: a Schedule is required", reconciler.ErrInvalidConfiguration)
+	}
+	if r.Describe().Name == "" {
+		return fmt.Errorf("%w: the Details name of the inner reconciler is required to record its last run",
+			reconciler.ErrInvalidConfiguration)
+	}
+	if validator, ok := r.Inner.(api.Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// requeue returns the result requeued when the inner reconciler is next due after its last run, if ever.
+func (r *Reconciler[Parent]) requeue(ctx context.Context, result reconcile.Result, now, lastRun time.Time) reconcile.Result {
+	next := r.Schedule.Next(lastRun)
+	if next.IsZero() {
+		return result
+	}
+	// Requeue a second late, so the parent isn't requeued just before the reconciler is due.
+	after := next.Sub(now) + time.Second
+	if !conductor.RequestResync(ctx, after) {
+		result.RequeueAfter = after
+	}
+	return result
+}
+
+func (r *Reconciler[Parent]) now() time.Time {
+	if r.Now == nil {
+		return time.Now()
+	}
+	return r.Now()
+}
+
+// LastRun returns the time the named reconciler last succeeded, as recorded on the parent.
+func LastRun(parent client.Object, name string) (time.Time, bool) {
+	value, ok := parent.GetAnnotations()[LastRunPrefix+name]
+	if !ok {
+		return time.Time{}, false
+	}
+	lastRun, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return lastRun, true
+}
+
+// SetLastRun records on the parent the time the named reconciler last succeeded.
+func SetLastRun(parent client.Object, name string, lastRun time.Time) {
+	annotations := parent.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastRunPrefix+name] = lastRun.UTC().Format(time.RFC3339)
+	parent.SetAnnotations(annotations)
+}
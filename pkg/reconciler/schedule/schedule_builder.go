@@ -0,0 +1,39 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// On returns a new instance of Builder that runs the inner reconciler according to the schedule.
+func On[Parent client.Object](inner api.Reconciler[Parent], schedule Schedule) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			Inner:    inner,
+			Schedule: schedule,
+		},
+	}
+}
+
+// Every returns a new instance of Builder that runs the inner reconciler once per interval.
+func Every[Parent client.Object](inner api.Reconciler[Parent], interval time.Duration) *Builder[Parent] {
+	return On(inner, Interval(interval))
+}
+
+// WithNow sets the Now field.
+func (b *Builder[Parent]) WithNow(now func() time.Time) *Builder[Parent] {
+	b.reconciler.Now = now
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
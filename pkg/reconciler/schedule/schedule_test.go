@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type countingReconciler struct {
+	calls  int
+	result reconcile.Result
+}
+
+func (c *countingReconciler) Describe() api.Descriptor {
+	return api.Descriptor{Name: "Rotation"}
+}
+
+func (c *countingReconciler) Reconcile(context.Context, client.Client, *corev1.ConfigMap) (reconcile.Result, error) {
+	c.calls++
+	return c.result, nil
+}
+
+func TestParseCron(t *testing.T) {
+	after := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC) // a Wednesday
+
+	for expr, next := range map[string]time.Time{
+		"* * * * *":          time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC),
+		"*/15 * * * *":       time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC),
+		"0 3 * * *":          time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC),
+		"30 9 * * 1-5":       time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC),
+		"0 0 * * 7":          time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":         time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 15 * 1":         time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC),
+		"5,10 10-11/1 * * *": time.Date(2024, time.January, 31, 11, 5, 0, 0, time.UTC),
+		"@monthly":           time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"@every 90m":         time.Date(2024, time.January, 31, 11, 47, 30, 0, time.UTC),
+	} {
+		schedule, err := ParseCron(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, next, schedule.Next(after), expr)
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every -1m"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.True(t, MustParseCron("0 0 30 2 *").Next(after).IsZero())
+}
+
+func TestSchedule(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+
+	now := time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)
+	inner := &countingReconciler{}
+	scheduled := Every(inner, time.Hour).WithNow(func() time.Time { return now }).Build()
+	require.NoError(t, scheduled.Validate())
+
+	// The first run is recorded on the parent
+	result, err := scheduled.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.calls)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Hour + time.Second}, result)
+	require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(parent), parent))
+	lastRun, ok := LastRun(parent, "Rotation")
+	require.True(t, ok)
+	assert.Equal(t, now, lastRun)
+
+	// The inner reconciler is skipped until it is due
+	now = now.Add(20 * time.Minute)
+	result, err = scheduled.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.calls)
+	assert.Equal(t, reconcile.Result{RequeueAfter: 40*time.Minute + time.Second}, result)
+
+	// Within a conductor, the requeue is requested from the State
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err = scheduled.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, 40*time.Minute+time.Second, state.Resync())
+
+	// A requeueing run isn't recorded
+	now = now.Add(time.Hour)
+	inner.result = reconcile.Result{Requeue: true}
+	result, err = scheduled.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.calls)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+	lastRun, _ = LastRun(parent, "Rotation")
+	assert.Equal(t, now.Add(-80*time.Minute), lastRun)
+
+	// Invalid configurations are rejected
+	assert.ErrorIs(t, On[*corev1.ConfigMap](inner, nil).Build().Validate(), reconciler.ErrInvalidConfiguration)
+}
@@ -0,0 +1,44 @@
+package simple
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// addNamespaceTerminatingCondition adds a `<Name>NamespaceTerminating` condition to the State, if any.
+func (r *Reconciler[Parent, Child]) addNamespaceTerminatingCondition(ctx context.Context, namespace string) {
+	state, err := conductor.FetchState(ctx)
+	if err != nil {
+		return
+	}
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sNamespaceTerminating", r.Details.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "NamespaceTerminating",
+		Message: fmt.Sprintf("the child can't be created while namespace %s is terminating", namespace),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
+
+// addBlockedCondition adds a `<Name>Blocked` condition to the State, if any, with the message of the admission denial.
+func (r *Reconciler[Parent, Child]) addBlockedCondition(ctx context.Context, err error) {
+	state, stateErr := conductor.FetchState(ctx)
+	if stateErr != nil {
+		return
+	}
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sBlocked", r.Details.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "AdmissionDenied",
+		Message: err.Error(),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package simple
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ChecksumAnnotationPrefix is the prefix of the pod template annotations holding config checksums.
+const ChecksumAnnotationPrefix = reconciler.AnnotationPrefix + "checksum-"
+
+// ChecksumSource identifies a ConfigMap or Secret whose contents are hashed into a pod template annotation, so
+// changes to it roll the pods of the workload child.
+type ChecksumSource[Parent client.Object] struct {
+	// Name is the suffix of the annotation (maestro.io/checksum-<Name>).
+	// When KeyFn is nil, the contents of the conductor Reference with the same name are hashed instead.
+	Name string // required
+	// Kind is the kind of object to hash.
+	Kind conductor.ReferenceKind // required with KeyFn
+	// KeyFn returns the key of the object to hash, typically another child of the same parent.
+	KeyFn func(Parent) client.ObjectKey // optional
+}
+
+// injectChecksums annotates the pod template of the desired workload with the checksums of the sources.
+// Sources that don't exist yet are skipped; the annotation is added once they do.
+func (r *Reconciler[Parent, Child]) injectChecksums(ctx context.Context, k8sCli client.Client, parent Parent, desired Child) error {
+	if !reconciler.HasPodTemplate(desired) {
+		return nil
+	}
+
+	for _, source := range r.ChecksumSources {
+		data, found, err := checksumData(ctx, k8sCli, parent, source)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		reconciler.SetPodTemplateAnnotation(desired, ChecksumAnnotationPrefix+source.Name, reconciler.HashData(data))
+	}
+	return nil
+}
+
+func checksumData[Parent client.Object](ctx context.Context, k8sCli client.Client, parent Parent, source ChecksumSource[Parent]) (map[string]string, bool, error) {
+	if source.KeyFn == nil {
+		references, err := conductor.FetchReferences(ctx)
+		if err != nil {
+			return nil, false, nil
+		}
+		data := references.Data(source.Name)
+		return data, data != nil, nil
+	}
+
+	data, err := conductor.LoadReferenceData(ctx, k8sCli, source.Kind, source.KeyFn(parent))
+	if errors.Is(err, conductor.ErrMissingReference) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
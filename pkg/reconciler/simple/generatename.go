@@ -0,0 +1,58 @@
+package simple
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolveGeneratedName returns whether the child uses a name generated by the API server: it has a generateName, and
+// either no name or the one recorded on the parent. A child without a name is given the recorded name, if any.
+func (r *Reconciler[Parent, Child]) resolveGeneratedName(parent Parent, child Child) bool {
+	if child.GetGenerateName() == "" {
+		return false
+	}
+	recorded, ok := reconciler.GeneratedName(parent, r.Details.Name)
+	switch {
+	case child.GetName() == "":
+		if ok {
+			child.SetName(recorded)
+		}
+		return true
+	case ok && child.GetName() == recorded:
+		return true
+	}
+	return false
+}
+
+// getCurrent reads the current child. A child with a generated name that wasn't created yet is not found.
+func (r *Reconciler[Parent, Child]) getCurrent(ctx context.Context, k8sCli client.Client, current Child, generated bool) error {
+	if generated && current.GetName() == "" {
+		gvk := current.GetObjectKind().GroupVersionKind()
+		return apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, current.GetGenerateName())
+	}
+	return r.reader(ctx, k8sCli).Get(ctx, client.ObjectKeyFromObject(current), current)
+}
+
+// recordGeneratedName records the name generated for the created child on the parent, so the child is read, updated
+// and deleted by name afterward. If the parent can't be updated, the child is deleted so it isn't orphaned.
+func (r *Reconciler[Parent, Child]) recordGeneratedName(ctx context.Context, k8sCli client.Client, log klog.Logger, parent Parent, created Child) error {
+	if r.Details.Name == "" {
+		return fmt.Errorf("%w: the Details name is required to record generated names", reconciler.ErrInvalidConfiguration)
+	}
+	before := parent.DeepCopyObject().(Parent)
+	reconciler.SetGeneratedName(parent, r.Details.Name, created.GetName())
+	if err := k8sCli.Patch(ctx, parent, client.MergeFrom(before)); err != nil {
+		if err := k8sCli.Delete(ctx, created); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to delete child whose generated name wasn't recorded")
+		}
+		return err
+	}
+	log.Info("recorded generated name", "name", created.GetName())
+	return nil
+}
@@ -0,0 +1,22 @@
+package simple
+
+import (
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// logNoChanges logs a message about an up to date child, sampled by the LogSampler if any.
+func (r *Reconciler[Parent, Child]) logNoChanges(log klog.Logger, child client.Object, msg string, keysAndValues ...any) {
+	if r.LogSampler == nil {
+		log.Info(msg, keysAndValues...)
+		return
+	}
+	r.LogSampler.Info(log, child, msg, keysAndValues...)
+}
+
+// resetLogSampler resets the LogSampler for the child, if any, as it changed.
+func (r *Reconciler[Parent, Child]) resetLogSampler(child client.Object) {
+	if r.LogSampler != nil {
+		r.LogSampler.Reset(child)
+	}
+}
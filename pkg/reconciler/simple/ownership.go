@@ -0,0 +1,130 @@
+package simple
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// markOwned marks the desired child as owned by the parent following the Ownership mode, and returns whether it has a
+// controller reference.
+func (r *Reconciler[Parent, Child]) markOwned(k8sCli client.Client, parent Parent, desired Child) (bool, error) {
+	if r.NoReference {
+		return false, nil
+	}
+	var reference bool
+	switch r.Ownership {
+	case reconciler.OwnershipLabels:
+		reference = false
+	case reconciler.OwnershipAuto:
+		var err error
+		if reference, err = reconciler.CanReference(k8sCli, parent, desired); err != nil {
+			return false, err
+		}
+	default:
+		// A child without a namespace is cluster-scoped, and can't have a namespaced parent as owner.
+		reference = parent.GetNamespace() == "" || desired.GetNamespace() != ""
+	}
+	if !reference {
+		return false, reconciler.MarkOwned(parent, desired, k8sCli.Scheme())
+	}
+	return true, controllerutil.SetControllerReference(parent, desired, k8sCli.Scheme())
+}
+
+// checkOwnership decides whether an existing child that isn't controlled by the parent can be taken over, following
+// the ForeignOwnerPolicy for children controlled by another owner, and AdoptOrphans for the others. When taken over,
+// the owner references of the current child are kept on the desired one.
+func (r *Reconciler[Parent, Child]) checkOwnership(ctx context.Context, log klog.Logger, current, desired Child) (bool, error) {
+	key := client.ObjectKeyFromObject(current)
+	if owner := metav1.GetControllerOf(current); owner != nil {
+		switch r.ForeignOwnerPolicy {
+		case reconciler.ForeignOwnerAdopt:
+			log.Info("adopting child from foreign owner", "owner", owner.Name, "ownerKind", owner.Kind)
+			r.takeOver(current, desired)
+			return true, nil
+		case reconciler.ForeignOwnerSkip:
+			log.Info("skipping child controlled by foreign owner", "owner", owner.Name, "ownerKind", owner.Kind)
+			r.addForeignOwnerCondition(ctx, key, owner)
+			return false, nil
+		default:
+			r.addForeignOwnerCondition(ctx, key, owner)
+			return false, fmt.Errorf("%w: %s is controlled by %s %s", reconciler.ErrForeignOwner, key, owner.Kind, owner.Name)
+		}
+	}
+
+	return r.adoptOrphan(log, current, desired)
+}
+
+// adoptOrphan takes over an existing child without an owner if AdoptOrphans is set and it matches the AdoptSelector,
+// and returns an ErrNotOwned error otherwise.
+func (r *Reconciler[Parent, Child]) adoptOrphan(log klog.Logger, current, desired Child) (bool, error) {
+	key := client.ObjectKeyFromObject(current)
+	if !r.AdoptOrphans {
+		return false, fmt.Errorf("%w: %s already exists without an owner, set AdoptOrphans to take it over", reconciler.ErrNotOwned, key)
+	}
+	if r.AdoptSelector != nil && !r.AdoptSelector.Matches(labels.Set(current.GetLabels())) {
+		return false, fmt.Errorf("%w: %s does not match the adoption selector", reconciler.ErrNotOwned, key)
+	}
+	log.Info("adopting orphaned child")
+	r.takeOver(current, desired)
+	return true, nil
+}
+
+// takeOver keeps the owner references of the current child on the desired one, demoting any other controller to a
+// regular owner, and records the adoption as an annotation.
+func (r *Reconciler[Parent, Child]) takeOver(current, desired Child) {
+	references := desired.GetOwnerReferences()
+	for _, reference := range current.GetOwnerReferences() {
+		if hasOwnerReference(references, reference.UID) {
+			continue
+		}
+		if reference.Controller != nil && *reference.Controller {
+			controller := false
+			reference.Controller = &controller
+		}
+		references = append(references, reference)
+	}
+	desired.SetOwnerReferences(references)
+
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[reconciler.AdoptedAnnotation] = "true"
+	desired.SetAnnotations(annotations)
+}
+
+// addForeignOwnerCondition adds a `<name>ForeignOwner` condition to the State, if any.
+func (r *Reconciler[Parent, Child]) addForeignOwnerCondition(ctx context.Context, key client.ObjectKey, owner *metav1.OwnerReference) {
+	state, err := conductor.FetchState(ctx)
+	if err != nil {
+		return
+	}
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sForeignOwner", r.Details.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "ControlledByForeignOwner",
+		Message: fmt.Sprintf("%s is controlled by %s %s", key, owner.Kind, owner.Name),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
+
+func hasOwnerReference(references []metav1.OwnerReference, uid types.UID) bool {
+	for _, reference := range references {
+		if reference.UID == uid {
+			return true
+		}
+	}
+	return false
+}
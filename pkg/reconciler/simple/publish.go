@@ -0,0 +1,55 @@
+package simple
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PublishChild returns a PublishFn publishing a copy of the child under the key. Without a conductor State, nothing
+// is published.
+func PublishChild[Parent client.Object, Child client.Object](key conductor.Key[Child]) func(context.Context, Parent, Child) error {
+	return func(ctx context.Context, _ Parent, child Child) error {
+		return setOutput(ctx, key, child.DeepCopyObject().(Child))
+	}
+}
+
+// PublishField returns a PublishFn publishing the value selected from the child under the key, e.g. the address of
+// the load balancer of a Service. Without a conductor State, nothing is published.
+func PublishField[Parent client.Object, Child client.Object, T any](key conductor.Key[T], fieldFn func(child Child) T) func(context.Context, Parent, Child) error {
+	return func(ctx context.Context, _ Parent, child Child) error {
+		return setOutput(ctx, key, fieldFn(child))
+	}
+}
+
+// setOutput publishes the output if a conductor State is bound to the context.
+func setOutput[T any](ctx context.Context, key conductor.Key[T], value T) error {
+	if _, err := conductor.FetchState(ctx); err != nil {
+		return nil
+	}
+	return conductor.SetOutput(ctx, key, value)
+}
+
+// Contract returns the Outputs of the reconciler, so the conductor checks that they are produced before the
+// reconcilers consuming them.
+func (r *Reconciler[Parent, Child]) Contract() conductor.Contract {
+	return conductor.Contract{Outputs: r.Outputs}
+}
+
+// publishCurrent publishes the child skipped as unchanged with the PublishFn, if any. Metadata reads don't return
+// the status of the child, so it is then read whole.
+func (r *Reconciler[Parent, Child]) publishCurrent(ctx context.Context, k8sCli client.Client, parent Parent, current client.Object) error {
+	if r.PublishFn == nil {
+		return nil
+	}
+	child, ok := current.(Child)
+	if !ok {
+		child = r.ChildKeyFn(parent)
+		r.resolveGeneratedName(parent, child)
+		if err := r.reader(ctx, k8sCli).Get(ctx, client.ObjectKeyFromObject(child), child); err != nil {
+			return err
+		}
+	}
+	return r.PublishFn(ctx, parent, child)
+}
@@ -2,7 +2,9 @@ package simple
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/ethan-gallant/maestro/api"
@@ -11,9 +13,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -30,6 +37,10 @@ type Reconciler[Parent client.Object, Child client.Object] struct {
 	PredicateFn func(parent Parent) bool // optional
 	// NoReference optionally disables setting the owner reference on the child object.
 	NoReference bool // optional
+	// Ownership configures how the child is marked as owned by the parent, unless NoReference is set. Use
+	// reconciler.OwnershipLabels or reconciler.OwnershipAuto for children in other namespaces than the parent, which
+	// can't have an owner reference. Defaults to reconciler.OwnershipReference.
+	Ownership reconciler.OwnershipMode // optional
 	// DryRunType configures the dry-run behavior of the reconciler.
 	DryRunType reconciler.DryRunType // optional
 	// CompareOpts are the options to use when comparing the child object to the desired state.
@@ -41,47 +52,264 @@ type Reconciler[Parent client.Object, Child client.Object] struct {
 	// ChildKeyFn returns the child object with only a key (name and namespace) set.
 	// It must always match the key the ReconcileFn returns. Otherwise, Reconcile calls will fail.
 	// All other fields should be empty and will be ignored.
-	ChildKeyFn func(Parent) Child // required if ShouldDeleteFn is set
+	ChildKeyFn func(Parent) Child // required if ShouldDeleteFn is set, unless the DeleteMode is reconciler.DeleteCollection
+	// DeleteMode configures how the children are deleted when the ShouldDeleteFn returns true. With
+	// reconciler.DeleteCollection, every child of the type matching the DeleteLabelsFn labels, in the namespace of the
+	// parent for namespaced types, is deleted with a single DeleteAllOf call; the Child must then be a typed object.
+	// Defaults to reconciler.DeleteSingle.
+	DeleteMode reconciler.DeleteMode // optional
+	// ForegroundDeletion deletes the children with the foreground propagation policy when the ShouldDeleteFn returns
+	// true, so they are only gone once their dependents are (e.g. the pods of a Job). The parent is then requeued after
+	// the DeletionRequeueAfter delay, without calling the ReconcileFn, until the children are actually gone.
+	ForegroundDeletion bool // optional
+	// DeletionRequeueAfter is the delay to check again whether the children deleted with ForegroundDeletion are gone.
+	// Defaults to DefaultDeletionRequeueAfter.
+	DeletionRequeueAfter time.Duration // optional
+	// DeleteOptions are the options of the deletions triggered by the ShouldDeleteFn, e.g. client.GracePeriodSeconds or
+	// client.PropagationPolicy. Deletions of a single child always have a UID precondition on the child that was read,
+	// so a child recreated by another actor in the meantime is not deleted.
+	DeleteOptions []client.DeleteOption // optional
+	// DeleteLabelsFn returns the labels selecting the children deleted with reconciler.DeleteCollection.
+	// Defaults to the labels of reconciler.NewApplySet for the descriptor name, which are then added to the child.
+	DeleteLabelsFn func(Parent) map[string]string // optional
 	// PreUpdateFn is a function that is called before the child object is applied.
 	// This function is not called for the first creation of the child object.
 	PreUpdateFn func(ctx context.Context, parent Parent, previous, child Child) error // optional
+	// RestartTriggerFn returns the revision of what triggers a rollout restart of the child workload, e.g. the
+	// resourceVersion of a referenced Secret. When it changes, the pod template is annotated to restart the workload
+	// once; the revision is recorded on the child (see reconciler.RestartOnChange), and the first one seen doesn't
+	// restart it. An empty revision keeps the recorded one.
+	// When set, the restart annotation of the current object is kept, so restarts are not reverted on the next reconcile.
+	// The child must have a pod template (see reconciler.HasPodTemplate): a typed workload or an unstructured object.
+	RestartTriggerFn func(ctx context.Context, parent Parent, current Child) (string, error) // optional
+	// ChecksumSources are ConfigMaps or Secrets whose checksums are added as pod template annotations of the child
+	// workload, so that changes to them roll the pods. The child must have a pod template, as for the RestartTriggerFn.
+	ChecksumSources []ChecksumSource[Parent] // optional
+	// UpdateStrategy configures how an out of date child is updated. Patch strategies only send the fields set on the
+	// object returned by the ReconcileFn, preserving fields written by mutating webhooks or other controllers.
+	UpdateStrategy reconciler.UpdateStrategy // optional
+	// AdoptOrphans takes over the existing children without an owner, e.g. when migrating pre-existing resources under
+	// operator control: they are adopted (and annotated with reconciler.AdoptedAnnotation) if they match the
+	// AdoptSelector. When unset, or for orphans not matching the AdoptSelector, they fail with reconciler.ErrNotOwned
+	// and are left untouched.
+	AdoptOrphans bool // optional
+	// AdoptSelector restricts the orphans adopted with AdoptOrphans to those matching the selector. Orphans not
+	// matching it fail with reconciler.ErrNotOwned.
+	AdoptSelector labels.Selector // optional
+	// ForeignOwnerPolicy configures what happens when an existing child is controlled by another owner.
+	// Defaults to reconciler.ForeignOwnerError.
+	ForeignOwnerPolicy reconciler.ForeignOwnerPolicy // optional
+	// PreserveFields copy fields populated by the API server or other controllers from the current onto the desired
+	// child before they are compared, so updates don't wipe them. See the reconciler.Preserve* presets, e.g.
+	// reconciler.PreserveServiceClusterIP.
+	PreserveFields []func(current, desired Child) // optional
+	// RecreateOnImmutableChange deletes the child so it is created again on the next reconcile when it can't be updated
+	// in place: when the IsImmutableChangeFn returns true, or when the API rejects the update because of immutable
+	// fields (see reconciler.IsImmutableFieldError), e.g. a Job template or a PVC storage class.
+	// The child is deleted with background propagation, so its dependents are garbage collected.
+	RecreateOnImmutableChange bool // optional
+	// IsImmutableChangeFn returns true if going from the current to the desired child requires a recreation.
+	// It is only called if RecreateOnImmutableChange is set, and saves a rejected update for known cases.
+	IsImmutableChangeFn func(current, desired Child) bool // optional
+	// SensitiveFields are the dot-separated paths of fields redacted from the logged diffs, e.g.
+	// "spec.credentials.password". The data of Secrets is always redacted.
+	SensitiveFields []string // optional
+	// ChangeDetection configures how an out of date child is detected. With reconciler.ChangeDetectionHash, a hash of
+	// the desired object is stored in an annotation of the child, which is only updated when the hash changes,
+	// without dry-runs. Defaults to reconciler.ChangeDetectionCompare.
+	ChangeDetection reconciler.ChangeDetection // optional
+	// RetryPolicy retries transient errors in-process before they are returned and requeue the parent.
+	RetryPolicy *reconciler.RetryPolicy // optional
+	// MinUpdateInterval rate limits the updates of the child, to avoid fighting with other controllers in a hot loop.
+	// The time of the last update is kept in the reconciler.LastUpdatedAnnotation of the child; more frequent updates
+	// are postponed and a `<Name>Throttled` condition is added to the conductor State.
+	MinUpdateInterval time.Duration // optional
+	// ResyncInterval requeues the parent after the interval once the child is up to date, to detect its drift more
+	// often than the resync period of the controller (see conductor.RequestResync).
+	ResyncInterval time.Duration // optional
+	// NextSyncFn returns the delay after which the parent should be reconciled again, given the up to date child, e.g.
+	// shortly before a certificate it holds expires or a token needs refreshing. Non-positive delays are ignored. Within
+	// a conductor, the delay is requested from the State like the ResyncInterval, so the smallest one wins.
+	NextSyncFn func(parent Parent, child Child) time.Duration // optional
+	// PublishFn publishes the child observed after a successful reconcile (or selected fields of its status) in the
+	// conductor State, e.g. with conductor.SetOutput, so later reconcilers consume it without reading the cluster again.
+	// See PublishChild and PublishField.
+	PublishFn func(ctx context.Context, parent Parent, child Child) error // optional
+	// Outputs are the values published by the PublishFn, declared in the Contract of the reconciler.
+	Outputs []conductor.Port // optional
+	// Timeout bounds the duration of a reconcile, retries included. When exceeded, the parent is requeued with the
+	// backoff of the controller and a `<Name>TimedOut` condition is added to the conductor State.
+	Timeout time.Duration // optional
+	// FlapDetector stops updating the child when the same update is applied repeatedly, typically because another
+	// controller reverts it. A `<Name>Conflicting` condition is added to the conductor State and the conflicting fields
+	// are logged. The detector keeps its history in memory and should be shared across reconciles.
+	FlapDetector *reconciler.FlapDetector // optional
+	// DefaultingCache remembers the dry-runs showing that the child only differs from the desired one by the defaults
+	// of the API server, so the comparison and the dry-runs are skipped on the next reconciles while neither changes,
+	// until the TTL of the cache. The cache should be shared across reconciles.
+	DefaultingCache *reconciler.DefaultingCache // optional
+	// DiffRenderer renders the changes logged when the child is updated, e.g. reconciler.YAMLDiffRenderer for a
+	// kubectl diff style output. When unset, updates are logged without a diff and dry-run warnings use cmp.Diff.
+	DiffRenderer reconciler.DiffRenderer // optional
+	// SkipUnchanged skips the ReconcileFn when the current child was applied for the same generation of the parent and
+	// the same InputsHashFn hash, recorded in the reconciler.ParentGenerationAnnotation and
+	// reconciler.InputsHashAnnotation of the child. Changes made to the child by others are then not reverted until the
+	// parent changes. It requires a ChildKeyFn.
+	SkipUnchanged bool // optional
+	// InputsHashFn returns a hash of the inputs of the ReconcileFn besides the spec of the parent (e.g. the labels or
+	// annotations of the parent, referenced objects, external data), which are not covered by the generation.
+	InputsHashFn func(ctx context.Context, parent Parent) (string, error) // optional
+	// LiveReads reads the current child with the uncached reader of the conductor (see conductor.APIReader) instead
+	// of the client, so decisions aren't made on a stale cache right after the child was created or updated. It has
+	// no effect without a reader.
+	LiveReads bool // optional
+	// APIReader is the uncached reader of the current child, e.g. the one of manager.GetAPIReader. It takes
+	// precedence over the reader of the conductor and doesn't require LiveReads.
+	APIReader client.Reader // optional
+	// NewChildFn returns an empty Child, which the current child is read into instead of a copy of the desired one.
+	// Defaults to a new zero value of the type of the desired child, which suits typed and unstructured objects.
+	NewChildFn func() Child // optional
+	// ConflictBackoff bounds the retries of an update rejected because the child changed since it was read (a 409
+	// Conflict). The current child is read again and the update rebuilt from the desired object, PreUpdateFn included,
+	// without a requeue. Defaults to retry.DefaultRetry; set Steps to 1 to requeue on the first conflict.
+	ConflictBackoff *wait.Backoff // optional
+	// MetadataReads reads only the metadata of the child (as a metav1.PartialObjectMetadata) to check whether it
+	// exists or is being deleted, when the ShouldDeleteFn returns true and on teardown, saving the memory and decoding
+	// time of large children. With a cached client, the metadata is served by a separate metadata-only informer: set it
+	// along with LiveReads or an APIReader, or when the child type isn't otherwise cached.
+	MetadataReads bool // optional
+	// FieldManager is the field manager of the writes of the reconciler, so the managed fields and the audit logs
+	// attribute the changes of the child to it rather than to the controller as a whole.
+	FieldManager string // optional
+	// Impersonator makes the reconciler send its requests as another user, e.g. a service account with the
+	// permissions of the reconciler only (see reconciler.NewServiceAccountImpersonator). It requires
+	// reconciler.EnableImpersonation on the configuration of the manager; the reads served by the cache are not
+	// impersonated.
+	Impersonator *reconciler.Impersonator // optional
+	// SkipTerminatingNamespace skips the creation of the child while the namespace of the parent is terminating, with
+	// a `<Name>NamespaceTerminating` condition added to the conductor State, instead of attempting it on every
+	// reconcile. It reads the Namespace of the parent, which requires the permission to get namespaces.
+	// Regardless, a creation rejected because the namespace of the child is terminating adds the condition and
+	// requeues the parent with the backoff of the controller, without returning an error.
+	SkipTerminatingNamespace bool // optional
+	// LogSampler samples the "no changes" messages logged on every reconcile of an up to date child, which otherwise
+	// dominate the logs of controllers with many parents. The sampler should be shared across reconciles.
+	LogSampler *reconciler.LogSampler // optional
 }
 
-var _ api.Reconciler[client.Object] = &Reconciler[client.Object, client.Object]{}
+// DefaultDeletionRequeueAfter is the delay used to requeue the parent while children deleted with ForegroundDeletion
+// are not gone yet.
+const DefaultDeletionRequeueAfter = 5 * time.Second
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object, client.Object]{}
+	_ api.ChildDescriber            = &Reconciler[client.Object, client.Object]{}
+	_ api.Cleaner[client.Object]    = &Reconciler[client.Object, client.Object]{}
+)
 
 // Reconcile method for SimpleReconciler calls the embedded ChildReconciler's Reconcile method and handles the child object.
 func (r *Reconciler[Parent, Child]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
-	state, err := conductor.FetchState(ctx)
-	if err != nil { // With no state / conductor, do a normal reconcile
+	if err := r.validateFuncs(); err != nil {
+		return reconcile.Result{}, err
+	}
+	ctx, k8sCli = r.client(ctx, k8sCli)
+	result, err := conductor.RunWithTimeout(ctx, r.Details.Name, r.Timeout, func(ctx context.Context) (reconcile.Result, error) {
+		if r.RetryPolicy != nil {
+			return r.RetryPolicy.Do(ctx, func(ctx context.Context) (reconcile.Result, error) {
+				return r.doReconcile(ctx, k8sCli, parent)
+			})
+		}
 		return r.doReconcile(ctx, k8sCli, parent)
+	})
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	if err == nil && r.ResyncInterval > 0 {
+		result = requestSync(ctx, result, r.ResyncInterval)
 	}
+	return result, err
+}
 
-	result, err := r.doReconcile(ctx, k8sCli, parent)
-	if err != nil {
-		state.AddCondition(metav1.Condition{
-			Type:    fmt.Sprintf("%sError", r.Details.Name),
-			Status:  metav1.ConditionTrue,
-			Reason:  "ReconcileError",
-			Message: err.Error(),
-			LastTransitionTime: metav1.Time{
-				Time: time.Now(),
-			},
-		})
-
-		return result, err
-	}
-
-	state.AddCondition(metav1.Condition{
-		Type:    fmt.Sprintf("%sReconciled", r.Details.Name),
-		Status:  conditionFromResult(result),
-		Reason:  "Reconciled",
-		Message: "Reconciled successfully",
-		LastTransitionTime: metav1.Time{
-			Time: time.Now(),
-		},
-	})
+// requestSync requests the parent to be reconciled again after the delay, through the conductor State or else the
+// result, unless the result already requeues sooner.
+func requestSync(ctx context.Context, result reconcile.Result, after time.Duration) reconcile.Result {
+	if after <= 0 || result.Requeue || (result.RequeueAfter > 0 && result.RequeueAfter <= after) {
+		return result
+	}
+	if !conductor.RequestResync(ctx, after) {
+		result.RequeueAfter = after
+	}
+	return result
+}
+
+// Validate returns an ErrInvalidConfiguration error for each required field left unset: the Details name, the
+// ReconcileFn, and the ChildKeyFn when a ShouldDeleteFn is set outside of reconciler.DeleteCollection or SkipUnchanged
+// is set, for a RestartTriggerFn or ChecksumSources on a child without a pod template, and for an Impersonator without
+// reconciler.EnableImpersonation. Reconcile fails on the missing functions instead of panicking.
+func (r *Reconciler[Parent, Child]) Validate() error {
+	var err error
+	if r.Details.Name == "" {
+		err = fmt.Errorf("%w: the Details name of the simple reconciler is empty", reconciler.ErrInvalidConfiguration)
+	}
+	return errors.Join(err, r.validatePodTemplate(), r.validateFuncs())
+}
+
+// validatePodTemplate returns an ErrInvalidConfiguration error if the RestartTriggerFn or ChecksumSources are set for
+// a typed child without a pod template, whose pod template annotations could never be set. Unstructured children are
+// checked for a spec.template field when reconciled.
+func (r *Reconciler[Parent, Child]) validatePodTemplate() error {
+	if r.RestartTriggerFn == nil && len(r.ChecksumSources) == 0 {
+		return nil
+	}
+	childType := reflect.TypeFor[Child]()
+	if childType.Kind() != reflect.Pointer {
+		return nil
+	}
+	child := reflect.New(childType.Elem()).Interface().(Child)
+	if _, ok := any(child).(*unstructured.Unstructured); ok || reconciler.HasPodTemplate(child) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s restarts or checksums the pods of a %s, which has no pod template",
+		reconciler.ErrInvalidConfiguration, r.Details.Name, childType.Elem().Name())
+}
+
+// validateFuncs returns an ErrInvalidConfiguration error for each required function left unset.
+func (r *Reconciler[Parent, Child]) validateFuncs() error {
+	var errs []error
+	if r.ReconcileFn == nil {
+		errs = append(errs, fmt.Errorf("%w: %s has no ReconcileFn", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	if r.ShouldDeleteFn != nil && r.ChildKeyFn == nil && r.DeleteMode != reconciler.DeleteCollection {
+		errs = append(errs, fmt.Errorf("%w: %s has a ShouldDeleteFn but no ChildKeyFn", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	if r.SkipUnchanged && r.ChildKeyFn == nil {
+		errs = append(errs, fmt.Errorf("%w: %s skips unchanged parents but has no ChildKeyFn", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	if r.Impersonator != nil {
+		errs = append(errs, r.Impersonator.Validate())
+	}
+	return errors.Join(errs...)
+}
+
+// client returns the context and the client of the reconciler: ctx impersonating the user of the Impersonator, if
+// any, and k8sCli setting the FieldManager, if any.
+func (r *Reconciler[Parent, Child]) client(ctx context.Context, k8sCli client.Client) (context.Context, client.Client) {
+	if r.Impersonator != nil {
+		ctx = r.Impersonator.Impersonate(ctx)
+	}
+	return ctx, reconciler.WithFieldManager(k8sCli, r.FieldManager)
+}
 
-	return result, nil
+// reader returns the reader of the current child: the APIReader, or the reader of the conductor with LiveReads, or
+// k8sCli.
+func (r *Reconciler[Parent, Child]) reader(ctx context.Context, k8sCli client.Client) client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	if r.LiveReads {
+		if reader, ok := conductor.APIReader(ctx); ok {
+			return reader
+		}
+	}
+	return k8sCli
 }
 
 // Describe returns the descriptor for the reconciler.
@@ -89,30 +317,75 @@ func (r *Reconciler[Parent, Child]) Describe() api.Descriptor {
 	return r.Details
 }
 
-func conditionFromResult(result reconcile.Result) metav1.ConditionStatus {
-	if result.Requeue || result.RequeueAfter > 0 {
-		return metav1.ConditionFalse
+// Conditions returns the condition Types the reconciler can add to the conductor State, depending on its configuration.
+func (r *Reconciler[Parent, Child]) Conditions() []string {
+	name := r.Details.Name
+	conditions := []string{name + "Reconciled", name + "Error"}
+	if r.ForeignOwnerPolicy != reconciler.ForeignOwnerAdopt {
+		conditions = append(conditions, name+"ForeignOwner")
 	}
-	return metav1.ConditionTrue
+	if r.FlapDetector != nil {
+		conditions = append(conditions, name+"Conflicting")
+	}
+	if r.MinUpdateInterval > 0 {
+		conditions = append(conditions, name+"Throttled")
+	}
+	return append(conditions, name+"NamespaceTerminating", name+"Blocked")
+}
+
+// Children returns the type of the child, unless NoReference is set or the Ownership is reconciler.OwnershipLabels as
+// the child is then not owned by the parent through an owner reference.
+// Unstructured children are not returned, their kinds should be set in the ChildGVKs of the Details.
+func (r *Reconciler[Parent, Child]) Children() []client.Object {
+	if r.NoReference || r.Ownership == reconciler.OwnershipLabels {
+		return nil
+	}
+	child := reconciler.NewObject[Child]()
+	if _, ok := any(child).(runtime.Unstructured); ok {
+		// The kind of unstructured children is unknown until they are built, see api.Descriptor.ChildGVKs.
+		return nil
+	}
+	return []client.Object{child}
+}
+
+// ChildTypes returns the type of the child, owned or not. Unstructured children, and children whose type is an
+// interface, are not returned.
+func (r *Reconciler[Parent, Child]) ChildTypes() []client.Object {
+	if reflect.TypeFor[Child]().Kind() == reflect.Interface {
+		return nil
+	}
+	child := reconciler.NewObject[Child]()
+	if _, ok := any(child).(runtime.Unstructured); ok {
+		return nil
+	}
+	return []client.Object{child}
 }
 
-func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
-	log := klog.FromContext(ctx).V(1).
-		WithValues("parent", client.ObjectKeyFromObject(parent))
+func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (_ reconcile.Result, err error) {
+	log := conductor.Logger(ctx, r.Details.Name, parent).V(1)
 
 	var childKey client.ObjectKey
-	if r.ShouldDeleteFn != nil {
-		current := r.ChildKeyFn(parent)
-		childKey = client.ObjectKeyFromObject(current)
-		if err := k8sCli.Get(ctx, client.ObjectKeyFromObject(current), current); err == nil && r.ShouldDeleteFn(parent) {
-			if err := k8sCli.Delete(ctx, current); err != nil {
+	if r.ChildKeyFn != nil {
+		key := r.ChildKeyFn(parent)
+		r.resolveGeneratedName(parent, key)
+		childKey = client.ObjectKeyFromObject(key)
+		defer func() { err = reconciler.WithChildKey(err, childKey) }()
+	}
+	if r.ShouldDeleteFn != nil && r.DeleteMode == reconciler.DeleteCollection {
+		if r.ShouldDeleteFn(parent) {
+			deleted, err := r.deleteCollection(ctx, k8sCli, parent)
+			if err != nil {
 				return reconcile.Result{}, err
 			}
-			log.Info("deleted child")
-			return reconcile.Result{
-				Requeue: true,
-			}, nil
-		} else if err != nil && !apierrors.IsNotFound(err) {
+			if deleted {
+				return r.deletedResult(), nil
+			}
+		}
+	} else if r.ShouldDeleteFn != nil && r.ShouldDeleteFn(parent) {
+		current, err := r.getChildKey(ctx, k8sCli, parent)
+		if err == nil {
+			return r.delete(ctx, k8sCli, log, current)
+		} else if !apierrors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
 	}
@@ -121,11 +394,34 @@ func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli clie
 		return reconcile.Result{}, nil
 	}
 
+	var inputs string
+	if r.SkipUnchanged {
+		unchanged, hash, current, err := r.unchanged(ctx, k8sCli, parent)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if unchanged {
+			log.Info("parent unchanged since the child was applied, skipping")
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, nil)
+			return reconcile.Result{}, r.publishCurrent(ctx, k8sCli, parent, current)
+		}
+		inputs = hash
+	}
+
 	desired, err := r.ReconcileFn(ctx, parent)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	if r.SkipUnchanged {
+		setInputs(parent, desired, inputs)
+	}
 
+	if r.DeleteMode == reconciler.DeleteCollection && r.DeleteLabelsFn == nil {
+		// Label the child so it is selected by deleteCollection.
+		reconciler.NewApplySet(parent, r.Details.Name).Add(desired)
+	}
+
+	generated := r.resolveGeneratedName(parent, desired)
 	if r.ChildKeyFn != nil {
 		// Backfill the name and namespace if not already set by the ReconcileFn
 		if desired.GetName() == "" {
@@ -141,34 +437,189 @@ func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli clie
 		}
 	}
 
-	key := client.ObjectKeyFromObject(desired)
-	log = log.WithValues("child", key.Name, "namespace", key.Namespace, "kind", desired.GetObjectKind().GroupVersionKind().Kind)
+	return r.apply(ctx, k8sCli, parent, desired, generated)
+}
 
-	if !r.NoReference {
-		if err := controllerutil.SetControllerReference(parent, desired, k8sCli.Scheme()); err != nil {
+// Apply creates or updates the desired child for the parent, as done by Reconcile with the object returned by the
+// ReconcileFn. It honours every option of the reconciler except the ReconcileFn, PredicateFn, ShouldDeleteFn,
+// ChildKeyFn, FieldManager and Impersonator, which makes it reusable by reconcilers managing several children.
+func (r *Reconciler[Parent, Child]) Apply(ctx context.Context, k8sCli client.Client, parent Parent, desired Child) (reconcile.Result, error) {
+	return r.apply(ctx, k8sCli, parent, desired, false)
+}
+
+// apply is Apply, for a desired child whose name is generated by the API server if generated is set.
+func (r *Reconciler[Parent, Child]) apply(ctx context.Context, k8sCli client.Client, parent Parent, desired Child, generated bool) (reconcile.Result, error) {
+	if err := reconciler.NormalizeUnstructured(desired); err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(r.ChecksumSources) > 0 {
+		if err := r.injectChecksums(ctx, k8sCli, parent, desired); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
-	// Fetch the current object, if not already set from ShouldDeleteFn.
-	current := desired.DeepCopyObject().(Child)
+	key := client.ObjectKeyFromObject(desired)
+	log := conductor.Logger(ctx, r.Details.Name, parent).V(1).
+		WithValues("child", key.Name, "namespace", key.Namespace, "kind", desired.GetObjectKind().GroupVersionKind().Kind)
 
-	if err := k8sCli.Get(ctx, key, current); err != nil {
+	referenced, err := r.markOwned(k8sCli, parent, desired)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	original := desired.DeepCopyObject().(Child)
+	attempts := 0
+	var result reconcile.Result
+	var updating bool
+	// Only conflicts of updates are retried, the creation of a child is not.
+	retriable := func(err error) bool { return updating && apierrors.IsConflict(err) }
+	err = retry.OnError(r.conflictBackoff(), retriable, func() error {
+		if attempts > 0 {
+			// Start over from the desired object, as it was changed to match the current one.
+			log.Info("child changed before it was updated, retrying", "attempt", attempts)
+			desired = original.DeepCopyObject().(Child)
+		}
+		attempts++
+		var err error
+		result, updating, err = r.createOrUpdate(ctx, k8sCli, log, parent, desired, referenced, generated)
+		return err
+	})
+	return result, reconciler.WithChildKey(err, client.ObjectKeyFromObject(desired))
+}
+
+// newChild returns an empty child with the kind and key of the desired one, to read the current child into: from the
+// NewChildFn, or a new zero value of the type of the desired child.
+func (r *Reconciler[Parent, Child]) newChild(desired Child) Child {
+	var child Child
+	if r.NewChildFn != nil {
+		child = r.NewChildFn()
+	} else {
+		// The type of the desired child is used, as Child may be an interface.
+		child = reflect.New(reflect.TypeOf(desired).Elem()).Interface().(Child)
+	}
+	// Unstructured children are read according to their kind.
+	child.GetObjectKind().SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+	child.SetName(desired.GetName())
+	child.SetNamespace(desired.GetNamespace())
+	child.SetGenerateName(desired.GetGenerateName())
+	return child
+}
+
+// conflictBackoff returns the ConflictBackoff, or retry.DefaultRetry if unset.
+func (r *Reconciler[Parent, Child]) conflictBackoff() wait.Backoff {
+	if r.ConflictBackoff != nil {
+		return *r.ConflictBackoff
+	}
+	return retry.DefaultRetry
+}
+
+// createOrUpdate reads the current child, and creates it or updates it with the desired object as needed. It returns
+// whether the child existed, in which case an error comes from its update. If generated is set, a created child is
+// named by the API server and its name recorded on the parent. A creation denied by admission (see
+// reconciler.IsAdmissionDenied) adds a `<Name>Blocked` condition and requeues the parent with the backoff of the
+// controller, as retrying right away would fail the same way.
+func (r *Reconciler[Parent, Child]) createOrUpdate(ctx context.Context, k8sCli client.Client, log klog.Logger, parent Parent, desired Child, referenced, generated bool) (result reconcile.Result, updating bool, err error) {
+	key := client.ObjectKeyFromObject(desired)
+	// Fetch the current object.
+	current := r.newChild(desired)
+
+	if err := r.getCurrent(ctx, k8sCli, current, generated); err != nil {
 		// Allow only not-found errors, any other error is a problem.
 		if !apierrors.IsNotFound(err) {
 			log.Error(err, "unable to fetch child")
-			return reconcile.Result{}, err
+			return reconcile.Result{}, false, err
+		}
+
+		if r.ChangeDetection == reconciler.ChangeDetectionHash {
+			if _, err := setDesiredHash(desired); err != nil {
+				return reconcile.Result{}, false, err
+			}
 		}
 
 		// Create the object & requeue, it doesn't yet exist.
+		if r.SkipTerminatingNamespace && parent.GetNamespace() != "" {
+			terminating, err := reconciler.NamespaceTerminating(ctx, k8sCli, parent.GetNamespace())
+			if err != nil {
+				return reconcile.Result{}, false, err
+			}
+			if terminating {
+				log.Info("namespace of the parent is terminating, skipping creation")
+				r.addNamespaceTerminatingCondition(ctx, parent.GetNamespace())
+				return reconcile.Result{}, false, nil
+			}
+		}
+
+		if generated {
+			// A child whose recorded name is gone is created with a new name.
+			desired.SetName("")
+		}
 		if err := k8sCli.Create(ctx, desired); err != nil {
-			return reconcile.Result{}, err
+			if reconciler.IsNamespaceTerminating(err) {
+				// Requeue with the backoff of the controller, the creation fails until the namespace is gone.
+				log.Info("namespace of the child is terminating, requeueing")
+				r.addNamespaceTerminatingCondition(ctx, key.Namespace)
+				return reconcile.Result{Requeue: true}, false, nil
+			}
+			if reconciler.IsAdmissionDenied(err) {
+				// Back off as well, the creation fails until the quota, the limits or the policies change.
+				log.Info("creation of the child denied by admission, requeueing", "reason", err.Error())
+				r.addBlockedCondition(ctx, err)
+				return reconcile.Result{Requeue: true}, false, nil
+			}
+			return reconcile.Result{}, false, err
+		}
+		if generated {
+			if err := r.recordGeneratedName(ctx, k8sCli, log, parent, desired); err != nil {
+				return reconcile.Result{}, false, err
+			}
 		}
 
 		log.Info("created child")
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationCreate, desired)
+		r.resetLogSampler(desired)
+		if r.PublishFn != nil {
+			if err := r.PublishFn(ctx, parent, desired); err != nil {
+				return reconcile.Result{}, false, err
+			}
+		}
 		return reconcile.Result{
 			Requeue: true,
-		}, nil
+		}, false, nil
+	}
+
+	if r.PublishFn != nil {
+		defer func() {
+			if err == nil {
+				err = r.PublishFn(ctx, parent, current)
+			}
+		}()
+	}
+	if r.NextSyncFn != nil {
+		defer func() {
+			if err == nil && result.IsZero() {
+				// The child is up to date.
+				result = requestSync(ctx, result, r.NextSyncFn(parent, current))
+			}
+		}()
+	}
+
+	if referenced && !metav1.IsControlledBy(current, parent) {
+		if proceed, err := r.checkOwnership(ctx, log, current, desired); !proceed {
+			return reconcile.Result{}, true, err
+		}
+	} else if !referenced && !r.NoReference && !reconciler.IsOwnedBy(current, parent) {
+		if owner, ok := reconciler.OwnerKey(current); ok {
+			return reconcile.Result{}, true, fmt.Errorf("%w: %s is owned by %s", reconciler.ErrForeignOwner, key, owner)
+		}
+		if proceed, err := r.adoptOrphan(log, current, desired); !proceed {
+			return reconcile.Result{}, true, err
+		}
+	}
+
+	if r.RecreateOnImmutableChange && !current.GetDeletionTimestamp().IsZero() {
+		// The child is being deleted to be recreated, wait for it to be gone.
+		log.Info("waiting for child deletion before recreating it")
+		return reconcile.Result{Requeue: true}, true, nil
 	}
 
 	// ResourceVersion should come from the API, so we need to update it.
@@ -177,21 +628,109 @@ func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli clie
 	desired.SetCreationTimestamp(current.GetCreationTimestamp())
 	desired.SetGeneration(current.GetGeneration())
 	desired.SetUID(current.GetUID())
+	for _, preserve := range r.PreserveFields {
+		preserve(current, desired)
+	}
+	if r.MinUpdateInterval > 0 {
+		reconciler.CarryLastUpdated(current, desired)
+	}
+	if r.RestartTriggerFn != nil {
+		reconciler.CarryRestartedAt(current, desired)
+		revision, err := r.RestartTriggerFn(ctx, parent, current)
+		if err != nil {
+			return reconcile.Result{}, true, err
+		}
+		if reconciler.RestartOnChange(current, desired, revision, time.Now()) {
+			log.Info("restarting child")
+		}
+	}
 	if r.PreUpdateFn != nil {
 		if err := r.PreUpdateFn(ctx, parent, current, desired); err != nil {
-			return reconcile.Result{}, err
+			return reconcile.Result{}, true, err
 		}
 	}
 
 	// We always append the two options IgnoreManagedFields and IgnoreTypeMeta.
 	// This avoids unnecessary updates when the child object is already in the desired state.
-	compareOpts := append(r.CompareOpts, reconciler.IgnoreManagedFields(), reconciler.IgnoreTypeMeta(), reconciler.IgnoreStatusFields())
-	if cmp.Equal(current, desired, compareOpts...) {
-		log.Info("no changes", "key", key)
-		return reconcile.Result{}, nil
+	compareOpts := append(r.CompareOpts, reconciler.IgnoreManagedFields(), reconciler.IgnoreTypeMeta(), reconciler.IgnoreStatusFields(),
+		reconciler.UnstructuredDefaults())
+	if r.ChangeDetection == reconciler.ChangeDetectionHash {
+		hash, err := setDesiredHash(desired)
+		if err != nil {
+			return reconcile.Result{}, true, err
+		}
+		if current.GetAnnotations()[reconciler.DesiredHashAnnotation] == hash {
+			r.logNoChanges(log, current, "no changes in desired hash", "key", key)
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, desired)
+			return reconcile.Result{}, true, nil
+		}
+	} else {
+		// Unstructured children have no zero values to compare with the defaults set by the API server, so the fields
+		// they don't set are taken from the current object.
+		reconciler.MergeUnstructured(current, desired)
+		if r.dryRun() && r.DefaultingCache != nil && r.DefaultingCache.Clean(current, desired, time.Now()) {
+			// A dry-run already showed that the differences are only defaults, skip the comparison.
+			r.logNoChanges(log, current, "no changes after cached dry-run", "key", key)
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, desired)
+			return reconcile.Result{}, true, nil
+		}
+		if cmp.Equal(current, desired, compareOpts...) {
+			r.logNoChanges(log, current, "no changes", "key", key)
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, desired)
+			return reconcile.Result{}, true, nil
+		}
+	}
+
+	var update reconciler.Update
+	if r.FlapDetector != nil {
+		var err error
+		if update, err = reconciler.UpdateFor(current, desired); err != nil {
+			return reconcile.Result{}, true, err
+		}
+		if r.FlapDetector.Flapping(update, time.Now()) {
+			log.Info("child update is flapping, another controller may be reverting it", "fields", update.Fields)
+			r.addConflictingCondition(ctx, update)
+			return reconcile.Result{}, true, nil
+		}
 	}
 
-	if r.DryRunType != reconciler.DryRunNone {
+	if r.MinUpdateInterval > 0 {
+		now := time.Now()
+		if wait := r.throttleWait(current, now); wait > 0 {
+			log.Info("throttling child update", "retryAfter", wait)
+			r.addThrottledCondition(ctx, wait)
+			return reconcile.Result{RequeueAfter: wait}, true, nil
+		}
+		reconciler.SetLastUpdated(desired, now)
+	}
+
+	if r.RecreateOnImmutableChange && r.IsImmutableChangeFn != nil && r.IsImmutableChangeFn(current, desired) {
+		log.Info("immutable fields changed, recreating child")
+		result, err := r.recreate(ctx, k8sCli, log, current)
+		return result, true, err
+	}
+
+	if r.UpdateStrategy != "" && r.UpdateStrategy != reconciler.UpdateStrategyUpdate {
+		result, err = r.patch(ctx, k8sCli, log, current, desired, compareOpts)
+	} else {
+		result, err = r.update(ctx, k8sCli, log, current, desired, compareOpts)
+	}
+	if err != nil && r.RecreateOnImmutableChange && reconciler.IsImmutableFieldError(err) {
+		log.Info("update rejected due to immutable fields, recreating child", "error", err.Error())
+		result, err = r.recreate(ctx, k8sCli, log, current)
+		return result, true, err
+	}
+	if err == nil && result.Requeue && r.FlapDetector != nil {
+		// Only updates actually sent are recorded, not the ones found to be no-ops by a dry-run.
+		r.FlapDetector.Record(update, time.Now())
+	}
+	return result, true, err
+}
+
+// update updates the child with the desired object, unless a dry-run shows it wouldn't change anything.
+func (r *Reconciler[Parent, Child]) update(ctx context.Context, k8sCli client.Client, log klog.Logger, current, desired Child, compareOpts []cmp.Option) (reconcile.Result, error) {
+	key := client.ObjectKeyFromObject(desired)
+	if r.dryRun() {
 		// Dry-run the update to see if it would change anything.
 		// We need to copy it due to kubernetes/kubernetes/pull/121167 not being resolved yet.
 		// TL;DR, due to the above bug, we need to dry-run both objects (desired and current) then compare them
@@ -213,22 +752,340 @@ func (r *Reconciler[Parent, Child]) doReconcile(ctx context.Context, k8sCli clie
 			// Log the diff, user should update the object returned by ReconcileFn to include the changes.
 			// Or to ignore annotations like those added by the deployment controller.
 			if r.DryRunType == reconciler.DryRunWarn {
-				diff := cmp.Diff(currentHack, desiredCopy, compareOpts...)
-				log.Info("no changes after dry-run. Please update CompareOpts or add the API defaults to the object", "diff", diff)
+				diff := r.diff(currentHack, desiredCopy, compareOpts)
+				r.logNoChanges(log, current, "no changes after dry-run. Please update CompareOpts or add the API defaults to the object", "diff", diff)
+			}
+			conductor.RecordDefaultsMismatch(ctx, r.Details.Name)
+			if r.DefaultingCache != nil {
+				r.DefaultingCache.Record(current, desired, time.Now())
 			}
 
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, desired)
 			return reconcile.Result{}, nil
 		}
 	}
 
-	log.Info("updating child", "key", key)
+	if r.DiffRenderer != nil {
+		log.Info("updating child", "key", key, "diff", r.diff(current, desired, compareOpts))
+	} else {
+		log.Info("updating child", "key", key)
+	}
 	// Do an update as it's required.
 	if err := k8sCli.Update(ctx, desired); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	log.Info("updated child", "key", key)
+	conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationUpdate, desired)
+	r.resetLogSampler(desired)
+	return reconcile.Result{
+		Requeue: true,
+	}, nil
+}
+
+// dryRun returns true if updates are dry-run first, to check whether they would change anything.
+func (r *Reconciler[Parent, Child]) dryRun() bool {
+	return r.DryRunType != reconciler.DryRunNone && r.ChangeDetection != reconciler.ChangeDetectionHash
+}
+
+// setDesiredHash sets the DesiredHashAnnotation of the desired child, and returns the hash.
+func setDesiredHash(desired client.Object) (string, error) {
+	hash, err := reconciler.HashObject(desired)
+	if err != nil {
+		return "", err
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[reconciler.DesiredHashAnnotation] = hash
+	desired.SetAnnotations(annotations)
+	return hash, nil
+}
+
+// delete deletes the current child when the ShouldDeleteFn returns true. With ForegroundDeletion, a child already being
+// deleted is waited for instead.
+func (r *Reconciler[Parent, Child]) delete(ctx context.Context, k8sCli client.Client, log klog.Logger, current client.Object) (reconcile.Result, error) {
+	if r.ForegroundDeletion && !current.GetDeletionTimestamp().IsZero() {
+		log.Info("waiting for child to be deleted")
+		return r.deletedResult(), nil
+	}
+
+	// Only delete the object that was read, not one recreated with the same name in the meantime.
+	uid := current.GetUID()
+	opts := append([]client.DeleteOption{client.Preconditions{UID: &uid}}, r.DeleteOptions...)
+	if r.ForegroundDeletion {
+		opts = append(opts, client.PropagationPolicy(metav1.DeletePropagationForeground))
+	}
+	if err := k8sCli.Delete(ctx, current, opts...); apierrors.IsConflict(err) {
+		log.Info("child changed before it was deleted")
+		return reconcile.Result{Requeue: true}, nil
+	} else if client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, err
+	}
+	log.Info("deleted child", "foreground", r.ForegroundDeletion)
+	conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, current)
+	r.resetLogSampler(current)
+	return r.deletedResult(), nil
+}
+
+// getChildKey reads the current child with the key of the ChildKeyFn, to check whether it exists or is being deleted.
+// Only its metadata is read with MetadataReads.
+func (r *Reconciler[Parent, Child]) getChildKey(ctx context.Context, k8sCli client.Client, parent Parent) (client.Object, error) {
+	current := r.ChildKeyFn(parent)
+	if r.resolveGeneratedName(parent, current) && current.GetName() == "" {
+		return nil, r.getCurrent(ctx, k8sCli, current, true)
+	}
+	key := client.ObjectKeyFromObject(current)
+	if !r.MetadataReads {
+		return current, r.reader(ctx, k8sCli).Get(ctx, key, current)
+	}
+
+	gvk, err := apiutil.GVKForObject(current, k8sCli.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	metadata := &metav1.PartialObjectMetadata{}
+	metadata.SetGroupVersionKind(gvk)
+	if err := r.reader(ctx, k8sCli).Get(ctx, key, metadata); err != nil {
+		return nil, err
+	}
+	// The type metadata is not always set by the reader.
+	metadata.SetGroupVersionKind(gvk)
+	return metadata, nil
+}
+
+// deletedResult returns the result of a reconcile that deleted children: with ForegroundDeletion, the parent is
+// requeued after the DeletionRequeueAfter delay to check whether they are gone.
+func (r *Reconciler[Parent, Child]) deletedResult() reconcile.Result {
+	if !r.ForegroundDeletion {
+		return reconcile.Result{Requeue: true}
+	}
+	return r.waitForDeletion()
+}
+
+// waitForDeletion returns the result requeuing the parent after the DeletionRequeueAfter delay.
+func (r *Reconciler[Parent, Child]) waitForDeletion() reconcile.Result {
+	if r.DeletionRequeueAfter > 0 {
+		return reconcile.Result{RequeueAfter: r.DeletionRequeueAfter}
+	}
+	return reconcile.Result{RequeueAfter: DefaultDeletionRequeueAfter}
+}
+
+// Cleanup deletes the children when the ShouldDeleteFn returns true for the parent being deleted, as Reconcile would,
+// and requeues the parent after the DeletionRequeueAfter delay until they are gone. It is called by the teardown
+// sequence of the conductor. Without a ShouldDeleteFn, the children are left to the garbage collector, except the
+// child of the ChildKeyFn when it is owned through labels (e.g. a cluster-scoped child), which the garbage collector
+// ignores.
+func (r *Reconciler[Parent, Child]) Cleanup(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.Impersonator != nil {
+		if err := r.Impersonator.Validate(); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	ctx, k8sCli = r.client(ctx, k8sCli)
+	if r.ShouldDeleteFn == nil {
+		return r.cleanupLabelOwned(ctx, k8sCli, parent)
+	}
+	if !r.ShouldDeleteFn(parent) {
+		return reconcile.Result{}, nil
+	}
+	if err := r.validateFuncs(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if r.DeleteMode == reconciler.DeleteCollection {
+		deleted, err := r.deleteCollection(ctx, k8sCli, parent)
+		if err != nil || !deleted {
+			return reconcile.Result{}, err
+		}
+		return r.waitForDeletion(), nil
+	}
+
+	current, err := r.getChildKey(ctx, k8sCli, parent)
+	if err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	log := conductor.Logger(ctx, r.Details.Name, parent).V(1)
+	if !current.GetDeletionTimestamp().IsZero() {
+		log.Info("waiting for child to be deleted")
+		return r.waitForDeletion(), nil
+	}
+	// A child that changed before it was deleted is read again after the delay as well.
+	if _, err := r.delete(ctx, k8sCli, log, current); err != nil {
+		return reconcile.Result{}, err
+	}
+	return r.waitForDeletion(), nil
+}
+
+// cleanupLabelOwned deletes the child of the ChildKeyFn if it is owned by the parent through labels, and requeues the
+// parent until it is gone.
+func (r *Reconciler[Parent, Child]) cleanupLabelOwned(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.ChildKeyFn == nil || r.NoReference {
+		return reconcile.Result{}, nil
+	}
+	current, err := r.getChildKey(ctx, k8sCli, parent)
+	if err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if !reconciler.IsOwnedBy(current, parent) || metav1.IsControlledBy(current, parent) {
+		return reconcile.Result{}, nil
+	}
+	log := conductor.Logger(ctx, r.Details.Name, parent).V(1)
+	if current.GetDeletionTimestamp().IsZero() {
+		if _, err := r.delete(ctx, k8sCli, log, current); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return r.waitForDeletion(), nil
+}
+
+// deleteCollection deletes the children matching the DeleteLabelsFn labels with a DeleteAllOf call, and returns
+// whether any child was deleted. The children are listed (metadata only) first to skip the call when none is left.
+func (r *Reconciler[Parent, Child]) deleteCollection(ctx context.Context, k8sCli client.Client, parent Parent) (bool, error) {
+	child := reconciler.NewObject[Child]()
+	gvk, err := apiutil.GVKForObject(child, k8sCli.Scheme())
+	if err != nil {
+		return false, err
+	}
+	namespaced, err := k8sCli.IsObjectNamespaced(child)
+	if err != nil {
+		return false, err
+	}
+
+	var selector client.MatchingLabels
+	if r.DeleteLabelsFn != nil {
+		selector = r.DeleteLabelsFn(parent)
+	} else {
+		selector = reconciler.NewApplySet(parent, r.Details.Name).Labels
+	}
+	opts := []client.ListOption{selector}
+	deleteOpts := []client.DeleteAllOfOption{selector}
+	if namespaced {
+		opts = append(opts, client.InNamespace(parent.GetNamespace()))
+		deleteOpts = append(deleteOpts, client.InNamespace(parent.GetNamespace()))
+	}
+
+	existing := &metav1.PartialObjectMetadataList{}
+	existing.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := k8sCli.List(ctx, existing, opts...); err != nil {
+		return false, err
+	}
+	if len(existing.Items) == 0 {
+		return false, nil
+	}
+
+	log := conductor.Logger(ctx, r.Details.Name, parent).V(1)
+	for _, opt := range r.DeleteOptions {
+		if opt, ok := opt.(client.DeleteAllOfOption); ok {
+			deleteOpts = append(deleteOpts, opt)
+		}
+	}
+	if r.ForegroundDeletion {
+		deleting := 0
+		for i := range existing.Items {
+			if !existing.Items[i].GetDeletionTimestamp().IsZero() {
+				deleting++
+			}
+		}
+		if deleting == len(existing.Items) {
+			log.Info("waiting for children to be deleted", "count", deleting, "kind", gvk.Kind)
+			return true, nil
+		}
+		deleteOpts = append(deleteOpts, client.PropagationPolicy(metav1.DeletePropagationForeground))
+	}
+
+	if err := k8sCli.DeleteAllOf(ctx, child, deleteOpts...); err != nil {
+		return false, err
+	}
+	log.Info("deleted children", "count", len(existing.Items), "kind", gvk.Kind)
+	for i := range existing.Items {
+		existing.Items[i].SetGroupVersionKind(gvk)
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, &existing.Items[i])
+	}
+	return true, nil
+}
+
+// recreate deletes the current child, which is created again on the next reconcile.
+func (r *Reconciler[Parent, Child]) recreate(ctx context.Context, k8sCli client.Client, log klog.Logger, current Child) (reconcile.Result, error) {
+	uid := current.GetUID()
+	if err := k8sCli.Delete(ctx, current,
+		client.Preconditions{UID: &uid},
+		client.PropagationPolicy(metav1.DeletePropagationBackground),
+	); client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("deleted child to recreate it")
+	conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationDelete, current)
+	r.resetLogSampler(current)
 	return reconcile.Result{
 		Requeue: true,
 	}, nil
 }
+
+// patch sends the fields of the desired object that differ from the current one, following the UpdateStrategy.
+func (r *Reconciler[Parent, Child]) patch(ctx context.Context, k8sCli client.Client, log klog.Logger, current, desired Child, compareOpts []cmp.Option) (reconcile.Result, error) {
+	patch, err := reconciler.PatchFor(r.UpdateStrategy, current, desired)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if patch == nil {
+		r.logNoChanges(log, current, "no changes in patched fields")
+		conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, current)
+		return reconcile.Result{}, nil
+	}
+
+	if r.dryRun() {
+		// Start from the locally patched object, in case the dry-run response isn't decoded into it.
+		patched := current.DeepCopyObject().(Child)
+		if err := reconciler.ApplyPatch(patched, patch); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := k8sCli.Patch(ctx, patched, patch, client.DryRunAll); err != nil {
+			log.Error(err, "unable to dry-run patch")
+			return reconcile.Result{}, err
+		}
+		if cmp.Equal(current, patched, compareOpts...) {
+			if r.DryRunType == reconciler.DryRunWarn {
+				diff := r.diff(current, desired, compareOpts)
+				r.logNoChanges(log, current, "no changes after dry-run. Please update CompareOpts or add the API defaults to the object", "diff", diff)
+			}
+			conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationNoOp, current)
+			return reconcile.Result{}, nil
+		}
+	}
+
+	if r.DiffRenderer != nil {
+		log.Info("patching child", "strategy", r.UpdateStrategy, "diff", r.diff(current, desired, compareOpts))
+	} else {
+		log.Info("patching child", "strategy", r.UpdateStrategy)
+	}
+	if err := k8sCli.Patch(ctx, current, patch); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("patched child")
+	conductor.RecordOperation(ctx, r.Details.Name, conductor.OperationUpdate, current)
+	r.resetLogSampler(current)
+	return reconcile.Result{
+		Requeue: true,
+	}, nil
+}
+
+// diff renders the difference between the current and the desired child with the DiffRenderer, or cmp.Diff if unset.
+// Secrets and the SensitiveFields are redacted.
+func (r *Reconciler[Parent, Child]) diff(current, desired Child, compareOpts []cmp.Option) string {
+	renderer := r.DiffRenderer
+	if renderer == nil {
+		renderer = reconciler.CmpDiffRenderer{Opts: compareOpts}
+	}
+	if _, ok := renderer.(reconciler.RedactingDiffRenderer); !ok {
+		renderer = reconciler.RedactingDiffRenderer{Renderer: renderer, SensitiveFields: r.SensitiveFields}
+	}
+	diff, err := renderer.RenderDiff(current, desired)
+	if err != nil {
+		return fmt.Sprintf("unable to render diff: %v", err)
+	}
+	return diff
+}
@@ -2,15 +2,24 @@ package simple
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
 	"github.com/ethan-gallant/maestro/pkg/reconciler"
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ReconcileFn[Parent client.Object, Child client.Object] func(ctx context.Context, parent Parent) (Child, error)
 
+// DependentReconcileFn is a ReconcileFn that additionally receives the dependencies provided to the conductor.
+// As all inputs are passed explicitly, it can be unit-tested without a conductor.
+type DependentReconcileFn[Parent client.Object, Child client.Object, Deps any] func(ctx context.Context, parent Parent, deps *Deps) (Child, error)
+
 // Builder is a builder for the Reconciler.
 type Builder[Parent client.Object, Child client.Object] struct {
 	reconciler Reconciler[Parent, Child]
@@ -20,13 +29,28 @@ type Builder[Parent client.Object, Child client.Object] struct {
 func FromReconcileFunc[Parent client.Object, Child client.Object](fn ReconcileFn[Parent, Child]) *Builder[Parent, Child] {
 	return &Builder[Parent, Child]{
 		reconciler: Reconciler[Parent, Child]{
-			ReconcileFn: fn,
-			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
-			DryRunType:  reconciler.DryRunWarn,
+			ReconcileFn:        fn,
+			PredicateFn:        reconciler.IsNotMarkedForDeletion[Parent],
+			DryRunType:         reconciler.DryRunWarn,
+			UpdateStrategy:     reconciler.UpdateStrategyUpdate,
+			ForeignOwnerPolicy: reconciler.ForeignOwnerError,
 		},
 	}
 }
 
+// FromDependentFunc returns a new instance of Builder for a DependentReconcileFn.
+// The dependencies are injected from the context, see conductor.Provide.
+func FromDependentFunc[Parent client.Object, Child client.Object, Deps any](fn DependentReconcileFn[Parent, Child, Deps]) *Builder[Parent, Child] {
+	return FromReconcileFunc(func(ctx context.Context, parent Parent) (Child, error) {
+		deps, err := conductor.Inject[Deps](ctx)
+		if err != nil {
+			var child Child
+			return child, err
+		}
+		return fn(ctx, parent, deps)
+	})
+}
+
 // WithPredicateFn sets the PredicateFn field.
 func (b *Builder[Parent, Child]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent, Child] {
 	b.reconciler.PredicateFn = predicate
@@ -67,12 +91,255 @@ func (b *Builder[Parent, Child]) WithChildKeyFn(childKeyFn func(Parent) Child) *
 	return b
 }
 
+// WithOwnership sets the Ownership field.
+func (b *Builder[Parent, Child]) WithOwnership(mode reconciler.OwnershipMode) *Builder[Parent, Child] {
+	b.reconciler.Ownership = mode
+	return b
+}
+
+// WithDeleteMode sets the DeleteMode field.
+func (b *Builder[Parent, Child]) WithDeleteMode(mode reconciler.DeleteMode) *Builder[Parent, Child] {
+	b.reconciler.DeleteMode = mode
+	return b
+}
+
+// WithForegroundDeletion deletes the children in the foreground and waits until they are gone, checking again after
+// the requeueAfter delay (DefaultDeletionRequeueAfter if zero).
+func (b *Builder[Parent, Child]) WithForegroundDeletion(requeueAfter time.Duration) *Builder[Parent, Child] {
+	b.reconciler.ForegroundDeletion = true
+	b.reconciler.DeletionRequeueAfter = requeueAfter
+	return b
+}
+
+// WithDeleteOptions adds options to the deletions triggered by the ShouldDeleteFn.
+func (b *Builder[Parent, Child]) WithDeleteOptions(opts ...client.DeleteOption) *Builder[Parent, Child] {
+	b.reconciler.DeleteOptions = append(b.reconciler.DeleteOptions, opts...)
+	return b
+}
+
+// WithDeleteLabelsFn sets the DeleteLabelsFn field.
+func (b *Builder[Parent, Child]) WithDeleteLabelsFn(fn func(Parent) map[string]string) *Builder[Parent, Child] {
+	b.reconciler.DeleteLabelsFn = fn
+	return b
+}
+
 func (b *Builder[Parent, Child]) WithPreUpdateFn(preUpdateFn func(ctx context.Context, parent Parent, previous, child Child) error) *Builder[Parent, Child] {
 	b.reconciler.PreUpdateFn = preUpdateFn
 	return b
 }
 
+// WithRestartTriggerFn sets the RestartTriggerFn field.
+func (b *Builder[Parent, Child]) WithRestartTriggerFn(triggerFn func(ctx context.Context, parent Parent, current Child) (string, error)) *Builder[Parent, Child] {
+	b.reconciler.RestartTriggerFn = triggerFn
+	return b
+}
+
+// AddChecksumSource adds a ConfigMap or Secret whose checksum is annotated on the pod template of the child workload.
+func (b *Builder[Parent, Child]) AddChecksumSource(sources ...ChecksumSource[Parent]) *Builder[Parent, Child] {
+	b.reconciler.ChecksumSources = append(b.reconciler.ChecksumSources, sources...)
+	return b
+}
+
+// WithUpdateStrategy configures how an out of date child is updated.
+func (b *Builder[Parent, Child]) WithUpdateStrategy(strategy reconciler.UpdateStrategy) *Builder[Parent, Child] {
+	b.reconciler.UpdateStrategy = strategy
+	return b
+}
+
+// WithAdoptOrphans sets the AdoptOrphans field.
+func (b *Builder[Parent, Child]) WithAdoptOrphans(adopt bool) *Builder[Parent, Child] {
+	b.reconciler.AdoptOrphans = adopt
+	return b
+}
+
+// WithSkipTerminatingNamespace sets the SkipTerminatingNamespace field.
+func (b *Builder[Parent, Child]) WithSkipTerminatingNamespace(skip bool) *Builder[Parent, Child] {
+	b.reconciler.SkipTerminatingNamespace = skip
+	return b
+}
+
+// WithLogSampler samples the "no changes" messages of the up to date child with the sampler.
+func (b *Builder[Parent, Child]) WithLogSampler(sampler *reconciler.LogSampler) *Builder[Parent, Child] {
+	b.reconciler.LogSampler = sampler
+	return b
+}
+
+// WithAdoptSelector restricts the adopted orphans to those matching the selector.
+func (b *Builder[Parent, Child]) WithAdoptSelector(selector labels.Selector) *Builder[Parent, Child] {
+	b.reconciler.AdoptSelector = selector
+	return b
+}
+
+// WithForeignOwnerPolicy configures what happens when an existing child is controlled by another owner.
+func (b *Builder[Parent, Child]) WithForeignOwnerPolicy(policy reconciler.ForeignOwnerPolicy) *Builder[Parent, Child] {
+	b.reconciler.ForeignOwnerPolicy = policy
+	return b
+}
+
+// WithRetryPolicy sets the RetryPolicy field, retrying transient errors before they requeue the parent.
+func (b *Builder[Parent, Child]) WithRetryPolicy(policy reconciler.RetryPolicy) *Builder[Parent, Child] {
+	b.reconciler.RetryPolicy = &policy
+	return b
+}
+
+// WithPreserveFields adds functions copying server-populated fields from the current onto the desired child before
+// they are compared, e.g. reconciler.PreserveServiceClusterIP.
+func (b *Builder[Parent, Child]) WithPreserveFields(preserve ...func(current, desired Child)) *Builder[Parent, Child] {
+	b.reconciler.PreserveFields = append(b.reconciler.PreserveFields, preserve...)
+	return b
+}
+
+// WithMinUpdateInterval rate limits the updates of the child to one per interval.
+func (b *Builder[Parent, Child]) WithMinUpdateInterval(interval time.Duration) *Builder[Parent, Child] {
+	b.reconciler.MinUpdateInterval = interval
+	return b
+}
+
+// WithFlapDetector stops updating the child when the detector sees the same update applied repeatedly. The detector
+// should be shared across builds, as it keeps its history in memory.
+func (b *Builder[Parent, Child]) WithFlapDetector(detector *reconciler.FlapDetector) *Builder[Parent, Child] {
+	b.reconciler.FlapDetector = detector
+	return b
+}
+
+// WithTimeout sets the Timeout field, bounding the duration of a reconcile.
+func (b *Builder[Parent, Child]) WithTimeout(timeout time.Duration) *Builder[Parent, Child] {
+	b.reconciler.Timeout = timeout
+	return b
+}
+
+// WithRecreateOnImmutableChange deletes and recreates the child when it can't be updated in place because of immutable
+// fields. The optional isImmutableChange detects such changes before sending the update.
+func (b *Builder[Parent, Child]) WithRecreateOnImmutableChange(isImmutableChange func(current, desired Child) bool) *Builder[Parent, Child] {
+	b.reconciler.RecreateOnImmutableChange = true
+	b.reconciler.IsImmutableChangeFn = isImmutableChange
+	return b
+}
+
+// WithChangeDetection sets how an out of date child is detected, e.g. reconciler.ChangeDetectionHash to compare a
+// hash of the desired object stored on the child instead of comparing the objects.
+func (b *Builder[Parent, Child]) WithChangeDetection(detection reconciler.ChangeDetection) *Builder[Parent, Child] {
+	b.reconciler.ChangeDetection = detection
+	return b
+}
+
+// WithSensitiveFields adds the dot-separated paths of fields redacted from the logged diffs, e.g.
+// "spec.credentials.password".
+func (b *Builder[Parent, Child]) WithSensitiveFields(fields ...string) *Builder[Parent, Child] {
+	b.reconciler.SensitiveFields = append(b.reconciler.SensitiveFields, fields...)
+	return b
+}
+
+// WithDefaultingCache sets the DefaultingCache skipping the dry-runs already known to show no changes. The cache
+// should be shared across reconciles.
+func (b *Builder[Parent, Child]) WithDefaultingCache(cache *reconciler.DefaultingCache) *Builder[Parent, Child] {
+	b.reconciler.DefaultingCache = cache
+	return b
+}
+
+// WithDiffRenderer sets the DiffRenderer used to log the changes of updated children, e.g. reconciler.YAMLDiffRenderer.
+func (b *Builder[Parent, Child]) WithDiffRenderer(renderer reconciler.DiffRenderer) *Builder[Parent, Child] {
+	b.reconciler.DiffRenderer = renderer
+	return b
+}
+
+// WithSkipUnchanged skips the ReconcileFn while neither the generation of the parent nor the hash returned by the
+// inputsHashFn, which may be nil, changed since the child was applied. It requires a ChildKeyFn.
+func (b *Builder[Parent, Child]) WithSkipUnchanged(inputsHashFn func(ctx context.Context, parent Parent) (string, error)) *Builder[Parent, Child] {
+	b.reconciler.SkipUnchanged = true
+	b.reconciler.InputsHashFn = inputsHashFn
+	return b
+}
+
+// WithLiveReads reads the current child with the uncached reader of the conductor, see conductor.Builder.WithAPIReader.
+func (b *Builder[Parent, Child]) WithLiveReads(live bool) *Builder[Parent, Child] {
+	b.reconciler.LiveReads = live
+	return b
+}
+
+// WithAPIReader sets the APIReader, an uncached reader of the current child, e.g. the one of manager.GetAPIReader.
+func (b *Builder[Parent, Child]) WithAPIReader(reader client.Reader) *Builder[Parent, Child] {
+	b.reconciler.APIReader = reader
+	return b
+}
+
+// WithNewChildFn sets the NewChildFn returning the empty Child the current child is read into.
+func (b *Builder[Parent, Child]) WithNewChildFn(fn func() Child) *Builder[Parent, Child] {
+	b.reconciler.NewChildFn = fn
+	return b
+}
+
+// WithConflictBackoff sets the ConflictBackoff bounding the retries of updates rejected with a conflict.
+func (b *Builder[Parent, Child]) WithConflictBackoff(backoff wait.Backoff) *Builder[Parent, Child] {
+	b.reconciler.ConflictBackoff = &backoff
+	return b
+}
+
+// WithMetadataReads reads only the metadata of the child to check whether it exists or is being deleted.
+func (b *Builder[Parent, Child]) WithMetadataReads(metadataOnly bool) *Builder[Parent, Child] {
+	b.reconciler.MetadataReads = metadataOnly
+	return b
+}
+
+// WithResyncInterval sets the ResyncInterval after which the parent is reconciled again once the child is up to date.
+func (b *Builder[Parent, Child]) WithResyncInterval(interval time.Duration) *Builder[Parent, Child] {
+	b.reconciler.ResyncInterval = interval
+	return b
+}
+
+// WithPublishFn sets the function publishing the observed child in the conductor State, and the outputs it publishes.
+func (b *Builder[Parent, Child]) WithPublishFn(publishFn func(ctx context.Context, parent Parent, child Child) error, outputs ...conductor.Port) *Builder[Parent, Child] {
+	b.reconciler.PublishFn = publishFn
+	b.reconciler.Outputs = outputs
+	return b
+}
+
+// WithNextSyncFn sets the function returning the delay after which the parent is reconciled again, given the up to
+// date child.
+func (b *Builder[Parent, Child]) WithNextSyncFn(nextSyncFn func(parent Parent, child Child) time.Duration) *Builder[Parent, Child] {
+	b.reconciler.NextSyncFn = nextSyncFn
+	return b
+}
+
+// WithFieldManager sets the FieldManager of the writes of the reconciler.
+func (b *Builder[Parent, Child]) WithFieldManager(manager string) *Builder[Parent, Child] {
+	b.reconciler.FieldManager = manager
+	return b
+}
+
+// WithImpersonation makes the reconciler act as the service account. The configuration of the manager must have
+// reconciler.EnableImpersonation applied.
+func (b *Builder[Parent, Child]) WithImpersonation(serviceAccount types.NamespacedName) *Builder[Parent, Child] {
+	b.reconciler.Impersonator = reconciler.NewServiceAccountImpersonator(serviceAccount.Namespace, serviceAccount.Name)
+	return b
+}
+
+// WithImpersonator sets the Impersonator, to act as any user.
+func (b *Builder[Parent, Child]) WithImpersonator(impersonator *reconciler.Impersonator) *Builder[Parent, Child] {
+	b.reconciler.Impersonator = impersonator
+	return b
+}
+
 // Build returns the constructed Reconciler.
 func (b *Builder[Parent, Child]) Build() *Reconciler[Parent, Child] {
 	return &b.reconciler
 }
+
+// BuildValidated builds the reconciler and validates its configuration, see Reconciler.Validate. Misconfigurations
+// are then caught at startup instead of on the first reconcile.
+func (b *Builder[Parent, Child]) BuildValidated() (*Reconciler[Parent, Child], error) {
+	r := b.Build()
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MustBuild is like BuildValidated, but panics if the configuration is invalid.
+func (b *Builder[Parent, Child]) MustBuild() *Reconciler[Parent, Child] {
+	r, err := b.BuildValidated()
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
@@ -2,17 +2,44 @@ package simple
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/binder"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/go-logr/logr/funcr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestConfigMapUpdate(t *testing.T) {
@@ -44,9 +71,10 @@ func TestConfigMapUpdate(t *testing.T) {
 		return cm, nil
 	}
 
-	// Create a SimpleReconciler
+	// Create a SimpleReconciler, taking over the existing ConfigMap
 	reconciler := Reconciler[client.Object, client.Object]{
-		ReconcileFn: reconcileFn,
+		ReconcileFn:  reconcileFn,
+		AdoptOrphans: true,
 	}
 
 	// Call the Reconcile method
@@ -86,3 +114,1892 @@ func TestConfigMapUpdate(t *testing.T) {
 	assert.Error(t, err)
 	require.False(t, result.Requeue || result.RequeueAfter > 0)
 }
+
+func TestRestartTrigger(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+	}
+	desiredFn := func(_ context.Context, parent *corev1.ConfigMap) (*appsv1.Deployment, error) {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+				},
+			},
+		}, nil
+	}
+	existing, _ := desiredFn(context.Background(), parent)
+	existing.Spec.Template.Annotations = map[string]string{reconciler.RestartedAtAnnotation: "2024-01-01T00:00:00Z"}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+
+	revision := ""
+	r := FromReconcileFunc(desiredFn).
+		WithNoReference(true).
+		WithRestartTriggerFn(func(context.Context, *corev1.ConfigMap, *appsv1.Deployment) (string, error) {
+			return revision, nil
+		}).
+		Build()
+	restartedAt := func() string {
+		current := &appsv1.Deployment{}
+		require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(existing), current))
+		return current.Spec.Template.Annotations[reconciler.RestartedAtAnnotation]
+	}
+
+	// Without a revision, the existing restart annotation is kept and nothing is updated
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.Equal(t, "2024-01-01T00:00:00Z", restartedAt())
+
+	// The first revision is recorded without restarting
+	revision = "1"
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, "2024-01-01T00:00:00Z", restartedAt())
+
+	// A new revision restarts the workload once, however many times it is reconciled
+	revision = "2"
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	restarted := restartedAt()
+	assert.NotEqual(t, "2024-01-01T00:00:00Z", restarted)
+
+	for range 2 {
+		result, err = r.Reconcile(context.Background(), k8sCli, parent)
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		assert.Equal(t, restarted, restartedAt())
+	}
+}
+
+func TestRestartTriggerUnstructured(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	desiredFn := func(_ context.Context, parent *corev1.ConfigMap) (*unstructured.Unstructured, error) {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": parent.Name, "namespace": parent.Namespace},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "test"}},
+				},
+			},
+		}}, nil
+	}
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "test"},
+			Annotations: map[string]string{reconciler.RestartedAtAnnotation: "2024-01-01T00:00:00Z"},
+		}}},
+	}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+
+	revision := "1"
+	r := FromReconcileFunc(desiredFn).
+		WithDetails(api.Descriptor{Name: "Workload"}).
+		WithNoReference(true).
+		WithRestartTriggerFn(func(context.Context, *corev1.ConfigMap, *unstructured.Unstructured) (string, error) {
+			return revision, nil
+		}).
+		Build()
+	require.NoError(t, r.Validate())
+	restartedAt := func() string {
+		current := &appsv1.Deployment{}
+		require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(existing), current))
+		return current.Spec.Template.Annotations[reconciler.RestartedAtAnnotation]
+	}
+
+	// The first revision is recorded, keeping the existing restart annotation
+	_, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01T00:00:00Z", restartedAt())
+
+	// A new revision restarts the workload once
+	revision = "2"
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	restarted := restartedAt()
+	assert.NotEqual(t, "2024-01-01T00:00:00Z", restarted)
+
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.Equal(t, restarted, restartedAt())
+
+	// A child without a pod template is rejected
+	invalid := FromReconcileFunc(func(context.Context, *corev1.ConfigMap) (*corev1.Secret, error) { return &corev1.Secret{}, nil }).
+		WithDetails(api.Descriptor{Name: "Secret"}).
+		WithRestartTriggerFn(func(context.Context, *corev1.ConfigMap, *corev1.Secret) (string, error) { return "", nil }).
+		Build()
+	require.ErrorIs(t, invalid.Validate(), reconciler.ErrInvalidConfiguration)
+}
+
+func TestChecksumSources(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+	}
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"logLevel": "debug"},
+	}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(config).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*appsv1.Deployment, error) {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+		}, nil
+	}).
+		WithNoReference(true).
+		AddChecksumSource(ChecksumSource[*corev1.ConfigMap]{
+			Name: "config",
+			Kind: conductor.ConfigMapReference,
+			KeyFn: func(parent *corev1.ConfigMap) client.ObjectKey {
+				return client.ObjectKey{Name: parent.Name + "-config", Namespace: parent.Namespace}
+			},
+		}).
+		Build()
+
+	ctx := context.Background()
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "default"}, deployment))
+	checksum := deployment.Spec.Template.Annotations[ChecksumAnnotationPrefix+"config"]
+	assert.Equal(t, reconciler.HashData(config.Data), checksum)
+
+	// Changing the config changes the checksum
+	config.Data["logLevel"] = "info"
+	require.NoError(t, k8sCli.Update(ctx, config))
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "default"}, deployment))
+	assert.NotEqual(t, checksum, deployment.Spec.Template.Annotations[ChecksumAnnotationPrefix+"config"])
+}
+
+func TestRetryPolicy(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	failures := 0
+	createErr := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "app-child", nil)
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if failures > 0 {
+				failures--
+				return createErr
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithNoReference(true).
+		WithRetryPolicy(reconciler.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     reconciler.ExponentialBackoff(time.Millisecond, 5*time.Millisecond),
+		}).
+		Build()
+
+	// Transient errors within the attempts are retried in-process
+	failures = 2
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, 0, failures)
+
+	// Once the attempts are exhausted, the error is returned
+	require.NoError(t, k8sCli.Delete(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default"}}))
+	failures = 3
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	assert.True(t, apierrors.IsConflict(err))
+	assert.Equal(t, 0, failures)
+
+	// Errors that are not retriable are returned right away
+	failures = 2
+	createErr = apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "app-child", assert.AnError)
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Equal(t, 1, failures)
+}
+
+func TestFieldManager(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	var managers []string
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			createOpts := &client.CreateOptions{}
+			createOpts.ApplyOptions(opts)
+			managers = append(managers, createOpts.FieldManager)
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			updateOpts := &client.UpdateOptions{}
+			updateOpts.ApplyOptions(opts)
+			managers = append(managers, updateOpts.FieldManager)
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+
+	value := "v1"
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"value": value},
+		}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Config"}).
+		WithDryRunType(reconciler.DryRunNone).
+		WithFieldManager("maestro-config").
+		Build()
+
+	_, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	value = "v2"
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"maestro-config", "maestro-config"}, managers)
+}
+
+func TestFromDependentFunc(t *testing.T) {
+	type deps struct {
+		Suffix string
+		Now    func() time.Time
+	}
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	ctx := context.Background()
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+
+	projection := func(_ context.Context, parent *corev1.ConfigMap, deps *deps) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-" + deps.Suffix, Namespace: parent.Namespace},
+			Data:       map[string]string{"generated": deps.Now().Format(time.RFC3339)},
+		}, nil
+	}
+	r := FromDependentFunc(projection).
+		WithDetails(api.Descriptor{Name: "Config"}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+
+	// The dependencies provided to the conductor are injected into the function
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cond := conductor.ForParent(parent).
+		WithClient(k8sCli).
+		WithDependencies(conductor.Provide(&deps{Suffix: "config", Now: func() time.Time { return now }})).
+		Build()
+	cond.Register(r)
+	_, err := cond.Conduct(ctx, parent)
+	require.NoError(t, err)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app-config", Namespace: "default"}, child))
+	assert.Equal(t, "2024-01-01T00:00:00Z", child.Data["generated"])
+	assert.True(t, metav1.IsControlledBy(child, parent))
+
+	// Without the dependency, the reconciler fails without creating anything
+	k8sCli = fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, binder.ErrStateNotFound)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "app-config", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestImpersonation(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	ctx := context.Background()
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+
+	var users []string
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			user, _ := reconciler.ImpersonatedUser(ctx)
+			users = append(users, user.UserName)
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Config"}).
+		WithDryRunType(reconciler.DryRunNone).
+		WithImpersonation(types.NamespacedName{Namespace: "system", Name: "config-writer"}).
+		Build()
+
+	require.NotNil(t, r.Impersonator)
+	assert.Equal(t, "system:serviceaccount:system:config-writer", r.Impersonator.User.UserName)
+	assert.Contains(t, r.Impersonator.User.Groups, "system:serviceaccounts:system")
+	assert.ErrorIs(t, reconciler.NewImpersonator(rest.ImpersonationConfig{}).Validate(), reconciler.ErrInvalidConfiguration)
+
+	// The transport impersonates the user bound to the context of the request only
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		headers = append(headers, req.Header.Clone())
+	}))
+	defer server.Close()
+	cfg := &rest.Config{Host: server.URL}
+	reconciler.EnableImpersonation(cfg)
+	require.NoError(t, r.Validate())
+	httpClient, err := rest.HTTPClientFor(cfg)
+	require.NoError(t, err)
+	for _, reqCtx := range []context.Context{r.Impersonator.Impersonate(ctx), ctx} {
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	require.Len(t, headers, 2)
+	assert.Equal(t, "system:serviceaccount:system:config-writer", headers[0].Get("Impersonate-User"))
+	assert.ElementsMatch(t, r.Impersonator.User.Groups, headers[0].Values("Impersonate-Group"))
+	assert.Empty(t, headers[1].Get("Impersonate-User"))
+
+	// Planning goes through the client of the conductor and writes nothing
+	cond := conductor.ForParent(parent).WithClient(k8sCli).Build()
+	cond.Register(r)
+	plan, err := cond.Plan(ctx, parent)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, "create ConfigMap default/app-child (Config)", plan.Changes[0].String())
+	assert.Empty(t, users)
+	err = k8sCli.Get(ctx, client.ObjectKey{Name: "app-child", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Reconciling writes through the client of the conductor, as the impersonated user
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"system:serviceaccount:system:config-writer"}, users)
+}
+
+func TestLiveReads(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	live := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	// The cache hasn't seen the child yet
+	cached := interceptor.NewClient(live, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if key.Name == "app-child" {
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	})
+
+	builder := func() *Builder[*corev1.ConfigMap, *corev1.ConfigMap] {
+		return FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+		}).
+			WithDetails(api.Descriptor{Name: "Child"}).
+			WithDryRunType(reconciler.DryRunNone)
+	}
+
+	// The reader of the conductor is used with LiveReads
+	r := builder().WithLiveReads(true).Build()
+	d := conductor.ForParent(parent).WithClient(cached).WithAPIReader(live).Build()
+	d.Register(r)
+	_, err := d.Conduct(context.Background(), parent)
+	require.NoError(t, err)
+	_, err = d.Conduct(context.Background(), parent)
+	require.NoError(t, err)
+
+	// A stale cache makes the reconciler create the child again
+	_, err = builder().Build().Reconcile(context.Background(), cached, parent)
+	assert.True(t, apierrors.IsAlreadyExists(err))
+
+	// The APIReader doesn't need a conductor
+	_, err = builder().WithAPIReader(live).Build().Reconcile(context.Background(), cached, parent)
+	require.NoError(t, err)
+}
+
+func TestNewChildFn(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			// The current child is read into an empty object, not a copy of the desired one
+			assert.Empty(t, obj.(*corev1.ConfigMap).Data)
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+
+	desiredFn := func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"large": "value"},
+		}, nil
+	}
+	_, err := FromReconcileFunc(desiredFn).WithNoReference(true).WithDryRunType(reconciler.DryRunNone).Build().
+		Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+
+	calls := 0
+	r := FromReconcileFunc(desiredFn).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithNewChildFn(func() *corev1.ConfigMap {
+			calls++
+			return &corev1.ConfigMap{}
+		}).
+		Build()
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDefaultingCache(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	// The API server defaults an annotation the desired child doesn't set
+	defaulted := map[string]string{"defaulted": "true"}
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default", Annotations: defaulted}}
+	dryRuns, updates := 0, 0
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			updateOpts := &client.UpdateOptions{}
+			updateOpts.ApplyOptions(opts)
+			if len(updateOpts.DryRun) > 0 {
+				dryRuns++
+				obj.SetAnnotations(defaulted)
+				return nil
+			}
+			updates++
+			obj.SetAnnotations(defaulted)
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+
+	value := "v1"
+	cache := &reconciler.DefaultingCache{}
+	builder := func(cache *reconciler.DefaultingCache) *Builder[*corev1.ConfigMap, *corev1.ConfigMap] {
+		return FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+				Data:       map[string]string{"value": value},
+			}, nil
+		}).
+			WithDetails(api.Descriptor{Name: "Child"}).
+			WithNoReference(true).
+			WithDryRunType(reconciler.DryRunSilent).
+			WithDefaultingCache(cache)
+	}
+	r := builder(cache).Build()
+
+	// The first reconcile updates the data, the next one dry-runs the defaults
+	_, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updates)
+	dryRuns = 0
+	registry := prometheus.NewRegistry()
+	d := conductor.ForParent(parent).WithClient(k8sCli).WithMetrics(registry).Build()
+	d.Register(r)
+	_, err = d.Conduct(context.Background(), parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dryRuns)
+	assert.Equal(t, 1, updates)
+	assert.Equal(t, 1.0, testutil.ToFloat64(conductor.NewMetrics(registry).DefaultsMismatches.WithLabelValues("Child")))
+
+	// The following reconciles skip the dry-runs
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dryRuns)
+
+	// A change of the desired child is dry-run again
+	value = "v2"
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 4, dryRuns)
+	assert.Equal(t, 2, updates)
+
+	// Expired entries are dry-run again
+	expiring := builder(&reconciler.DefaultingCache{TTL: time.Nanosecond}).Build()
+	dryRuns = 0
+	for range 2 {
+		_, err = expiring.Reconcile(context.Background(), k8sCli, parent)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 4, dryRuns)
+}
+
+func TestConflictRetry(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default"}, Data: map[string]string{"value": "v1"}}
+	conflicts := 0
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if conflicts > 0 {
+				conflicts--
+				return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), assert.AnError)
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+
+	preUpdates := 0
+	builder := func() *Builder[*corev1.ConfigMap, *corev1.ConfigMap] {
+		return FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+				Data:       map[string]string{"value": "v2"},
+			}, nil
+		}).
+			WithNoReference(true).
+			WithDryRunType(reconciler.DryRunNone).
+			WithPreUpdateFn(func(_ context.Context, _ *corev1.ConfigMap, _, child *corev1.ConfigMap) error {
+				preUpdates++
+				assert.NotContains(t, child.Annotations, "pre-updated")
+				child.Annotations = map[string]string{"pre-updated": "true"}
+				return nil
+			})
+	}
+
+	// Conflicts are retried in-process, rebuilding the update from the desired object
+	conflicts = 2
+	result, err := builder().Build().Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, 3, preUpdates)
+	require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(child), child))
+	assert.Equal(t, "v2", child.Data["value"])
+
+	// A single step returns the first conflict
+	child.Data["value"] = "v1"
+	require.NoError(t, k8sCli.Update(context.Background(), child))
+	conflicts = 1
+	_, err = builder().WithConflictBackoff(wait.Backoff{Steps: 1}).Build().Reconcile(context.Background(), k8sCli, parent)
+	assert.True(t, apierrors.IsConflict(err))
+}
+
+func TestUpdateStrategy(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	image := "app:v2"
+	desiredFn := func(_ context.Context, parent *corev1.ConfigMap) (*appsv1.Deployment, error) {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+				},
+			},
+		}, nil
+	}
+
+	// The existing deployment was scaled by an HPA, and a webhook injected a sidecar
+	newExisting := func() *appsv1.Deployment {
+		replicas := int32(5)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{
+						{Name: "app", Image: "app:v1"},
+						{Name: "sidecar", Image: "proxy:v1"},
+					}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		strategy   reconciler.UpdateStrategy
+		containers []string
+	}{
+		{name: "strategic merge patch merges lists", strategy: reconciler.UpdateStrategyStrategicMergePatch, containers: []string{"app:v2", "proxy:v1"}},
+		{name: "merge patch replaces lists", strategy: reconciler.UpdateStrategyMergePatch, containers: []string{"app:v2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := newExisting()
+			k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+			r := FromReconcileFunc(desiredFn).
+				WithNoReference(true).
+				WithUpdateStrategy(tt.strategy).
+				Build()
+
+			result, err := r.Reconcile(context.Background(), k8sCli, parent)
+			require.NoError(t, err)
+			assert.True(t, result.Requeue)
+
+			current := &appsv1.Deployment{}
+			require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(existing), current))
+			assert.Equal(t, int32(5), *current.Spec.Replicas)
+			var images []string
+			for _, container := range current.Spec.Template.Spec.Containers {
+				images = append(images, container.Image)
+			}
+			assert.Equal(t, tt.containers, images)
+
+			// The fields owned by others don't cause further patches
+			result, err = r.Reconcile(context.Background(), k8sCli, parent)
+			require.NoError(t, err)
+			assert.False(t, result.Requeue)
+		})
+	}
+}
+
+func TestAdoptOrphans(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+	desiredFn := func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Secret, error) {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+			StringData: map[string]string{"key": "value"},
+		}, nil
+	}
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		owner    client.Object
+		selector labels.Selector
+		noAdopt  bool
+		adopted  bool
+	}{
+		{name: "orphan is refused by default", noAdopt: true},
+		{name: "orphan is adopted", adopted: true},
+		{name: "orphan matching the selector is adopted", labels: map[string]string{"migrate": "true"},
+			selector: labels.SelectorFromSet(labels.Set{"migrate": "true"}), adopted: true},
+		{name: "orphan not matching the selector is refused", selector: labels.SelectorFromSet(labels.Set{"migrate": "true"})},
+		{name: "child controlled by another owner is refused", owner: other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Labels: tt.labels}}
+			if tt.owner != nil {
+				require.NoError(t, controllerutil.SetControllerReference(tt.owner, existing, s))
+			}
+			k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+
+			r := FromReconcileFunc(desiredFn).
+				WithAdoptOrphans(!tt.noAdopt).
+				WithAdoptSelector(tt.selector).
+				Build()
+
+			_, err := r.Reconcile(context.Background(), k8sCli, parent)
+			current := &corev1.Secret{}
+			require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(existing), current))
+			if !tt.adopted {
+				assert.ErrorIs(t, err, reconciler.ErrNotOwned)
+				assert.False(t, metav1.IsControlledBy(current, parent))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, metav1.IsControlledBy(current, parent))
+			assert.True(t, reconciler.IsAdopted(current))
+		})
+	}
+
+	// A child owned through labels, as a cluster-scoped one, is adopted the same way
+	require.NoError(t, rbacv1.AddToScheme(s))
+	existing := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "app-reader"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+	roleFn := func(_ context.Context, parent *corev1.ConfigMap) (*rbacv1.ClusterRole, error) {
+		return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-reader"}}, nil
+	}
+	_, err := FromReconcileFunc(roleFn).Build().Reconcile(context.Background(), k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrNotOwned)
+	_, err = FromReconcileFunc(roleFn).WithAdoptOrphans(true).Build().Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	current := &rbacv1.ClusterRole{}
+	require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(existing), current))
+	assert.True(t, reconciler.IsOwnedBy(current, parent))
+	assert.True(t, reconciler.IsAdopted(current))
+}
+
+func TestForeignOwnerPolicy(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+	desiredFn := func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Secret, error) {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+			StringData: map[string]string{"key": "value"},
+		}, nil
+	}
+
+	for _, policy := range []reconciler.ForeignOwnerPolicy{reconciler.ForeignOwnerError, reconciler.ForeignOwnerSkip, reconciler.ForeignOwnerAdopt} {
+		t.Run(string(policy), func(t *testing.T) {
+			existing := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+			require.NoError(t, controllerutil.SetControllerReference(other, existing, s))
+			k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(existing).Build()
+
+			state := &conductor.State{}
+			ctx, err := conductor.BindState(context.Background(), state)
+			require.NoError(t, err)
+
+			r := FromReconcileFunc(desiredFn).
+				WithDetails(api.Descriptor{Name: "Secret"}).
+				WithForeignOwnerPolicy(policy).
+				Build()
+			_, err = r.Reconcile(ctx, k8sCli, parent)
+
+			current := &corev1.Secret{}
+			require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(existing), current))
+			condition := state.FindCondition("SecretForeignOwner")
+
+			switch policy {
+			case reconciler.ForeignOwnerError:
+				assert.ErrorIs(t, err, reconciler.ErrForeignOwner)
+				assert.True(t, metav1.IsControlledBy(current, other))
+				require.NotNil(t, condition)
+			case reconciler.ForeignOwnerSkip:
+				assert.NoError(t, err)
+				assert.True(t, metav1.IsControlledBy(current, other))
+				require.NotNil(t, condition)
+			case reconciler.ForeignOwnerAdopt:
+				require.NoError(t, err)
+				assert.True(t, metav1.IsControlledBy(current, parent))
+				assert.True(t, reconciler.IsAdopted(current))
+				assert.Len(t, current.OwnerReferences, 2, "the previous controller is kept as a regular owner")
+				assert.Nil(t, condition)
+			}
+		})
+	}
+}
+
+func TestRecreateOnImmutableChange(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	ctx := context.Background()
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	immutableErr := apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "app-child", field.ErrorList{
+		field.Invalid(field.NewPath("data"), nil, "field is immutable"),
+	})
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if obj.(*corev1.ConfigMap).Data["key"] == "immutable" {
+				return immutableErr
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+
+	value := "initial"
+	builder := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"key": value},
+		}, nil
+	}).WithDryRunType(reconciler.DryRunNone)
+	key := client.ObjectKey{Name: "app-child", Namespace: "default"}
+
+	_, err := builder.Build().Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+
+	// Without the option, the rejected update fails
+	value = "immutable"
+	_, err = builder.Build().Reconcile(ctx, k8sCli, parent)
+	assert.True(t, reconciler.IsImmutableFieldError(err))
+
+	// With it, the child is deleted then created again
+	r := builder.WithRecreateOnImmutableChange(nil).Build()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	err = k8sCli.Get(ctx, key, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, key, child))
+	assert.Equal(t, "immutable", child.Data["key"])
+
+	// The hook detects immutable changes before the update is sent
+	value = "changed"
+	r = builder.WithRecreateOnImmutableChange(func(current, desired *corev1.ConfigMap) bool {
+		return current.Data["key"] != desired.Data["key"]
+	}).Build()
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	err = k8sCli.Get(ctx, key, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Other validation errors are returned
+	assert.False(t, reconciler.IsImmutableFieldError(apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "app-child", field.ErrorList{
+		field.Required(field.NewPath("data"), "required"),
+	})))
+	assert.False(t, reconciler.IsImmutableFieldError(apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "app-child", assert.AnError)))
+}
+
+func TestPreserveFields(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	ctx := context.Background()
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Service, error) {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}, nil
+	}).
+		WithDryRunType(reconciler.DryRunNone).
+		WithPreserveFields(reconciler.PreserveServiceClusterIP, reconciler.PreserveServiceNodePorts).
+		Build()
+
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+
+	// Simulate the allocations of the API server
+	svc := &corev1.Service{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "default"}, svc))
+	svc.Spec.ClusterIP = "10.0.0.10"
+	svc.Spec.ClusterIPs = []string{"10.0.0.10"}
+	svc.Spec.Ports[0].NodePort = 30080
+	require.NoError(t, k8sCli.Update(ctx, svc))
+
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue, "preserved fields should not be updated")
+
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "default"}, svc))
+	assert.Equal(t, "10.0.0.10", svc.Spec.ClusterIP)
+	assert.Equal(t, int32(30080), svc.Spec.Ports[0].NodePort)
+}
+
+func TestChangeDetectionHash(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	ctx := context.Background()
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	dryRuns := 0
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			options := &client.UpdateOptions{}
+			options.ApplyOptions(opts)
+			if len(options.DryRun) > 0 {
+				dryRuns++
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+
+	value := "initial"
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"key": value},
+		}, nil
+	}).WithChangeDetection(reconciler.ChangeDetectionHash).Build()
+	key := client.ObjectKey{Name: "app-child", Namespace: "default"}
+
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, key, child))
+	hash := child.Annotations[reconciler.DesiredHashAnnotation]
+	assert.NotEmpty(t, hash)
+
+	// Changes made by others don't trigger an update while the desired object is unchanged
+	child.Data["other"] = "value"
+	require.NoError(t, k8sCli.Update(ctx, child))
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// Changes to the desired object update the child, without dry-runs
+	value = "changed"
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	require.NoError(t, k8sCli.Get(ctx, key, child))
+	assert.Equal(t, map[string]string{"key": "changed"}, child.Data)
+	assert.NotEqual(t, hash, child.Annotations[reconciler.DesiredHashAnnotation])
+	assert.Zero(t, dryRuns)
+}
+
+func TestDiffRedaction(t *testing.T) {
+	r := FromReconcileFunc(func(_ context.Context, _ *corev1.ConfigMap) (*corev1.Secret, error) {
+		return nil, nil
+	}).WithDiffRenderer(reconciler.YAMLDiffRenderer{}).Build()
+
+	current := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+	desired := current.DeepCopy()
+	desired.Data["password"] = []byte("correct-horse")
+
+	diff := r.diff(current, desired, nil)
+	assert.NotContains(t, diff, "hunter2")
+	assert.NotContains(t, diff, "correct-horse")
+	assert.Contains(t, diff, "-  password:")
+	assert.Contains(t, diff, "+  password:")
+	assert.Equal(t, []byte("hunter2"), current.Data["password"], "the objects should be left untouched")
+
+	// Sensitive fields of other kinds are redacted as well
+	cms := FromReconcileFunc(func(_ context.Context, _ *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return nil, nil
+	}).WithSensitiveFields("data.token").Build()
+	diff = cms.diff(
+		&corev1.ConfigMap{Data: map[string]string{"token": "old-token", "mode": "a"}},
+		&corev1.ConfigMap{Data: map[string]string{"token": "new-token", "mode": "b"}},
+		nil,
+	)
+	assert.NotContains(t, diff, "old-token")
+	assert.NotContains(t, diff, "new-token")
+	assert.Contains(t, diff, "<redacted sha256:")
+	assert.Contains(t, diff, `"b"`)
+}
+
+func TestTimeout(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, _ client.WithWatch, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithNoReference(true).
+		WithTimeout(10 * time.Millisecond).
+		Build()
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, "ChildTimedOut", state.Conditions[0].Type)
+}
+
+func TestMinUpdateInterval(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+
+	value := "initial"
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"key": value},
+		}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithMinUpdateInterval(time.Hour).
+		Build()
+	key := client.ObjectKey{Name: "app-child", Namespace: "default"}
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+
+	// The first update goes through and is timestamped
+	value = "first"
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, key, child))
+	assert.Equal(t, "first", child.Data["key"])
+	_, ok := reconciler.LastUpdated(child)
+	assert.True(t, ok)
+
+	// The timestamp alone is not a change
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+
+	// A second update within the interval is postponed
+	value = "second"
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, 59*time.Minute)
+	require.NoError(t, k8sCli.Get(ctx, key, child))
+	assert.Equal(t, "first", child.Data["key"])
+
+	var types []string
+	for _, c := range state.Conditions {
+		types = append(types, c.Type)
+	}
+	assert.Contains(t, types, "ChildThrottled")
+}
+
+func TestFlapDetector(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"key": "desired"},
+		}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithFlapDetector(&reconciler.FlapDetector{Threshold: 2}).
+		Build()
+	key := client.ObjectKey{Name: "app-child", Namespace: "default"}
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+
+	// Another controller keeps reverting the child
+	revert := func() {
+		child := &corev1.ConfigMap{}
+		require.NoError(t, k8sCli.Get(ctx, key, child))
+		child.Data["key"] = "reverted"
+		require.NoError(t, k8sCli.Update(ctx, child))
+	}
+	for range 2 {
+		revert()
+		result, err := r.Reconcile(ctx, k8sCli, parent)
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+	}
+
+	// The third identical update is not applied
+	revert()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, key, child))
+	assert.Equal(t, "reverted", child.Data["key"])
+
+	var conflicting *metav1.Condition
+	for i, c := range state.Conditions {
+		if c.Type == "ChildConflicting" {
+			conflicting = &state.Conditions[i]
+		}
+	}
+	require.NotNil(t, conflicting)
+	assert.Contains(t, conflicting.Message, "data.key")
+}
+
+func TestBuildValidated(t *testing.T) {
+	fn := func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}
+
+	r, err := FromReconcileFunc(fn).WithDetails(api.Descriptor{Name: "Child"}).BuildValidated()
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	_, err = FromReconcileFunc(fn).BuildValidated()
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+	assert.Panics(t, func() { FromReconcileFunc(fn).MustBuild() })
+
+	// A ShouldDeleteFn requires a ChildKeyFn; Reconcile fails instead of panicking
+	r = FromReconcileFunc(fn).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return true }).
+		Build()
+	require.ErrorIs(t, r.Validate(), reconciler.ErrInvalidConfiguration)
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	_, err = r.Reconcile(context.Background(), fake.NewClientBuilder().Build(), parent)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+
+	r = FromReconcileFunc[*corev1.ConfigMap, *corev1.ConfigMap](nil).WithDetails(api.Descriptor{Name: "Child"}).Build()
+	_, err = r.Reconcile(context.Background(), fake.NewClientBuilder().Build(), parent)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+}
+
+func TestDeleteCollection(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(parent).Build()
+	ctx := context.Background()
+
+	deleting := false
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithDryRunType(reconciler.DryRunNone).
+		WithPredicateFn(func(*corev1.ConfigMap) bool { return !deleting }).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return deleting }).
+		WithDeleteMode(reconciler.DeleteCollection).
+		Build()
+	require.NoError(t, r.Validate())
+
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app-child", Namespace: "default"}, child))
+	assert.Equal(t, reconciler.ApplySetID(parent, "Child"), child.Labels[reconciler.ApplySetLabel])
+
+	// Children created by previous versions of the parent are selected by the same labels
+	previous := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "old-child", Namespace: "default"}}
+	reconciler.NewApplySet(parent, "Child").Add(previous)
+	require.NoError(t, k8sCli.Create(ctx, previous))
+
+	deleting = true
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	list := &corev1.ConfigMapList{}
+	require.NoError(t, k8sCli.List(ctx, list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "app", list.Items[0].Name)
+
+	// Nothing is left to delete
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+}
+
+func TestForegroundDeletion(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	// The finalizer stands for the dependents of the child being removed
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default", Finalizers: []string{"test/dependents"}}}
+	var propagation []metav1.DeletionPropagation
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			deleteOpts := &client.DeleteOptions{}
+			deleteOpts.ApplyOptions(opts)
+			propagation = append(propagation, *deleteOpts.PropagationPolicy)
+			return c.Delete(ctx, obj, opts...)
+		},
+	}).Build()
+	ctx := context.Background()
+
+	calls := 0
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		calls++
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return true }).
+		WithChildKeyFn(func(parent *corev1.ConfigMap) *corev1.ConfigMap {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}
+		}).
+		WithForegroundDeletion(time.Second).
+		Build()
+
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Second}, result)
+	assert.Equal(t, []metav1.DeletionPropagation{metav1.DeletePropagationForeground}, propagation)
+
+	// The child is waited for without deleting it again, nor calling the ReconcileFn
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Second}, result)
+	assert.Len(t, propagation, 1)
+	assert.Zero(t, calls)
+
+	// Once the child is gone, the reconciler proceeds
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(child), child))
+	child.Finalizers = nil
+	require.NoError(t, k8sCli.Update(ctx, child))
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGenerateName(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	ctx := context.Background()
+
+	value := "v1"
+	deleteChild := false
+	childKey := func(parent *corev1.ConfigMap) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{GenerateName: parent.Name + "-run-", Namespace: parent.Namespace}}
+	}
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		child := childKey(parent)
+		child.Data = map[string]string{"value": value}
+		return child, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Run"}).
+		WithDryRunType(reconciler.DryRunNone).
+		WithChildKeyFn(childKey).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return deleteChild }).
+		Build()
+
+	children := func() []corev1.ConfigMap {
+		list := &corev1.ConfigMapList{}
+		require.NoError(t, k8sCli.List(ctx, list))
+		var children []corev1.ConfigMap
+		for _, cm := range list.Items {
+			if cm.Name != "app" {
+				children = append(children, cm)
+			}
+		}
+		return children
+	}
+
+	// The child is created with a generated name, recorded on the parent
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	require.Len(t, children(), 1)
+	name, ok := reconciler.GeneratedName(parent, "Run")
+	require.True(t, ok)
+	assert.Equal(t, children()[0].Name, name)
+	assert.Contains(t, name, "app-run-")
+
+	// The recorded name is used for updates
+	value = "v2"
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	require.Len(t, children(), 1)
+	assert.Equal(t, "v2", children()[0].Data["value"])
+
+	// A child that is gone is created with a new name
+	require.NoError(t, k8sCli.Delete(ctx, &children()[0]))
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	require.Len(t, children(), 1)
+	renamed, _ := reconciler.GeneratedName(parent, "Run")
+	assert.NotEqual(t, name, renamed)
+	assert.Equal(t, children()[0].Name, renamed)
+
+	// The recorded name is used for deletions
+	deleteChild = true
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Empty(t, children())
+}
+
+func TestSkipUnchanged(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 1}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	ctx := context.Background()
+
+	calls := 0
+	inputs := "a"
+	childKey := func(parent *corev1.ConfigMap) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}
+	}
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		calls++
+		child := childKey(parent)
+		child.Data = map[string]string{"inputs": inputs}
+		return child, nil
+	}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithChildKeyFn(childKey).
+		WithSkipUnchanged(func(context.Context, *corev1.ConfigMap) (string, error) {
+			return reconciler.HashData(map[string]string{"inputs": inputs}), nil
+		}).
+		Build()
+
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	child := &corev1.ConfigMap{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(childKey(parent)), child))
+	assert.Equal(t, "1", child.Annotations[reconciler.ParentGenerationAnnotation])
+
+	// The ReconcileFn is skipped while the parent is unchanged
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, 1, calls)
+
+	// A new generation of the parent is reconciled
+	parent.Generation = 2
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// So are new inputs
+	inputs = "b"
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(childKey(parent)), child))
+	assert.Equal(t, "b", child.Data["inputs"])
+
+	// A ChildKeyFn is required
+	assert.ErrorIs(t, FromReconcileFunc(func(context.Context, *corev1.ConfigMap) (*corev1.ConfigMap, error) { return nil, nil }).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithSkipUnchanged(nil).
+		Build().Validate(), reconciler.ErrInvalidConfiguration)
+}
+
+func TestResyncInterval(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithResyncInterval(time.Minute).
+		Build()
+
+	// The creation requeues right away
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+
+	// An up to date child is resynced after the interval
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, result)
+
+	// Within a conductor, the resync is requested from the State
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, time.Minute, state.Resync())
+}
+
+func TestNextSyncFn(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Secret, error) {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-tls", Namespace: parent.Namespace},
+			Data:       map[string][]byte{"expiry": []byte("2h")},
+		}, nil
+	}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithNextSyncFn(func(_ *corev1.ConfigMap, child *corev1.Secret) time.Duration {
+			// Renew a minute before the expiry
+			expiry, err := time.ParseDuration(string(child.Data["expiry"]))
+			require.NoError(t, err)
+			return expiry - time.Minute
+		}).
+		Build()
+
+	// The creation requeues right away
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+
+	// An up to date child is synced before it expires
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: 119 * time.Minute}, result)
+
+	// The smallest delay wins over the resync interval
+	r.ResyncInterval = time.Hour
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Hour}, result)
+
+	// Within a conductor, the delays are requested from the State
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, time.Hour, state.Resync())
+}
+
+func TestPublishFn(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 1}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	childKey := func(parent *corev1.ConfigMap) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace}}
+	}
+	loadBalancerIP := conductor.NewKey[string]("loadBalancerIP")
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Service, error) {
+		return childKey(parent), nil
+	}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithChildKeyFn(childKey).
+		WithPublishFn(PublishField[*corev1.ConfigMap](loadBalancerIP, func(child *corev1.Service) string {
+			if len(child.Status.LoadBalancer.Ingress) == 0 {
+				return ""
+			}
+			return child.Status.LoadBalancer.Ingress[0].IP
+		}), loadBalancerIP).
+		Build()
+	assert.Equal(t, conductor.Contract{Outputs: []conductor.Port{loadBalancerIP}}, r.Contract())
+
+	reconcileInState := func() string {
+		ctx, err := conductor.BindState(context.Background(), &conductor.State{})
+		require.NoError(t, err)
+		_, err = r.Reconcile(ctx, k8sCli, parent)
+		require.NoError(t, err)
+		ip, err := conductor.GetInput(ctx, loadBalancerIP)
+		require.NoError(t, err)
+		return ip
+	}
+
+	// The created child is published
+	assert.Equal(t, "", reconcileInState())
+
+	// So is the up to date child, with its status
+	service := childKey(parent)
+	require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(service), service))
+	service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}}
+	require.NoError(t, k8sCli.Status().Update(context.Background(), service))
+	assert.Equal(t, "192.0.2.10", reconcileInState())
+
+	// And the child skipped as unchanged, read whole after a metadata read
+	r.SkipUnchanged = true
+	r.MetadataReads = true
+	assert.Equal(t, "192.0.2.10", reconcileInState())
+	assert.Equal(t, "192.0.2.10", reconcileInState())
+
+	// Nothing is published without a conductor
+	_, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+}
+
+func TestMetadataReads(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	child := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default"},
+		Data:       map[string]string{"large": "value"},
+	}
+	var reads []client.Object
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			reads = append(reads, obj)
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+
+	childKey := func(parent *corev1.ConfigMap) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}
+	}
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return childKey(parent), nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithChildKeyFn(childKey).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return true }).
+		WithMetadataReads(true).
+		Build()
+
+	// The existence check only reads the metadata of the child, which is deleted
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	require.Len(t, reads, 1)
+	assert.IsType(t, &metav1.PartialObjectMetadata{}, reads[0])
+	assert.True(t, apierrors.IsNotFound(k8sCli.Get(context.Background(), client.ObjectKeyFromObject(child), &corev1.ConfigMap{})))
+
+	// The teardown reads the metadata as well
+	reads = nil
+	result, err = r.Cleanup(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	require.Len(t, reads, 1)
+	assert.IsType(t, &metav1.PartialObjectMetadata{}, reads[0])
+}
+
+func TestDeleteOptions(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default", UID: "child-uid"}}
+	var deleteOpts *client.DeleteOptions
+	var deleteErr error
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			deleteOpts = (&client.DeleteOptions{}).ApplyOptions(opts)
+			if deleteErr != nil {
+				return deleteErr
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+	}).Build()
+	ctx := context.Background()
+
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return true }).
+		WithChildKeyFn(func(parent *corev1.ConfigMap) *corev1.ConfigMap {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}
+		}).
+		WithDeleteOptions(client.GracePeriodSeconds(0), client.PropagationPolicy(metav1.DeletePropagationOrphan)).
+		Build()
+
+	// A child recreated by another actor fails the UID precondition, and is read again on the next reconcile
+	deleteErr = apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "app-child", nil)
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	deleteErr = nil
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	require.NotNil(t, deleteOpts.Preconditions)
+	assert.Equal(t, types.UID("child-uid"), *deleteOpts.Preconditions.UID)
+	assert.Equal(t, int64(0), *deleteOpts.GracePeriodSeconds)
+	assert.Equal(t, metav1.DeletePropagationOrphan, *deleteOpts.PropagationPolicy)
+	assert.True(t, apierrors.IsNotFound(k8sCli.Get(ctx, client.ObjectKeyFromObject(child), child)))
+}
+
+func TestCleanup(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-child", Namespace: "default", Finalizers: []string{"test/dependents"}}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, child).Build()
+	ctx := context.Background()
+
+	builder := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Child"}).
+		WithChildKeyFn(func(parent *corev1.ConfigMap) *corev1.ConfigMap {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}
+		})
+
+	// Without a ShouldDeleteFn, the child is left to the garbage collector
+	result, err := builder.Build().Cleanup(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+
+	r := builder.WithShouldDeleteFn(func(*corev1.ConfigMap) bool { return true }).Build()
+	for range 2 {
+		result, err = r.Cleanup(ctx, k8sCli, parent)
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{RequeueAfter: DefaultDeletionRequeueAfter}, result)
+	}
+
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(child), child))
+	child.Finalizers = nil
+	require.NoError(t, k8sCli.Update(ctx, child))
+	result, err = r.Cleanup(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+}
+
+func TestClusterScopedChild(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, rbacv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	ctx := context.Background()
+
+	childKey := func(parent *corev1.ConfigMap) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: parent.Namespace + "-" + parent.Name}}
+	}
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*rbacv1.ClusterRole, error) {
+		role := childKey(parent)
+		role.Rules = []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+		return role, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Role"}).
+		WithDryRunType(reconciler.DryRunNone).
+		WithChildKeyFn(childKey).
+		Build()
+
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	role := &rbacv1.ClusterRole{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "default-app"}, role))
+	assert.Empty(t, role.OwnerReferences)
+	assert.True(t, reconciler.IsOwnedBy(role, parent))
+
+	// Steady state
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// The garbage collector ignores the child, so the teardown deletes it
+	result, err = r.Cleanup(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDeletionRequeueAfter, result.RequeueAfter)
+	assert.True(t, apierrors.IsNotFound(k8sCli.Get(ctx, client.ObjectKey{Name: "default-app"}, role)))
+	result, err = r.Cleanup(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+}
+
+func TestUnstructuredChild(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	replicas := 2
+	desired := func(_ context.Context, parent *corev1.ConfigMap) (*unstructured.Unstructured, error) {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": parent.Name, "namespace": parent.Namespace},
+			"spec":       map[string]interface{}{"replicas": replicas},
+		}}, nil
+	}
+	r := FromReconcileFunc(desired).WithDetails(api.Descriptor{Name: "Deployment"}).Build()
+	assert.Empty(t, r.Children())
+	key := client.ObjectKey{Name: "app", Namespace: "default"}
+
+	result, err := r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	current := &appsv1.Deployment{}
+	require.NoError(t, k8sCli.Get(context.Background(), key, current))
+	assert.Equal(t, int32(2), *current.Spec.Replicas)
+	assert.True(t, metav1.IsControlledBy(current, parent))
+
+	// Ints returned by the ReconcileFn equal the int64 of the current object
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	replicas = 3
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	require.NoError(t, k8sCli.Get(context.Background(), key, current))
+	assert.Equal(t, int32(3), *current.Spec.Replicas)
+
+	// Field paths ignore fields of unstructured children
+	replicas = 4
+	r = FromReconcileFunc(desired).
+		WithDetails(api.Descriptor{Name: "Deployment"}).
+		AddCompareOpt([]cmp.Option{reconciler.IgnoreUnstructuredFields("spec.replicas")}).
+		Build()
+	result, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	// The kind is required to find the child
+	r = FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*unstructured.Unstructured, error) {
+		child := &unstructured.Unstructured{}
+		child.SetName(parent.Name)
+		return child, nil
+	}).WithDetails(api.Descriptor{Name: "Unknown"}).Build()
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrMissingGVK)
+}
+
+func TestTerminatingNamespace(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	creates := 0
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent, namespace).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			creates++
+			err := apierrors.NewForbidden(corev1.Resource("configmaps"), obj.GetName(), errors.New("namespace default is being terminated"))
+			err.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: corev1.NamespaceTerminatingCause, Field: "default"}}
+			return err
+		},
+	}).Build()
+	builder := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "config"}).
+		WithDryRunType(reconciler.DryRunNone)
+
+	// A rejected creation requeues with the backoff of the controller instead of failing
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err := builder.Build().Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+	assert.Equal(t, 1, creates)
+	condition := state.FindCondition("configNamespaceTerminating")
+	require.NotNil(t, condition)
+	assert.Equal(t, "NamespaceTerminating", condition.Reason)
+
+	// The creation is not attempted while the namespace of the parent is terminating
+	state = &conductor.State{}
+	ctx, err = conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err = builder.WithSkipTerminatingNamespace(true).Build().Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, 1, creates)
+	assert.NotNil(t, state.FindCondition("configNamespaceTerminating"))
+}
+
+func TestAdmissionDenied(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	createErr := apierrors.NewForbidden(corev1.Resource("pods"), "app-child",
+		errors.New("exceeded quota: compute, requested: limits.cpu=2, used: limits.cpu=7, limited: limits.cpu=8"))
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			return createErr
+		},
+	}).Build()
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Pod, error) {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace}}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "worker"}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+	blocked := state.FindCondition("workerBlocked")
+	require.NotNil(t, blocked)
+	assert.Equal(t, "AdmissionDenied", blocked.Reason)
+	assert.Contains(t, blocked.Message, "exceeded quota")
+
+	// Other Forbidden errors, e.g. missing permissions, are returned
+	createErr = apierrors.NewForbidden(corev1.Resource("pods"), "app-child",
+		errors.New(`User "system:serviceaccount:default:controller" cannot create resource "pods"`))
+	_, err = r.Reconcile(context.Background(), k8sCli, parent)
+	assert.True(t, apierrors.IsForbidden(err))
+}
+
+func TestLogSampler(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	value := "a"
+	r := FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name + "-child", Namespace: parent.Namespace},
+			Data:       map[string]string{"value": value},
+		}, nil
+	}).
+		WithNoReference(true).
+		WithDryRunType(reconciler.DryRunNone).
+		WithLogSampler(&reconciler.LogSampler{}).
+		Build()
+
+	noChanges := 0
+	ctx := klog.NewContext(context.Background(), funcr.New(func(_, args string) {
+		if strings.Contains(args, `"msg"="no changes"`) {
+			noChanges++
+		}
+	}, funcr.Options{Verbosity: 1}))
+	reconcileTimes := func(n int) {
+		for range n {
+			_, err := r.Reconcile(ctx, k8sCli, parent)
+			require.NoError(t, err)
+		}
+	}
+
+	// The up to date child is only reported once
+	reconcileTimes(4)
+	assert.Equal(t, 1, noChanges)
+
+	// And again once it changed
+	value = "b"
+	reconcileTimes(3)
+	assert.Equal(t, 2, noChanges)
+}
@@ -0,0 +1,46 @@
+package simple
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// unchanged returns whether the current child was applied for the generation and the inputs of the parent, along with
+// the hash of the inputs and the current child, if any.
+func (r *Reconciler[Parent, Child]) unchanged(ctx context.Context, k8sCli client.Client, parent Parent) (bool, string, client.Object, error) {
+	var inputs string
+	if r.InputsHashFn != nil {
+		var err error
+		if inputs, err = r.InputsHashFn(ctx, parent); err != nil {
+			return false, "", nil, err
+		}
+	}
+
+	current, err := r.getChildKey(ctx, k8sCli, parent)
+	if apierrors.IsNotFound(err) {
+		return false, inputs, nil, nil
+	} else if err != nil {
+		return false, "", nil, err
+	}
+	annotations := current.GetAnnotations()
+	return current.GetDeletionTimestamp().IsZero() &&
+		annotations[reconciler.ParentGenerationAnnotation] == strconv.FormatInt(parent.GetGeneration(), 10) &&
+		annotations[reconciler.InputsHashAnnotation] == inputs, inputs, current, nil
+}
+
+// setInputs annotates the desired child with the generation and the hash of the inputs of the parent.
+func setInputs(parent client.Object, desired client.Object, inputs string) {
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[reconciler.ParentGenerationAnnotation] = strconv.FormatInt(parent.GetGeneration(), 10)
+	if inputs != "" {
+		annotations[reconciler.InputsHashAnnotation] = inputs
+	}
+	desired.SetAnnotations(annotations)
+}
@@ -0,0 +1,57 @@
+package simple
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// throttleWait returns how long to wait before the current child can be updated again, following the
+// MinUpdateInterval. It returns zero if the child can be updated now.
+func (r *Reconciler[Parent, Child]) throttleWait(current Child, now time.Time) time.Duration {
+	updated, ok := reconciler.LastUpdated(current)
+	if !ok {
+		return 0
+	}
+	return max(updated.Add(r.MinUpdateInterval).Sub(now), 0)
+}
+
+// addThrottledCondition adds a `<Name>Throttled` condition to the State, if any.
+func (r *Reconciler[Parent, Child]) addThrottledCondition(ctx context.Context, wait time.Duration) {
+	state, err := conductor.FetchState(ctx)
+	if err != nil {
+		return
+	}
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sThrottled", r.Details.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateThrottled",
+		Message: fmt.Sprintf("updates are limited to one every %s, next update in %s", r.MinUpdateInterval, wait.Round(time.Second)),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
+
+// addConflictingCondition adds a `<Name>Conflicting` condition to the State, if any, listing the fields of the
+// flapping update.
+func (r *Reconciler[Parent, Child]) addConflictingCondition(ctx context.Context, update reconciler.Update) {
+	state, err := conductor.FetchState(ctx)
+	if err != nil {
+		return
+	}
+	state.AddCondition(metav1.Condition{
+		Type:    fmt.Sprintf("%sConflicting", r.Details.Name),
+		Status:  metav1.ConditionTrue,
+		Reason:  "UpdateFlapping",
+		Message: fmt.Sprintf("the same update was applied repeatedly, another controller may be reverting fields: %s", strings.Join(update.Fields, ", ")),
+		LastTransitionTime: metav1.Time{
+			Time: time.Now(),
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// HTTP is a ManifestSource downloading a manifest from a URL.
+type HTTP struct {
+	// URL is the address of the manifest.
+	URL string // required
+	// Digest pins the content of the manifest, e.g. "sha256:4f3c...". A download with another digest fails with
+	// ErrDigestMismatch.
+	Digest string // optional
+	// Header is added to the request, e.g. for authentication.
+	Header http.Header // optional
+	// Client is the client used for the request. Defaults to http.DefaultClient.
+	Client *http.Client // optional
+	// MaxSize is the maximum size of the manifest in bytes; larger downloads fail with ErrTooLarge.
+	// Defaults to DefaultMaxSize.
+	MaxSize int64 // optional
+}
+
+var _ ManifestSource = &HTTP{}
+
+// Fetch downloads the manifest.
+func (h *HTTP) Fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range h.Header {
+		req.Header[key] = values
+	}
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", h.URL, resp.Status)
+	}
+
+	data, err := readAll(resp.Body, maxSizeOrDefault(h.MaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", h.URL, err)
+	}
+	if err := verify(data, h.Digest); err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", h.URL, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,299 @@
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// OCI is a ManifestSource pulling manifests from an artifact of an OCI registry, e.g. pushed with
+// `oras push ghcr.io/acme/manifests:v1 manifests.yaml`.
+//
+// The layers of the artifact are concatenated as a multi-document manifest. Layers whose media type is a tar archive
+// (optionally gzipped) are extracted, keeping their .yaml, .yml and .json files in lexical order. The digest of every
+// layer is verified.
+type OCI struct {
+	// Reference is the artifact, e.g. "ghcr.io/acme/manifests:v1" or "ghcr.io/acme/manifests@sha256:4f3c...".
+	// A reference by digest pins the artifact.
+	Reference string // required
+	// MediaTypes filters the layers to pull by their media type. Defaults to every layer.
+	MediaTypes []string // optional
+	// Archive returns the first layer to pull as is, e.g. the archive of a Helm chart, instead of the concatenated
+	// manifests of the layers.
+	Archive bool // optional
+	// Username and Password authenticate to the registry. Anonymous access is used if empty.
+	Username string // optional
+	Password string // optional
+	// PlainHTTP uses http instead of https, for local registries.
+	PlainHTTP bool // optional
+	// Client is the client used for the requests. Defaults to http.DefaultClient.
+	Client *http.Client // optional
+	// MaxSize is the maximum size in bytes of the registry manifest, of each layer, and of the content of the
+	// artifact once its layers are extracted and concatenated; larger content fails with ErrTooLarge.
+	// Defaults to DefaultMaxSize.
+	MaxSize int64 // optional
+}
+
+var _ ManifestSource = &OCI{}
+
+// ociManifest is the subset of an OCI image manifest used to find the layers.
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// Fetch pulls the layers of the artifact.
+func (o *OCI) Fetch(ctx context.Context) ([]byte, error) {
+	registry, repository, ref, err := parseReference(o.Reference)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := maxSizeOrDefault(o.MaxSize)
+	puller := &ociPuller{oci: o, registry: registry, repository: repository, maxSize: maxSize}
+
+	data, err := puller.get(ctx, "manifests/"+ref, ociManifestMediaType+", "+dockerManifestMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("pulling manifest of %s: %w", o.Reference, err)
+	}
+	if strings.HasPrefix(ref, "sha256:") {
+		if err := verify(data, ref); err != nil {
+			return nil, fmt.Errorf("pulling manifest of %s: %w", o.Reference, err)
+		}
+	}
+	manifest := ociManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest of %s: %w", o.Reference, err)
+	}
+
+	var content bytes.Buffer
+	pulled := 0
+	for _, layer := range manifest.Layers {
+		if len(o.MediaTypes) > 0 && !slices.Contains(o.MediaTypes, layer.MediaType) {
+			continue
+		}
+		blob, err := puller.get(ctx, "blobs/"+layer.Digest, "*/*")
+		if err != nil {
+			return nil, fmt.Errorf("pulling layer %s of %s: %w", layer.Digest, o.Reference, err)
+		}
+		if err := verify(blob, layer.Digest); err != nil {
+			return nil, fmt.Errorf("pulling layer %s of %s: %w", layer.Digest, o.Reference, err)
+		}
+		if o.Archive {
+			return blob, nil
+		}
+		if blob, err = extract(layer.MediaType, blob, maxSize-int64(content.Len())); err != nil {
+			return nil, fmt.Errorf("extracting layer %s of %s: %w", layer.Digest, o.Reference, err)
+		}
+		if int64(content.Len()+len(blob)) > maxSize {
+			return nil, fmt.Errorf("pulling %s: %w: exceeds %d bytes", o.Reference, ErrTooLarge, maxSize)
+		}
+		content.WriteString("\n---\n")
+		content.Write(blob)
+		pulled++
+	}
+	if pulled == 0 {
+		return nil, fmt.Errorf("artifact %s has no layer to pull", o.Reference)
+	}
+	return content.Bytes(), nil
+}
+
+// parseReference splits an artifact reference into its registry, repository, and tag or digest.
+func parseReference(reference string) (string, string, string, error) {
+	registry, rest, ok := strings.Cut(reference, "/")
+	if !ok || !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q, expected <registry>/<repository>[:<tag>|@<digest>]", reference)
+	}
+	if repository, digest, ok := strings.Cut(rest, "@"); ok {
+		return registry, repository, digest, nil
+	}
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		return registry, rest[:i], rest[i+1:], nil
+	}
+	return registry, rest, "latest", nil
+}
+
+// extract returns the manifests of a tar layer, or the layer itself if it isn't an archive. The extracted manifests
+// fail with ErrTooLarge beyond maxSize bytes, so a small compressed layer can't expand without bounds.
+func extract(mediaType string, blob []byte, maxSize int64) ([]byte, error) {
+	if !strings.Contains(mediaType, "tar") {
+		return blob, nil
+	}
+	var reader io.Reader = bytes.NewReader(blob)
+	if strings.HasSuffix(mediaType, "gzip") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	files := map[string][]byte{}
+	remaining := maxSize
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || !slices.Contains([]string{".yaml", ".yml", ".json"}, path.Ext(header.Name)) {
+			continue
+		}
+		if files[header.Name], err = readAll(archive, remaining); err != nil {
+			return nil, err
+		}
+		remaining -= int64(len(files[header.Name]))
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var content bytes.Buffer
+	for _, name := range names {
+		content.WriteString("\n---\n")
+		content.Write(files[name])
+	}
+	return content.Bytes(), nil
+}
+
+// ociPuller sends the requests of a pull to a registry, authenticating with a bearer token when challenged.
+type ociPuller struct {
+	oci        *OCI
+	registry   string
+	repository string
+	token      string
+	maxSize    int64
+}
+
+// get returns the body of a registry API path, relative to the repository.
+func (p *ociPuller) get(ctx context.Context, apiPath, accept string) ([]byte, error) {
+	scheme := "https"
+	if p.oci.PlainHTTP {
+		scheme = "http"
+	}
+	target := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, p.registry, p.repository, apiPath)
+
+	resp, err := p.do(ctx, target, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && p.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if p.token, err = p.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = p.do(ctx, target, accept); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return readAll(resp.Body, p.maxSize)
+}
+
+func (p *ociPuller) do(ctx context.Context, target, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	switch {
+	case p.token != "":
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	case p.oci.Username != "":
+		req.SetBasicAuth(p.oci.Username, p.oci.Password)
+	}
+
+	client := p.oci.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// authenticate requests a bearer token from the realm of the challenge, with the credentials if any.
+func (p *ociPuller) authenticate(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unauthorized, unsupported authentication challenge %q", challenge)
+	}
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		values[key] = strings.Trim(value, `"`)
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || values["realm"] == "" {
+		return "", fmt.Errorf("unauthorized, invalid authentication realm %q", values["realm"])
+	}
+	query := realm.Query()
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = "repository:" + p.repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if p.oci.Username != "" {
+		req.SetBasicAuth(p.oci.Username, p.oci.Password)
+	}
+	client := p.oci.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting token: unexpected status %s", resp.Status)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("requesting token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,126 @@
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ErrDigestMismatch is returned when fetched content doesn't match its pinned digest.
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// ErrTooLarge is returned when fetched content exceeds the maximum size of its source.
+var ErrTooLarge = errors.New("content too large")
+
+// DefaultMaxSize is the maximum size in bytes of the content fetched by a source without a MaxSize, so an oversized
+// or malicious artifact can't exhaust the memory of the operator.
+const DefaultMaxSize = 16 << 20
+
+// ManifestSource fetches manifests (or templates) from outside the operator, e.g. a web server or an OCI registry, so
+// they can be updated without releasing the operator.
+type ManifestSource interface {
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// Func is a function implementing ManifestSource.
+type Func func(ctx context.Context) ([]byte, error)
+
+// Fetch calls the function.
+func (f Func) Fetch(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// Digest returns the digest of the data, in the "sha256:<hex>" format used by OCI registries.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// maxSizeOrDefault returns the maximum size, or DefaultMaxSize if it isn't set.
+func maxSizeOrDefault(maxSize int64) int64 {
+	if maxSize <= 0 {
+		return DefaultMaxSize
+	}
+	return maxSize
+}
+
+// readAll reads the reader until EOF, failing with ErrTooLarge once more than maxSize bytes were read.
+func readAll(reader io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrTooLarge, maxSize)
+	}
+	return data, nil
+}
+
+// verify returns an ErrDigestMismatch error if the data doesn't match the digest. An empty digest matches any data.
+func verify(data []byte, digest string) error {
+	if digest == "" {
+		return nil
+	}
+	if !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("unsupported digest algorithm %q, only sha256 is supported", digest)
+	}
+	if actual := Digest(data); actual != digest {
+		return fmt.Errorf("%w: expected %s, got %s", ErrDigestMismatch, digest, actual)
+	}
+	return nil
+}
+
+// Cache is a ManifestSource caching the content of another one, so it is only fetched once per refresh interval
+// instead of on every reconcile. When a refresh fails, the cached content keeps being served and the error is logged,
+// so an unavailable server doesn't stop the reconcilers.
+type Cache struct {
+	// Source is the cached ManifestSource.
+	Source ManifestSource // required
+	// RefreshInterval is how long the content is served before being fetched again.
+	// With zero, the content is fetched once and never refreshed, which suits sources pinned to a digest.
+	RefreshInterval time.Duration // optional
+
+	mu      sync.Mutex
+	content []byte
+	fetched time.Time
+	now     func() time.Time
+}
+
+// NewCache returns a Cache of the source refreshed every interval.
+func NewCache(source ManifestSource, refreshInterval time.Duration) *Cache {
+	return &Cache{Source: source, RefreshInterval: refreshInterval}
+}
+
+// Fetch returns the cached content, fetching it first if it was never fetched or is due for a refresh.
+func (c *Cache) Fetch(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	if c.content != nil && (c.RefreshInterval == 0 || now.Sub(c.fetched) < c.RefreshInterval) {
+		return c.content, nil
+	}
+
+	content, err := c.Source.Fetch(ctx)
+	if err != nil {
+		if c.content == nil {
+			return nil, err
+		}
+		klog.FromContext(ctx).Error(err, "unable to refresh manifests, serving the cached ones", "fetched", c.fetched)
+		return c.content, nil
+	}
+	c.content = content
+	c.fetched = now
+	return content, nil
+}
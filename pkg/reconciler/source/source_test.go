@@ -0,0 +1,208 @@
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n"
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(manifest))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	src := &HTTP{URL: server.URL, Digest: Digest([]byte(manifest)), Header: http.Header{"Authorization": {"Bearer secret"}}}
+	data, err := src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, string(data))
+
+	src.Digest = Digest([]byte("other"))
+	_, err = src.Fetch(ctx)
+	require.ErrorIs(t, err, ErrDigestMismatch)
+
+	src.Digest = ""
+	src.MaxSize = int64(len(manifest)) - 1
+	_, err = src.Fetch(ctx)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	src.Header = nil
+	_, err = src.Fetch(ctx)
+	require.ErrorContains(t, err, "403")
+}
+
+func TestCache(t *testing.T) {
+	fetches := 0
+	var fetchErr error
+	src := Func(func(context.Context) ([]byte, error) {
+		fetches++
+		return []byte(fmt.Sprint(fetches)), fetchErr
+	})
+	now := time.Now()
+	cache := NewCache(src, time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	data, err := cache.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(data))
+	data, err = cache.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(data))
+
+	now = now.Add(time.Minute)
+	data, err = cache.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(data))
+
+	// The cached content is served while refreshes fail
+	now = now.Add(time.Minute)
+	fetchErr = errors.New("unavailable")
+	data, err = cache.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(data))
+	assert.Equal(t, 3, fetches)
+
+	_, err = NewCache(src, time.Minute).Fetch(ctx)
+	require.Error(t, err)
+}
+
+// registry serves an artifact with a raw YAML layer and a gzipped tar layer, requiring a bearer token.
+func registry(t *testing.T) (*httptest.Server, string) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"b.yaml":    strings.ReplaceAll(manifest, "config", "b"),
+		"a.yaml":    strings.ReplaceAll(manifest, "config", "a"),
+		"README.md": "not a manifest",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	blobs := map[string][]byte{
+		Digest([]byte(manifest)): []byte(manifest),
+		Digest(archive.Bytes()):  archive.Bytes(),
+	}
+	artifact, err := json.Marshal(ociManifest{Layers: []ociDescriptor{
+		{MediaType: "application/yaml", Digest: Digest([]byte(manifest))},
+		{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: Digest(archive.Bytes())},
+	}})
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:acme/manifests:pull", r.URL.Query().Get("scope"))
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "pull-token"})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/acme/manifests/manifests/v1", r.URL.Path == "/v2/acme/manifests/manifests/"+Digest(artifact):
+			_, _ = w.Write(artifact)
+		case strings.HasPrefix(r.URL.Path, "/v2/acme/manifests/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/acme/manifests/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, Digest(artifact)
+}
+
+func TestOCI(t *testing.T) {
+	server, digest := registry(t)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	ctx := context.Background()
+
+	src := &OCI{Reference: host + "/acme/manifests:v1", PlainHTTP: true}
+	data, err := src.Fetch(ctx)
+	require.NoError(t, err)
+	objects, err := reconciler.DecodeManifests(data)
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, []string{"config", "a", "b"}, []string{objects[0].GetName(), objects[1].GetName(), objects[2].GetName()})
+
+	// Pinned by digest
+	src = &OCI{Reference: host + "/acme/manifests@" + digest, PlainHTTP: true, MediaTypes: []string{"application/yaml"}}
+	data, err = src.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "\n---\n"+manifest, string(data))
+
+	// The first layer, as is
+	src = &OCI{Reference: host + "/acme/manifests:v1", PlainHTTP: true, MediaTypes: []string{"application/vnd.oci.image.layer.v1.tar+gzip"}, Archive: true}
+	data, err = src.Fetch(ctx)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	header, err := tar.NewReader(gz).Next()
+	require.NoError(t, err)
+	assert.Contains(t, []string{"a.yaml", "b.yaml", "README.md"}, header.Name)
+
+	src = &OCI{Reference: host + "/acme/manifests@" + Digest([]byte("other")), PlainHTTP: true}
+	_, err = src.Fetch(ctx)
+	require.Error(t, err)
+
+	// The artifact is larger than the maximum size
+	src = &OCI{Reference: host + "/acme/manifests:v1", PlainHTTP: true, MaxSize: 100}
+	_, err = src.Fetch(ctx)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = (&OCI{Reference: "manifests:v1"}).Fetch(ctx)
+	require.ErrorContains(t, err, "invalid OCI reference")
+}
+
+func TestExtractMaxSize(t *testing.T) {
+	// A small compressed layer expanding to a large manifest
+	large := strings.Repeat(" ", 1<<20)
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "large.yaml", Mode: 0o644, Size: int64(len(large)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte(large))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.Less(t, archive.Len(), 64<<10)
+
+	_, err = extract("application/vnd.oci.image.layer.v1.tar+gzip", archive.Bytes(), 64<<10)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	data, err := extract("application/vnd.oci.image.layer.v1.tar+gzip", archive.Bytes(), DefaultMaxSize)
+	require.NoError(t, err)
+	assert.Len(t, data, len(large)+len("\n---\n"))
+}
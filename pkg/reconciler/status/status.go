@@ -0,0 +1,84 @@
+package status
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler (StatusReconciler) computes the status of the parent itself, e.g. its replicas, endpoints or phase, and
+// patches the status subresource of the parent when it changed. The status conditions are left to the status
+// condition handler of the conductor.
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	Details api.Descriptor // required
+	// StatusFn sets the desired status on the parent. The parent passed in is a copy of the current object, freshly
+	// read, only changes to its status are applied. The conductor State is available from the context, e.g. to read
+	// the values published by earlier reconcilers.
+	StatusFn func(ctx context.Context, parent Parent) error // required
+	// PredicateFn is a function that returns true if the StatusFn should be called.
+	// If nil, the StatusFn will always be called.
+	PredicateFn func(parent Parent) bool // optional
+	// CompareOpts are additional options to use when comparing the current status to the desired status.
+	CompareOpts []cmp.Option // optional
+}
+
+var _ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+
+// Reconcile computes the desired status of the parent and patches the status subresource if it changed.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// doReconcile re-reads the parent and computes its status until the patch doesn't conflict, the patch being guarded by
+// the resource version of the parent read. The parent is then updated with the patched object.
+func (r *Reconciler[Parent]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent))
+
+	compareOpts := append(r.CompareOpts, reconciler.OnlyStatusFields())
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := parent.DeepCopyObject().(Parent)
+		if err := k8sCli.Get(ctx, client.ObjectKeyFromObject(parent), current); err != nil {
+			return err
+		}
+
+		desired := current.DeepCopyObject().(Parent)
+		if err := r.StatusFn(ctx, desired); err != nil {
+			return err
+		}
+		if cmp.Equal(current, desired, compareOpts...) {
+			log.Info("no status changes")
+			return nil
+		}
+
+		patch := client.MergeFromWithOptions(current, client.MergeFromWithOptimisticLock{})
+		if err := k8sCli.Status().Patch(ctx, desired, patch); err != nil {
+			return err
+		}
+		log.Info("updated parent status")
+		reflect.ValueOf(parent).Elem().Set(reflect.ValueOf(desired).Elem())
+		return nil
+	})
+	return reconcile.Result{}, err
+}
@@ -0,0 +1,50 @@
+package status
+
+import (
+	"context"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type StatusFn[Parent client.Object] func(ctx context.Context, parent Parent) error
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// FromStatusFunc returns a new instance of Builder for the StatusFn.
+func FromStatusFunc[Parent client.Object](fn StatusFn[Parent]) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			StatusFn:    fn,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+		},
+	}
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// AddCompareOpt adds a comparator option to the reconciler
+func (b *Builder[Parent]) AddCompareOpt(compareOpts []cmp.Option) *Builder[Parent] {
+	b.reconciler.CompareOpts = append(b.reconciler.CompareOpts, compareOpts...)
+	return b
+}
+
+// WithDetails sets the Details field.
+func (b *Builder[Parent]) WithDetails(details api.Descriptor) *Builder[Parent] {
+	b.reconciler.Details = details
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
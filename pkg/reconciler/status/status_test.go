@@ -0,0 +1,76 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestParentStatusPatch(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	parent := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+	}
+	patches := 0
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).WithStatusSubresource(parent).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				if patches == 1 {
+					// The parent changes concurrently, the patch conflicts.
+					concurrent := &corev1.Pod{}
+					require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(obj), concurrent))
+					concurrent.Labels = map[string]string{"changed": "true"}
+					require.NoError(t, c.Update(ctx, concurrent))
+				}
+				return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	require.NoError(t, k8sCli.Get(context.Background(), client.ObjectKeyFromObject(parent), parent))
+
+	state := &conductor.State{}
+	conductor.SetValue(state, "endpoint", "10.0.0.1")
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+
+	reconciler := FromStatusFunc(func(ctx context.Context, parent *corev1.Pod) error {
+		state, err := conductor.FetchState(ctx)
+		if err != nil {
+			return err
+		}
+		endpoint, _ := conductor.GetValue[string](state, "endpoint")
+		parent.Spec.NodeName = "ignored"
+		parent.Status.PodIP = endpoint
+		parent.Status.Phase = corev1.PodRunning
+		return nil
+	}).Build()
+
+	_, err = reconciler.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, patches, "the conflicting patch should be retried")
+	assert.Equal(t, "10.0.0.1", parent.Status.PodIP, "the parent should hold the patched status")
+	assert.Equal(t, "true", parent.Labels["changed"])
+
+	updated := &corev1.Pod{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKeyFromObject(parent), updated))
+	assert.Equal(t, corev1.PodRunning, updated.Status.Phase)
+	assert.Equal(t, "node-a", updated.Spec.NodeName, "spec must not be written by the status reconciler")
+
+	// A second reconcile is a no-op
+	_, err = reconciler.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, 2, patches)
+}
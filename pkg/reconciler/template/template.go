@@ -0,0 +1,165 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrEmptyManifest is returned when the template renders an empty manifest.
+var ErrEmptyManifest = errors.New("template rendered an empty manifest")
+
+// Reconciler (TemplateReconciler) renders a Go template of a YAML (or JSON) manifest into a child object and manages
+// it like a simple.Reconciler, for children that are mostly static manifests.
+//
+// The child is rendered as an *unstructured.Unstructured, so its kind doesn't need to be registered in the scheme.
+// The manifest must set the apiVersion, kind, name and namespace (if any) of the child.
+type Reconciler[Parent client.Object] struct {
+	// Details is the descriptor for the reconciler.
+	// It should contain the name and description of the reconciler for documentation and debugging purposes.
+	// Set the ChildGVKs to the kind of the manifest for the conductor to watch the child.
+	Details api.Descriptor // required
+	// Template renders the manifest of the child, with the value returned by DataFn.
+	Template *template.Template // required unless Source is set
+	// Source fetches the text of the template instead, e.g. from an OCI artifact, so it can be updated without
+	// releasing the operator. The text is parsed with the Funcs, again whenever it changes.
+	Source source.ManifestSource // optional
+	// DataFn returns the data the Template is executed with.
+	// If nil, the Template is executed with the parent.
+	DataFn func(ctx context.Context, parent Parent) (any, error) // optional
+	// PredicateFn is a function that returns true if the child should be reconciled.
+	// If nil, the child will always be reconciled.
+	PredicateFn func(parent Parent) bool // optional
+	// NoReference optionally disables setting the owner reference on the child object.
+	NoReference bool // optional
+	// DryRunType configures the dry-run behavior of the reconciler.
+	DryRunType reconciler.DryRunType // optional
+	// CompareOpts are the options to use when comparing the child object to the desired state, such as
+	// reconciler.IgnoreUnstructuredFields.
+	CompareOpts []cmp.Option // optional
+	// PreUpdateFn is a function that is called before an existing child object is compared and updated.
+	PreUpdateFn func(ctx context.Context, parent Parent, current, desired *unstructured.Unstructured) error // optional
+
+	mu         sync.Mutex
+	sourceText string
+	parsed     *template.Template
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object]{}
+)
+
+// Reconcile renders the manifest for the parent, then creates or updates the child.
+func (r *Reconciler[Parent]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.doReconcile(ctx, k8sCli, parent)
+	conductor.RecordResult(ctx, r.Details.Name, result, err)
+	return result, err
+}
+
+// Describe returns the descriptor for the reconciler.
+func (r *Reconciler[Parent]) Describe() api.Descriptor {
+	return r.Details
+}
+
+// Validate returns an ErrInvalidConfiguration error if the Details name, or both the Template and the Source, are
+// missing.
+func (r *Reconciler[Parent]) Validate() error {
+	var errs []error
+	if r.Details.Name == "" {
+		errs = append(errs, fmt.Errorf("%w: the Details name of the template reconciler is empty", reconciler.ErrInvalidConfiguration))
+	}
+	if r.Template == nil && r.Source == nil {
+		errs = append(errs, fmt.Errorf("%w: %s has no Template or Source", reconciler.ErrInvalidConfiguration, r.Details.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// Render renders the manifest of the child for the parent.
+func (r *Reconciler[Parent]) Render(ctx context.Context, parent Parent) (*unstructured.Unstructured, error) {
+	tmpl, err := r.template(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data any = parent
+	if r.DataFn != nil {
+		if data, err = r.DataFn(ctx, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	var manifest bytes.Buffer
+	if err := tmpl.Execute(&manifest, data); err != nil {
+		return nil, fmt.Errorf("rendering template %s: %w", tmpl.Name(), err)
+	}
+	if strings.TrimSpace(manifest.String()) == "" {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyManifest, tmpl.Name())
+	}
+
+	child := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(manifest.Bytes(), &child.Object); err != nil {
+		return nil, fmt.Errorf("decoding manifest of template %s: %w", tmpl.Name(), err)
+	}
+	return child, nil
+}
+
+// template returns the Template, or the template fetched from the Source, parsed again only when its text changed.
+func (r *Reconciler[Parent]) template(ctx context.Context) (*template.Template, error) {
+	if r.Source == nil {
+		return r.Template, nil
+	}
+	text, err := r.Source.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.parsed == nil || r.sourceText != string(text) {
+		parsed, err := template.New(r.Details.Name).Funcs(Funcs()).Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %w", err)
+		}
+		r.parsed, r.sourceText = parsed, string(text)
+	}
+	return r.parsed, nil
+}
+
+func (r *Reconciler[Parent]) doReconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	if r.Template == nil && r.Source == nil {
+		return reconcile.Result{}, r.Validate()
+	}
+	if r.PredicateFn != nil && !r.PredicateFn(parent) {
+		return reconcile.Result{}, nil
+	}
+
+	desired, err := r.Render(ctx, parent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	applier := &simple.Reconciler[Parent, *unstructured.Unstructured]{
+		Details:     r.Details,
+		NoReference: r.NoReference,
+		DryRunType:  r.DryRunType,
+		CompareOpts: r.CompareOpts,
+		PreUpdateFn: r.PreUpdateFn,
+	}
+	return applier.Apply(ctx, k8sCli, parent, desired)
+}
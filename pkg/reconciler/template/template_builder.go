@@ -0,0 +1,108 @@
+package template
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object] struct {
+	reconciler Reconciler[Parent]
+}
+
+// FromTemplate returns a new instance of Builder rendering the template.
+func FromTemplate[Parent client.Object](tmpl *template.Template) *Builder[Parent] {
+	return &Builder[Parent]{
+		reconciler: Reconciler[Parent]{
+			Template:    tmpl,
+			PredicateFn: reconciler.IsNotMarkedForDeletion[Parent],
+			DryRunType:  reconciler.DryRunWarn,
+		},
+	}
+}
+
+// FromText returns a new instance of Builder rendering the template text, parsed with the Funcs. It panics if the
+// text can't be parsed, like template.Must, as templates are usually static.
+func FromText[Parent client.Object](name, text string) *Builder[Parent] {
+	return FromTemplate[Parent](template.Must(template.New(name).Funcs(Funcs()).Parse(text)))
+}
+
+// FromSource returns a new instance of Builder rendering the template text fetched from the source, parsed with the
+// Funcs. Wrap the source in a source.Cache to avoid fetching it on every reconcile.
+func FromSource[Parent client.Object](src source.ManifestSource) *Builder[Parent] {
+	b := FromTemplate[Parent](nil)
+	b.reconciler.Source = src
+	return b
+}
+
+// Funcs returns the functions available to the templates parsed by FromText:
+//   - toYaml renders a value as YAML, e.g. to embed a part of the parent spec
+//   - indent indents every line of a string by a number of spaces
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"toYaml": func(v any) (string, error) {
+			data, err := yaml.Marshal(v)
+			return strings.TrimSuffix(string(data), "\n"), err
+		},
+		"indent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+	}
+}
+
+// WithDataFn sets the DataFn field.
+func (b *Builder[Parent]) WithDataFn(fn func(ctx context.Context, parent Parent) (any, error)) *Builder[Parent] {
+	b.reconciler.DataFn = fn
+	return b
+}
+
+// WithPredicateFn sets the PredicateFn field.
+func (b *Builder[Parent]) WithPredicateFn(predicate func(parent Parent) bool) *Builder[Parent] {
+	b.reconciler.PredicateFn = predicate
+	return b
+}
+
+// WithNoReference sets the NoReference field.
+func (b *Builder[Parent]) WithNoReference(noReference bool) *Builder[Parent] {
+	b.reconciler.NoReference = noReference
+	return b
+}
+
+// WithDryRunType configures the dry-run behavior of the reconciler.
+func (b *Builder[Parent]) WithDryRunType(dryRunType reconciler.DryRunType) *Builder[Parent] {
+	b.reconciler.DryRunType = dryRunType
+	return b
+}
+
+// AddCompareOpt adds a comparator option to the reconciler
+func (b *Builder[Parent]) AddCompareOpt(compareOpts []cmp.Option) *Builder[Parent] {
+	b.reconciler.CompareOpts = append(b.reconciler.CompareOpts, compareOpts...)
+	return b
+}
+
+// WithDetails sets the Details field.
+func (b *Builder[Parent]) WithDetails(details api.Descriptor) *Builder[Parent] {
+	b.reconciler.Details = details
+	return b
+}
+
+// WithPreUpdateFn sets the PreUpdateFn field.
+func (b *Builder[Parent]) WithPreUpdateFn(fn func(ctx context.Context, parent Parent, current, desired *unstructured.Unstructured) error) *Builder[Parent] {
+	b.reconciler.PreUpdateFn = fn
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent]) Build() *Reconciler[Parent] {
+	return &b.reconciler
+}
@@ -0,0 +1,126 @@
+package template
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const deploymentTemplate = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+{{ toYaml .Labels | indent 4 }}
+spec:
+  replicas: {{ index .Data "replicas" }}
+  selector:
+    matchLabels:
+      app: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Name }}
+    spec:
+      containers:
+      - name: app
+        image: nginx
+`
+
+func TestTemplateReconciler(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "parent-uid", Labels: map[string]string{"team": "web"}},
+		Data:       map[string]string{"replicas": "2"},
+	}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).WithObjects(parent).Build()
+	r := FromText[*corev1.ConfigMap]("deployment", deploymentTemplate).
+		WithDetails(api.Descriptor{Name: "Deployment"}).
+		Build()
+	require.NoError(t, r.Validate())
+
+	ctx := context.Background()
+	result, err := r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	child := &appsv1.Deployment{}
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "default"}, child))
+	assert.Equal(t, int32(2), *child.Spec.Replicas)
+	assert.Equal(t, "web", child.Labels["team"])
+	assert.True(t, metav1.IsControlledBy(child, parent))
+
+	// Steady state
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	parent.Data["replicas"] = "3"
+	result, err = r.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	require.NoError(t, k8sCli.Get(ctx, client.ObjectKey{Name: "app", Namespace: "default"}, child))
+	assert.Equal(t, int32(3), *child.Spec.Replicas)
+}
+
+func TestTemplateReconcilerErrors(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	k8sCli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	r := FromText[*corev1.ConfigMap]("empty", `{{ if false }}kind: ConfigMap{{ end }}`).
+		WithDetails(api.Descriptor{Name: "Empty"}).
+		Build()
+	_, err := r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, ErrEmptyManifest)
+
+	r = FromText[*corev1.ConfigMap]("no-kind", "metadata:\n  name: {{ .Name }}\n").
+		WithDetails(api.Descriptor{Name: "NoKind"}).
+		Build()
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrMissingGVK)
+
+	r = FromTemplate[*corev1.ConfigMap](nil).Build()
+	require.ErrorIs(t, r.Validate(), reconciler.ErrInvalidConfiguration)
+	_, err = r.Reconcile(ctx, k8sCli, parent)
+	require.ErrorIs(t, err, reconciler.ErrInvalidConfiguration)
+
+	assert.Panics(t, func() { FromText[*corev1.ConfigMap]("invalid", "{{ .Name ") })
+}
+
+func TestTemplateSource(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	text := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Name }}-config\n"
+	r := FromSource[*corev1.ConfigMap](source.Func(func(context.Context) ([]byte, error) {
+		return []byte(text), nil
+	})).
+		WithDetails(api.Descriptor{Name: "Config"}).
+		Build()
+	require.NoError(t, r.Validate())
+
+	child, err := r.Render(context.Background(), parent)
+	require.NoError(t, err)
+	assert.Equal(t, "app-config", child.GetName())
+
+	// The template is parsed again when its text changes
+	text = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Name }}-settings\n"
+	child, err = r.Render(context.Background(), parent)
+	require.NoError(t, err)
+	assert.Equal(t, "app-settings", child.GetName())
+}
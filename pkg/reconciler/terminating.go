@@ -0,0 +1,25 @@
+package reconciler
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsNamespaceTerminating reports whether the error is the Forbidden API error returned when creating an object in a
+// namespace being deleted. Retrying the creation fails the same way until the namespace is gone.
+func IsNamespaceTerminating(err error) bool {
+	return apierrors.IsForbidden(err) && apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause)
+}
+
+// NamespaceTerminating returns whether the named namespace is being deleted. A namespace not found is not considered
+// terminating, as objects can't be created in it either way.
+func NamespaceTerminating(ctx context.Context, reader client.Reader, name string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return !namespace.DeletionTimestamp.IsZero() || namespace.Status.Phase == corev1.NamespaceTerminating, nil
+}
@@ -0,0 +1,48 @@
+package reconciler
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LastUpdatedAnnotation holds the time of the last update of a child by its reconciler, to rate limit updates.
+const LastUpdatedAnnotation = AnnotationPrefix + "last-updated"
+
+// LastUpdated returns the time held by the LastUpdatedAnnotation of the object, if any.
+func LastUpdated(obj client.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[LastUpdatedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	updated, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return updated, true
+}
+
+// SetLastUpdated sets the LastUpdatedAnnotation of the object.
+func SetLastUpdated(obj client.Object, updated time.Time) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastUpdatedAnnotation] = updated.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+// CarryLastUpdated copies the LastUpdatedAnnotation from the current object onto the desired one, so it doesn't show
+// as a change.
+func CarryLastUpdated(current, desired client.Object) {
+	value, ok := current.GetAnnotations()[LastUpdatedAnnotation]
+	if !ok {
+		return
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastUpdatedAnnotation] = value
+	desired.SetAnnotations(annotations)
+}
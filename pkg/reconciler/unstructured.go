@@ -0,0 +1,117 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrMissingGVK is returned when an unstructured object has no apiVersion or kind, which are required to find it.
+var ErrMissingGVK = errors.New("unstructured object has no apiVersion or kind")
+
+var unstructuredType = reflect.TypeOf(&unstructured.Unstructured{})
+
+// NormalizeUnstructured prepares an unstructured object built by a reconciler to be compared with the objects returned
+// by the API server: its numbers are converted to int64 and float64, as decoded from JSON, so an int replica count
+// equals the int64 of the current object. It returns ErrMissingGVK if the object has no apiVersion or kind. Other
+// objects are left untouched.
+func NormalizeUnstructured(obj client.Object) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	if gvk := u.GroupVersionKind(); gvk.Version == "" || gvk.Kind == "" {
+		return fmt.Errorf("%w: %s/%s", ErrMissingGVK, u.GetNamespace(), u.GetName())
+	}
+
+	data, err := json.Marshal(u.Object)
+	if err != nil {
+		return err
+	}
+	content := map[string]interface{}{}
+	if err := utiljson.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	u.Object = content
+	return nil
+}
+
+// IgnoreUnstructuredFields ignores fields of unstructured objects by their dotted path, e.g. "spec.replicas" or
+// "metadata.annotations". A path also ignores the fields nested under it. List indexes are not part of the paths, so
+// "spec.template.spec.containers.image" ignores the image of every container. Typed objects are not affected, use
+// struct paths such as IgnoreAnnotations for them.
+func IgnoreUnstructuredFields(paths ...string) cmp.Option {
+	return cmp.FilterPath(func(p cmp.Path) bool {
+		if len(p) == 0 || p.Index(0).Type() != unstructuredType {
+			return false
+		}
+		var keys []string
+		for _, step := range p {
+			if index, ok := step.(cmp.MapIndex); ok {
+				keys = append(keys, fmt.Sprint(index.Key()))
+			}
+		}
+		path := strings.Join(keys, ".")
+		for _, ignored := range paths {
+			if path == ignored || strings.HasPrefix(path, ignored+".") {
+				return true
+			}
+		}
+		return false
+	}, cmp.Ignore())
+}
+
+// UnstructuredDefaults ignores the fields of unstructured objects that are not part of their desired state, the
+// counterpart of IgnoreManagedFields and IgnoreStatusFields. The apiVersion and kind are compared, so a change of
+// version is detected.
+func UnstructuredDefaults() cmp.Option {
+	return IgnoreUnstructuredFields("metadata.managedFields", "status")
+}
+
+// MergeUnstructured completes an unstructured desired object with the fields of the current object it doesn't set,
+// such as the defaults and the status set by the API server, which unstructured objects can't compare as zero values.
+// Nested maps are merged, as are the elements of lists of the same length (e.g. the defaults of containers); other
+// values of desired win. Fields removed from desired are therefore left on the object; set them to nil to clear them.
+// Other objects are left untouched.
+func MergeUnstructured(current, desired client.Object) {
+	c, ok := current.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	if d, ok := desired.(*unstructured.Unstructured); ok {
+		d.Object = mergeMaps(c.DeepCopy().Object, d.Object)
+	}
+}
+
+// mergeMaps sets the values of src on dst, merging nested maps and lists, and returns dst.
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	for key, value := range src {
+		dst[key] = mergeValues(dst[key], value)
+	}
+	return dst
+}
+
+// mergeValues returns src merged onto dst.
+func mergeValues(dst, src interface{}) interface{} {
+	switch src := src.(type) {
+	case map[string]interface{}:
+		if dst, ok := dst.(map[string]interface{}); ok {
+			return mergeMaps(dst, src)
+		}
+	case []interface{}:
+		if dst, ok := dst.([]interface{}); ok && len(dst) == len(src) {
+			for i := range src {
+				dst[i] = mergeValues(dst[i], src[i])
+			}
+			return dst
+		}
+	}
+	return src
+}
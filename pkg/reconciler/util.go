@@ -1,7 +1,33 @@
 package reconciler
 
-import "sigs.k8s.io/controller-runtime/pkg/client"
+import (
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
 
 func IsNotMarkedForDeletion[T client.Object](obj T) bool {
 	return obj.GetDeletionTimestamp() == nil
 }
+
+// NewObject returns a new, empty object of type T, which must be a pointer to a struct (e.g. *corev1.ConfigMap).
+// It panics if T is an interface type.
+func NewObject[T client.Object]() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
+
+// MergeResults combines two reconcile results, requeueing if either requeues and keeping the shortest RequeueAfter.
+func MergeResults(a, b reconcile.Result) reconcile.Result {
+	merged := reconcile.Result{Requeue: a.Requeue || b.Requeue}
+	switch {
+	case a.RequeueAfter == 0:
+		merged.RequeueAfter = b.RequeueAfter
+	case b.RequeueAfter == 0:
+		merged.RequeueAfter = a.RequeueAfter
+	default:
+		merged.RequeueAfter = min(a.RequeueAfter, b.RequeueAfter)
+	}
+	return merged
+}
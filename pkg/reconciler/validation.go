@@ -0,0 +1,7 @@
+package reconciler
+
+import "errors"
+
+// ErrInvalidConfiguration is returned when a reconciler or conductor is missing required configuration, such as its
+// ReconcileFn or client.
+var ErrInvalidConfiguration = errors.New("invalid configuration")
@@ -0,0 +1,137 @@
+package waitfor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultRequeueAfter is the delay used to requeue the parent while waiting for the child to be ready.
+const DefaultRequeueAfter = 5 * time.Second
+
+// Reconciler (WaitForReconciler) wraps the reconciler of a child and, once it ensured the child exists, only succeeds
+// when the child is ready. Until then the parent is requeued, so the reconcilers registered after it in a conductor
+// don't run before the child is up.
+type Reconciler[Parent client.Object, Child client.Object] struct {
+	// Inner is the reconciler ensuring the child exists, typically a simple reconciler.
+	Inner api.Reconciler[Parent] // required
+	// ChildKeyFn returns the child object with only a key (name and namespace) set.
+	ChildKeyFn func(Parent) Child // required
+	// ReadyFn returns true if the child is ready. Defaults to reconciler.IsReady: Deployments must be Available, Jobs
+	// Complete, and other objects report a Ready condition.
+	ReadyFn func(child Child) bool // optional
+	// RequeueAfter is the delay used to requeue the parent while waiting. Defaults to DefaultRequeueAfter.
+	RequeueAfter time.Duration // optional
+}
+
+var (
+	_ api.Reconciler[client.Object] = &Reconciler[client.Object, client.Object]{}
+	_ api.Validator                 = &Reconciler[client.Object, client.Object]{}
+)
+
+// Reconcile runs the inner reconciler, then fetches the child and checks its readiness. If the child is not ready, a
+// `<Name>Progressing` condition is added to the conductor State and the parent is requeued.
+func (r *Reconciler[Parent, Child]) Reconcile(ctx context.Context, k8sCli client.Client, parent Parent) (reconcile.Result, error) {
+	result, err := r.Inner.Reconcile(ctx, k8sCli, parent)
+	if err != nil || result.Requeue || result.RequeueAfter > 0 {
+		return result, err
+	}
+
+	child := r.ChildKeyFn(parent)
+	key := client.ObjectKeyFromObject(child)
+	log := klog.FromContext(ctx).V(1).
+		WithValues("parent", client.ObjectKeyFromObject(parent), "child", key)
+
+	reason, message, err := r.check(ctx, k8sCli, child)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if reason == "" {
+		return result, nil
+	}
+
+	log.Info("waiting for child to be ready", "reason", reason, "message", message)
+	if state, err := conductor.FetchState(ctx); err == nil {
+		state.AddCondition(metav1.Condition{
+			Type:    fmt.Sprintf("%sProgressing", r.Describe().Name),
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+			LastTransitionTime: metav1.Time{
+				Time: time.Now(),
+			},
+		})
+	}
+
+	return reconcile.Result{
+		RequeueAfter: r.requeueAfter(),
+	}, nil
+}
+
+// Describe returns the descriptor of the inner reconciler.
+func (r *Reconciler[Parent, Child]) Describe() api.Descriptor {
+	return r.Inner.Describe()
+}
+
+// Children returns the children of the inner reconciler, if it declares any.
+func (r *Reconciler[Parent, Child]) Children() []client.Object {
+	if describer, ok := r.Inner.(api.ChildDescriber); ok {
+		return describer.Children()
+	}
+	return nil
+}
+
+// Validate checks that the ChildKeyFn is set, then validates the inner reconciler if it implements api.Validator.
+func (r *Reconciler[Parent, Child]) Validate() error {
+	if r.ChildKeyFn == nil {
+		return fmt.Errorf("%w: %s waits for a child but has no ChildKeyFn", reconciler.ErrInvalidConfiguration,
+			r.Describe().Name)
+	}
+	if validator, ok := r.Inner.(api.Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// check returns an empty reason if the child is ready.
+func (r *Reconciler[Parent, Child]) check(ctx context.Context, k8sCli client.Client, child Child) (string, string, error) {
+	key := client.ObjectKeyFromObject(child)
+	if err := k8sCli.Get(ctx, key, child); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "ChildNotFound", fmt.Sprintf("Child %s was not found", key), nil
+		}
+		return "", "", err
+	}
+
+	if r.ReadyFn != nil {
+		if r.ReadyFn(child) {
+			return "", "", nil
+		}
+		return "ChildNotReady", fmt.Sprintf("Waiting for %s to be ready", key), nil
+	}
+
+	ready, message, err := reconciler.IsReady(child)
+	if err != nil || ready {
+		return "", "", err
+	}
+	if message == "" {
+		return "ChildNotReady", fmt.Sprintf("Waiting for %s to be ready", key), nil
+	}
+	return "ChildNotReady", fmt.Sprintf("Waiting for %s to be ready: %s", key, message), nil
+}
+
+func (r *Reconciler[Parent, Child]) requeueAfter() time.Duration {
+	if r.RequeueAfter <= 0 {
+		return DefaultRequeueAfter
+	}
+	return r.RequeueAfter
+}
@@ -0,0 +1,42 @@
+package waitfor
+
+import (
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder is a builder for the Reconciler.
+type Builder[Parent client.Object, Child client.Object] struct {
+	reconciler Reconciler[Parent, Child]
+}
+
+// Ready returns a new instance of Builder that runs the inner reconciler and waits for the child returned by keyFn to
+// be ready.
+func Ready[Parent client.Object, Child client.Object](inner api.Reconciler[Parent], keyFn func(Parent) Child) *Builder[Parent, Child] {
+	return &Builder[Parent, Child]{
+		reconciler: Reconciler[Parent, Child]{
+			Inner:        inner,
+			ChildKeyFn:   keyFn,
+			RequeueAfter: DefaultRequeueAfter,
+		},
+	}
+}
+
+// WithReadyFn sets the ReadyFn field.
+func (b *Builder[Parent, Child]) WithReadyFn(readyFn func(child Child) bool) *Builder[Parent, Child] {
+	b.reconciler.ReadyFn = readyFn
+	return b
+}
+
+// WithRequeueAfter sets the RequeueAfter field.
+func (b *Builder[Parent, Child]) WithRequeueAfter(after time.Duration) *Builder[Parent, Child] {
+	b.reconciler.RequeueAfter = after
+	return b
+}
+
+// Build returns the constructed Reconciler.
+func (b *Builder[Parent, Child]) Build() *Reconciler[Parent, Child] {
+	return &b.reconciler
+}
@@ -0,0 +1,101 @@
+package waitfor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type countingReconciler struct {
+	calls  int
+	result reconcile.Result
+}
+
+func (c *countingReconciler) Describe() api.Descriptor {
+	return api.Descriptor{Name: "Deployment"}
+}
+
+func (c *countingReconciler) Reconcile(context.Context, client.Client, *corev1.ConfigMap) (reconcile.Result, error) {
+	c.calls++
+	return c.result, nil
+}
+
+func TestWaitForReady(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status: appsv1.DeploymentStatus{
+			UpdatedReplicas: 1,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, Message: "Deployment does not have minimum availability."},
+			},
+		},
+	}
+
+	inner := &countingReconciler{}
+	waiting := Ready(inner, func(parent *corev1.ConfigMap) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace}}
+	}).Build()
+	require.NoError(t, waiting.Validate())
+
+	state := &conductor.State{}
+	ctx, err := conductor.BindState(context.Background(), state)
+	require.NoError(t, err)
+
+	// The requeue of the inner reconciler is returned as is
+	inner.result = reconcile.Result{Requeue: true}
+	k8sCli := fake.NewClientBuilder().WithScheme(s).Build()
+	result, err := waiting.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+	inner.result = reconcile.Result{}
+
+	// The child does not exist
+	result, err = waiting.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRequeueAfter, result.RequeueAfter)
+	condition := state.FindCondition("DeploymentProgressing")
+	require.NotNil(t, condition)
+	assert.Equal(t, "ChildNotFound", condition.Reason)
+
+	// The child is not available
+	k8sCli = fake.NewClientBuilder().WithScheme(s).WithObjects(deployment).Build()
+	result, err = waiting.Reconcile(ctx, k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRequeueAfter, result.RequeueAfter)
+	condition = state.FindCondition("DeploymentProgressing")
+	require.NotNil(t, condition)
+	assert.Equal(t, "ChildNotReady", condition.Reason)
+	assert.Contains(t, condition.Message, "minimum availability")
+
+	// The child is available
+	deployment.Status.Conditions[0].Status = corev1.ConditionTrue
+	k8sCli = fake.NewClientBuilder().WithScheme(s).WithObjects(deployment).Build()
+	result, err = waiting.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, 4, inner.calls)
+
+	// A custom ReadyFn takes precedence
+	waiting = Ready(inner, waiting.ChildKeyFn).
+		WithReadyFn(func(child *appsv1.Deployment) bool { return child.Status.ReadyReplicas > 0 }).
+		Build()
+	result, err = waiting.Reconcile(context.Background(), k8sCli, parent)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRequeueAfter, result.RequeueAfter)
+}
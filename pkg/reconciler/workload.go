@@ -0,0 +1,123 @@
+package reconciler
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestartedAtAnnotation is the pod template annotation used by `kubectl rollout restart`.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RestartTriggerAnnotation records on a workload the revision of what last triggered its restart, see
+// RestartOnChange. It is set on the workload rather than on its pod template, so recording it doesn't roll the pods.
+const RestartTriggerAnnotation = AnnotationPrefix + "restart-trigger"
+
+// PodTemplate returns the pod template of a workload (Deployment, StatefulSet, DaemonSet or ReplicaSet).
+// It returns nil for objects without a pod template, including unstructured ones: see HasPodTemplate and
+// PodTemplateAnnotations, which support them.
+func PodTemplate(obj client.Object) *corev1.PodTemplateSpec {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Template
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Template
+	case *appsv1.DaemonSet:
+		return &workload.Spec.Template
+	case *appsv1.ReplicaSet:
+		return &workload.Spec.Template
+	default:
+		return nil
+	}
+}
+
+// HasPodTemplate returns true if the object is a typed workload with a pod template (see PodTemplate), or an
+// unstructured object with a spec.template field, such as a workload of a custom resource.
+func HasPodTemplate(obj client.Object) bool {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		_, found, err := unstructured.NestedMap(u.Object, "spec", "template")
+		return found && err == nil
+	}
+	return PodTemplate(obj) != nil
+}
+
+// PodTemplateAnnotations returns the annotations of the pod template of a workload, typed or unstructured.
+// It returns nil if the object has no pod template or its pod template has no annotations.
+func PodTemplateAnnotations(obj client.Object) map[string]string {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		annotations, _, _ := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "annotations")
+		return annotations
+	}
+	if template := PodTemplate(obj); template != nil {
+		return template.Annotations
+	}
+	return nil
+}
+
+// SetPodTemplateAnnotation sets an annotation on the pod template of a workload, typed or unstructured.
+// It returns false if the object has no pod template.
+func SetPodTemplateAnnotation(obj client.Object, key, value string) bool {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if !HasPodTemplate(u) {
+			return false
+		}
+		return unstructured.SetNestedField(u.Object, value, "spec", "template", "metadata", "annotations", key) == nil
+	}
+	template := PodTemplate(obj)
+	if template == nil {
+		return false
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[key] = value
+	return true
+}
+
+// CarryRestartedAt copies the restartedAt annotation from the current pod template onto the desired one, unless the
+// desired object already sets it. This keeps restarts (including `kubectl rollout restart`) from being reverted.
+func CarryRestartedAt(current, desired client.Object) {
+	restartedAt, ok := PodTemplateAnnotations(current)[RestartedAtAnnotation]
+	if !ok {
+		return
+	}
+	if _, ok := PodTemplateAnnotations(desired)[RestartedAtAnnotation]; ok {
+		return
+	}
+	SetPodTemplateAnnotation(desired, RestartedAtAnnotation, restartedAt)
+}
+
+// Restart bumps the restartedAt annotation on the pod template of a workload, triggering a rollout.
+func Restart(obj client.Object, at time.Time) bool {
+	return SetPodTemplateAnnotation(obj, RestartedAtAnnotation, at.Format(time.RFC3339))
+}
+
+// RestartOnChange restarts the desired workload when the revision of what triggers its restarts (e.g. the
+// resourceVersion of a referenced Secret) differs from the one recorded on the current workload, and records the
+// revision on the desired one. The first revision recorded doesn't restart the workload, and an empty revision keeps
+// the recorded one. It returns true if the workload is restarted.
+func RestartOnChange(current, desired client.Object, revision string, at time.Time) bool {
+	if !HasPodTemplate(desired) {
+		return false
+	}
+	recorded, ok := current.GetAnnotations()[RestartTriggerAnnotation]
+	if revision == "" {
+		if !ok {
+			return false
+		}
+		revision = recorded
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[RestartTriggerAnnotation] = revision
+	desired.SetAnnotations(annotations)
+	if !ok || recorded == revision {
+		return false
+	}
+	return Restart(desired, at)
+}
@@ -0,0 +1,97 @@
+package simulation
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CountingClient wraps a client.Client and counts the API calls made through it, by verb.
+type CountingClient struct {
+	client.Client
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+var _ client.Client = &CountingClient{}
+
+// NewCountingClient returns a CountingClient wrapping the client.
+func NewCountingClient(c client.Client) *CountingClient {
+	return &CountingClient{
+		Client: c,
+		calls:  map[string]int{},
+	}
+}
+
+// Calls returns the number of calls made per verb.
+func (c *CountingClient) Calls() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := make(map[string]int, len(c.calls))
+	for verb, count := range c.calls {
+		calls[verb] = count
+	}
+	return calls
+}
+
+func (c *CountingClient) count(verb string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[verb]++
+}
+
+func (c *CountingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.count("get")
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *CountingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.count("list")
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *CountingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.count("create")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *CountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.count("update")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *CountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.count("patch")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *CountingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.count("delete")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *CountingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.count("deletecollection")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *CountingClient) Status() client.SubResourceWriter {
+	return &countingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type countingStatusWriter struct {
+	client.SubResourceWriter
+	client *CountingClient
+}
+
+func (s *countingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	s.client.count("update/status")
+	return s.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (s *countingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	s.client.count("patch/status")
+	return s.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,153 @@
+// Package simulation drives conductors with large numbers of synthetic parents against a fake or envtest client,
+// measuring throughput, API calls and memory for capacity planning without a real cluster.
+package simulation
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ethan-gallant/maestro/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultWorkers   = 4
+	DefaultMaxPasses = 10
+)
+
+// Config configures a simulation run.
+type Config[Parent client.Object] struct {
+	// Client is the client the pipelines run against, typically a fake client or an envtest client.
+	Client client.Client // required
+	// Parents is the number of synthetic parents to simulate.
+	Parents int // required
+	// ParentFn returns the synthetic parent with the given index. Parents are created in the client before the run.
+	ParentFn func(i int) Parent // required
+	// ConductorFn builds the pipeline under test for the client. It is called once per worker, so workers don't share
+	// the reconcilers and their state.
+	ConductorFn func(k8sCli client.Client) api.Conductor[Parent] // required
+	// Workers is the number of parents conducted concurrently, like MaxConcurrentReconciles. Defaults to DefaultWorkers.
+	Workers int // optional
+	// MaxPasses is the maximum number of Conduct passes per parent until it converges (no requeue).
+	// Defaults to DefaultMaxPasses.
+	MaxPasses int // optional
+}
+
+// Report summarizes a simulation run.
+type Report struct {
+	// Parents is the number of parents simulated.
+	Parents int
+	// Converged is the number of parents whose last pass neither requeued nor failed.
+	Converged int
+	// Errors is the number of Conduct passes that returned an error.
+	Errors int
+	// Passes is the total number of Conduct passes.
+	Passes int
+	// Duration is the wall-clock time of the run, excluding the creation of the parents.
+	Duration time.Duration
+	// Throughput is the number of Conduct passes per second.
+	Throughput float64
+	// APICalls is the number of API calls made by the pipelines, by verb.
+	APICalls map[string]int
+	// TotalAllocBytes is the number of bytes allocated during the run.
+	TotalAllocBytes uint64
+	// HeapInUseBytes is the heap in use at the end of the run, after a garbage collection.
+	HeapInUseBytes uint64
+}
+
+// Run creates the synthetic parents and conducts each of them until it converges or MaxPasses is reached.
+func Run[Parent client.Object](ctx context.Context, config Config[Parent]) (Report, error) {
+	if config.Client == nil || config.ParentFn == nil || config.ConductorFn == nil {
+		return Report{}, errors.New("simulation requires a Client, ParentFn and ConductorFn")
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	maxPasses := config.MaxPasses
+	if maxPasses <= 0 {
+		maxPasses = DefaultMaxPasses
+	}
+
+	parents := make([]Parent, config.Parents)
+	for i := range parents {
+		parents[i] = config.ParentFn(i)
+		if err := config.Client.Create(ctx, parents[i]); err != nil {
+			return Report{}, err
+		}
+	}
+
+	counting := NewCountingClient(config.Client)
+	report := Report{Parents: config.Parents}
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	queue := make(chan Parent)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conductor := config.ConductorFn(counting)
+			for parent := range queue {
+				passes, errs, converged := conduct(ctx, conductor, parent, maxPasses)
+				mu.Lock()
+				report.Passes += passes
+				report.Errors += errs
+				if converged {
+					report.Converged++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, parent := range parents {
+		select {
+		case queue <- parent:
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	if report.Duration > 0 {
+		report.Throughput = float64(report.Passes) / report.Duration.Seconds()
+	}
+	report.APICalls = counting.Calls()
+
+	var after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	report.TotalAllocBytes = after.TotalAlloc - before.TotalAlloc
+	report.HeapInUseBytes = after.HeapInuse
+
+	return report, ctx.Err()
+}
+
+// conduct runs passes for the parent until it converges, returning the passes, the failed passes and whether
+// the parent converged.
+func conduct[Parent client.Object](ctx context.Context, conductor api.Conductor[Parent], parent Parent, maxPasses int) (int, int, bool) {
+	errs := 0
+	for pass := 1; pass <= maxPasses; pass++ {
+		if ctx.Err() != nil {
+			return pass - 1, errs, false
+		}
+		result, err := conductor.Conduct(ctx, parent)
+		if err != nil {
+			errs++
+			continue
+		}
+		if !result.Requeue && result.RequeueAfter == 0 {
+			return pass, errs, true
+		}
+	}
+	return maxPasses, errs, false
+}
@@ -0,0 +1,61 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethan-gallant/maestro/api"
+	"github.com/ethan-gallant/maestro/pkg/conductor"
+	"github.com/ethan-gallant/maestro/pkg/reconciler"
+	"github.com/ethan-gallant/maestro/pkg/reconciler/simple"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRun(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+
+	childReconciler := simple.FromReconcileFunc(func(_ context.Context, parent *corev1.ConfigMap) (*corev1.Secret, error) {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: parent.Name, Namespace: parent.Namespace},
+			StringData: map[string]string{"owner": parent.Name},
+		}, nil
+	}).
+		WithDetails(api.Descriptor{Name: "Secret"}).
+		WithDryRunType(reconciler.DryRunNone).
+		Build()
+
+	report, err := Run(context.Background(), Config[*corev1.ConfigMap]{
+		Client:  fake.NewClientBuilder().WithScheme(s).Build(),
+		Parents: 100,
+		ParentFn: func(i int) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("parent-%d", i),
+					Namespace: "default",
+					UID:       types.UID(fmt.Sprintf("uid-%d", i)),
+				},
+			}
+		},
+		ConductorFn: func(k8sCli client.Client) api.Conductor[*corev1.ConfigMap] {
+			c := conductor.ForParent(&corev1.ConfigMap{}).WithClient(k8sCli).Build()
+			c.Register(childReconciler)
+			return c
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 100, report.Converged)
+	assert.Zero(t, report.Errors)
+	assert.Equal(t, 100, report.APICalls["create"])
+	assert.Positive(t, report.Throughput)
+	assert.Positive(t, report.TotalAllocBytes)
+}